@@ -0,0 +1,215 @@
+package apiformat
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/robesmi/MSISDNApp/rpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"gopkg.in/yaml.v2"
+)
+
+// MIMEYAML is the registered YAML media type, accepted next to gin's application/x-yaml
+const MIMEYAML = "application/yaml"
+
+// Offered are the formats API responses can be written in, JSON being the one used when
+// the client accepts anything or sends no Accept header
+var Offered = []string{binding.MIMEJSON, binding.MIMEXML, binding.MIMEXML2, binding.MIMEYAML, MIMEYAML, binding.MIMEPROTOBUF}
+
+// Write writes the data in the first format of the Accept header that's offered, or
+// responds with 406 Not Acceptable when there's none. Routes with side effects should
+// run Negotiate first so such requests are turned away before anything is done.
+// YAML bodies hold the same document as the JSON body and XML bodies are encoded from
+// the struct tags. Protobuf bodies are described by Marshal
+func Write(c *gin.Context, code int, data interface{}){
+
+	format := c.NegotiateFormat(Offered...)
+	if format == ""{
+		notAcceptable(c)
+		return
+	}
+	body, err := Marshal(format, data)
+	if err != nil{
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "Internal error",
+		})
+		return
+	}
+	c.Data(code, contentType(format), body)
+}
+
+// Negotiate returns a middleware responding with 406 Not Acceptable, without running the
+// handlers after it, to requests whose Accept header has none of the Offered formats
+func Negotiate() gin.HandlerFunc{
+	return func(c *gin.Context){
+		if c.NegotiateFormat(Offered...) == ""{
+			notAcceptable(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+func notAcceptable(c *gin.Context){
+	c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{
+		"error": "The response can only be sent as application/json, application/xml, application/x-yaml or application/x-protobuf",
+	})
+}
+
+// Marshal encodes the data in the format, one of Offered. In Protobuf, lookups, country
+// details and the numbering plan are the messages of the gRPC API, see rpc.Message, while
+// data without a message of its own, like errors, tokens and jobs, is untyped: a
+// google.protobuf.Value holding the same document as the JSON body
+func Marshal(format string, data interface{}) ([]byte, error){
+
+	switch format {
+	case binding.MIMEXML, binding.MIMEXML2:
+		return xml.Marshal(xmlValue(data))
+	case binding.MIMEYAML, MIMEYAML:
+		doc, err := document(data)
+		if err != nil{
+			return nil, err
+		}
+		return yaml.Marshal(doc)
+	case binding.MIMEPROTOBUF:
+		if message, ok := rpc.Message(data); ok{
+			return proto.Marshal(message)
+		}
+		doc, err := document(data)
+		if err != nil{
+			return nil, err
+		}
+		value, err := structpb.NewValue(doc)
+		if err != nil{
+			return nil, err
+		}
+		return proto.Marshal(value)
+	}
+	return json.Marshal(data)
+}
+
+// ProtoRequest is a request with a message of the gRPC API, which Bind decodes its
+// Protobuf bodies as
+type ProtoRequest interface {
+	// ProtoMessage returns an empty message of the request to decode the body into
+	ProtoMessage() proto.Message
+	// FromProto sets the request from the decoded message
+	FromProto(message proto.Message)
+}
+
+// Bind decodes the body into obj by its Content-Type, which can be any of the Offered
+// formats or a form, and validates it like gin's binding does. Protobuf bodies are the
+// message of a ProtoRequest, or else a google.protobuf.Value holding the JSON document
+func Bind(c *gin.Context, obj interface{}) error{
+
+	switch c.ContentType() {
+	case binding.MIMEPROTOBUF:
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil{
+			return err
+		}
+		if request, ok := obj.(ProtoRequest); ok{
+			message := request.ProtoMessage()
+			if err := proto.Unmarshal(body, message); err != nil{
+				return err
+			}
+			request.FromProto(message)
+			return binding.Validator.ValidateStruct(obj)
+		}
+		var value structpb.Value
+		if err := proto.Unmarshal(body, &value); err != nil{
+			return err
+		}
+		doc, err := json.Marshal(value.AsInterface())
+		if err != nil{
+			return err
+		}
+		if err := json.Unmarshal(doc, obj); err != nil{
+			return err
+		}
+		return binding.Validator.ValidateStruct(obj)
+	case binding.MIMEYAML, MIMEYAML:
+		return c.ShouldBindWith(obj, binding.YAML)
+	}
+	return c.ShouldBind(obj)
+}
+
+func contentType(format string) string{
+
+	if format == binding.MIMEPROTOBUF{
+		return format
+	}
+	return format + "; charset=utf-8"
+}
+
+// document returns the data the way it's written as JSON, so every format has the same field names
+func document(data interface{}) (interface{}, error){
+
+	encoded, err := json.Marshal(data)
+	if err != nil{
+		return nil, err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(encoded, &doc); err != nil{
+		return nil, err
+	}
+	return doc, nil
+}
+
+// xmlList is the root element of lists, which have none of their own
+type xmlList struct {
+	XMLName xml.Name `xml:"response"`
+	Items interface{} `xml:"item"`
+}
+
+// xmlMap writes a map as an element per key, sorted so the output doesn't change between calls
+type xmlMap map[string]interface{}
+
+func (m xmlMap) MarshalXML(e *xml.Encoder, start xml.StartElement) error{
+
+	start = xml.StartElement{Name: xml.Name{Local: "response"}}
+	if err := e.EncodeToken(start); err != nil{
+		return err
+	}
+	keys := make([]string, 0, len(m))
+	for key := range m{
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys{
+		if err := e.EncodeElement(m[key], xml.StartElement{Name: xml.Name{Local: key}}); err != nil{
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// xmlValue returns the data in a form encoding/xml can marshal
+func xmlValue(data interface{}) interface{}{
+
+	switch m := data.(type) {
+	case gin.H:
+		return xmlMap(m)
+	case map[string]string:
+		converted := xmlMap{}
+		for key, value := range m{
+			converted[key] = value
+		}
+		return converted
+	}
+	v := reflect.ValueOf(data)
+	if v.Kind() == reflect.Ptr{
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Slice{
+		return xmlList{Items: v.Interface()}
+	}
+	return data
+}
@@ -0,0 +1,298 @@
+package apiformat
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/robesmi/MSISDNApp/model/dto"
+	"github.com/robesmi/MSISDNApp/rpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"gopkg.in/yaml.v2"
+)
+
+type testResponse struct {
+	Number string	`json:"number" xml:"number"`
+	Steps []string	`json:"normalization_steps" xml:"normalization_step,omitempty"`
+}
+
+type testRequest struct {
+	Number string	`json:"number" xml:"number" yaml:"number" binding:"required"`
+	AsOf *time.Time	`json:"as_of" xml:"as_of" yaml:"as_of"`
+}
+
+func serve(handler gin.HandlerFunc, req *http.Request) *httptest.ResponseRecorder{
+
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	_, router := gin.CreateTestContext(recorder)
+	router.POST("/", handler)
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestWrite(t *testing.T) {
+
+	response := testResponse{Number: "38977123456", Steps: []string{"removed the plus sign"}}
+	tt := []struct{
+		Name string
+		Accept string
+		ExpectedCode int
+		ExpectedType string
+	}{
+		{
+			Name:			"No Accept header",
+			ExpectedCode:	http.StatusOK,
+			ExpectedType:	"application/json; charset=utf-8",
+		},
+		{
+			Name:			"Anything",
+			Accept:			"*/*",
+			ExpectedCode:	http.StatusOK,
+			ExpectedType:	"application/json; charset=utf-8",
+		},
+		{
+			Name:			"XML",
+			Accept:			"application/xml",
+			ExpectedCode:	http.StatusOK,
+			ExpectedType:	"application/xml; charset=utf-8",
+		},
+		{
+			Name:			"First offered format of the header",
+			Accept:			"text/html, text/xml;q=0.9, */*;q=0.8",
+			ExpectedCode:	http.StatusOK,
+			ExpectedType:	"text/xml; charset=utf-8",
+		},
+		{
+			Name:			"YAML",
+			Accept:			"application/yaml",
+			ExpectedCode:	http.StatusOK,
+			ExpectedType:	"application/yaml; charset=utf-8",
+		},
+		{
+			Name:			"Protobuf",
+			Accept:			"application/x-protobuf",
+			ExpectedCode:	http.StatusOK,
+			ExpectedType:	"application/x-protobuf",
+		},
+		{
+			Name:			"Nothing offered",
+			Accept:			"text/html",
+			ExpectedCode:	http.StatusNotAcceptable,
+			ExpectedType:	"application/json; charset=utf-8",
+		},
+	}
+
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Arrange
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if test.Accept != ""{
+				req.Header.Set("Accept", test.Accept)
+			}
+
+			//Act
+			recorder := serve(func(c *gin.Context){ Write(c, http.StatusOK, response) }, req)
+
+			//Assert
+			if recorder.Code != test.ExpectedCode || recorder.Header().Get("Content-Type") != test.ExpectedType{
+				t.Fatalf("Error in TestWrite:\n expected %d %s\n got %d %s", test.ExpectedCode, test.ExpectedType, recorder.Code, recorder.Header().Get("Content-Type"))
+			}
+			if test.ExpectedCode != http.StatusOK{
+				return
+			}
+			var decoded testResponse
+			var err error
+			switch {
+			case strings.Contains(test.ExpectedType, "json"):
+				err = json.Unmarshal(recorder.Body.Bytes(), &decoded)
+			case strings.Contains(test.ExpectedType, "xml"):
+				err = xml.Unmarshal(recorder.Body.Bytes(), &decoded)
+			case strings.Contains(test.ExpectedType, "yaml"):
+				var doc map[string]interface{}
+				err = yaml.Unmarshal(recorder.Body.Bytes(), &doc)
+				decoded.Number, _ = doc["number"].(string)
+				decoded.Steps = response.Steps
+			default:
+				var value structpb.Value
+				err = proto.Unmarshal(recorder.Body.Bytes(), &value)
+				decoded.Number = value.GetStructValue().GetFields()["number"].GetStringValue()
+				decoded.Steps = response.Steps
+			}
+			if err != nil || !reflect.DeepEqual(decoded, response){
+				t.Errorf("Error in TestWrite:\n expected %v\n got %v %v", response, decoded, err)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
+
+func TestWriteProtobufMessages(t *testing.T) {
+
+	//Arrange
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Accept", "application/x-protobuf")
+	response := &dto.NumberLookupResponse{
+		MNO: "A1",
+		CC: "389",
+		CI: "mk",
+		NetworkCodes: []dto.NetworkCode{{MCC: "294", MNC: "03"}},
+		Formats: dto.NumberFormats{E164: "+38977123456"},
+		Country: &dto.CountryDetails{Name: "North Macedonia", Alpha2: "MK"},
+	}
+
+	//Act
+	recorder := serve(func(c *gin.Context){ Write(c, http.StatusOK, response) }, req)
+
+	//Assert
+	var decoded rpc.LookupResponse
+	err := proto.Unmarshal(recorder.Body.Bytes(), &decoded)
+	if err != nil || decoded.Msisdn != "38977123456" || decoded.Mno != "A1" || len(decoded.NetworkCodes) != 1 || decoded.Country.GetName() != "North Macedonia"{
+		t.Errorf("Error in TestWriteProtobufMessages:\n expected %s\n got %v %v", "the lookup of 38977123456 by A1", &decoded, err)
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+
+	tt := []struct{
+		Name string
+		Accept string
+		ExpectedCode int
+		ExpectedRun bool
+	}{
+		{
+			Name:			"Offered format",
+			Accept:			"application/yaml",
+			ExpectedCode:	http.StatusOK,
+			ExpectedRun:	true,
+		},
+		{
+			Name:			"Nothing offered",
+			Accept:			"text/html",
+			ExpectedCode:	http.StatusNotAcceptable,
+		},
+	}
+
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Arrange
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.Header.Set("Accept", test.Accept)
+			gin.SetMode(gin.TestMode)
+			recorder := httptest.NewRecorder()
+			_, router := gin.CreateTestContext(recorder)
+			run := false
+			router.POST("/", Negotiate(), func(c *gin.Context){
+				run = true
+				c.Status(http.StatusOK)
+			})
+
+			//Act
+			router.ServeHTTP(recorder, req)
+
+			//Assert
+			if recorder.Code != test.ExpectedCode || run != test.ExpectedRun{
+				t.Errorf("Error in TestNegotiate:\n expected %d %t\n got %d %t", test.ExpectedCode, test.ExpectedRun, recorder.Code, run)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
+
+func TestWriteXMLLists(t *testing.T) {
+
+	//Arrange
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Accept", "application/xml")
+
+	//Act
+	recorder := serve(func(c *gin.Context){ Write(c, http.StatusOK, &[]testResponse{{Number: "1"}, {Number: "2"}}) }, req)
+
+	//Assert
+	expected := "<response><item><number>1</number></item><item><number>2</number></item></response>"
+	if recorder.Body.String() != expected{
+		t.Errorf("Error in TestWriteXMLLists:\n expected %s\n got %s", expected, recorder.Body.String())
+	}
+}
+
+func TestBind(t *testing.T) {
+
+	asOf := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	value, _ := structpb.NewValue(map[string]interface{}{"number": "38977123456", "as_of": "2023-06-01T12:00:00Z"})
+	protobuf, _ := proto.Marshal(value)
+	tt := []struct{
+		Name string
+		ContentType string
+		Body []byte
+	}{
+		{
+			Name:			"JSON",
+			ContentType:	"application/json",
+			Body:			[]byte(`{"number": "38977123456", "as_of": "2023-06-01T12:00:00Z"}`),
+		},
+		{
+			Name:			"XML",
+			ContentType:	"application/xml",
+			Body:			[]byte(`<request><number>38977123456</number><as_of>2023-06-01T12:00:00Z</as_of></request>`),
+		},
+		{
+			Name:			"YAML",
+			ContentType:	"application/yaml",
+			Body:			[]byte("number: \"38977123456\"\nas_of: 2023-06-01T12:00:00Z\n"),
+		},
+		{
+			Name:			"Protobuf",
+			ContentType:	"application/x-protobuf",
+			Body:			protobuf,
+		},
+	}
+
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Arrange
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(test.Body))
+			req.Header.Set("Content-Type", test.ContentType)
+			var bound testRequest
+			var bindErr error
+
+			//Act
+			serve(func(c *gin.Context){ bindErr = Bind(c, &bound) }, req)
+
+			//Assert
+			if bindErr != nil || bound.Number != "38977123456" || bound.AsOf == nil || !bound.AsOf.Equal(asOf){
+				t.Errorf("Error in TestBind:\n expected 38977123456 as of %s\n got %v %v", asOf, bound, bindErr)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
+
+func TestBindValidates(t *testing.T) {
+
+	//Arrange
+	value, _ := structpb.NewValue(map[string]interface{}{"region": "mk"})
+	protobuf, _ := proto.Marshal(value)
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(protobuf))
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	var bound testRequest
+	var bindErr error
+
+	//Act
+	serve(func(c *gin.Context){ bindErr = Bind(c, &bound) }, req)
+
+	//Assert
+	if bindErr == nil{
+		t.Errorf("Error in TestBindValidates:\n expected the missing number to be rejected\n got %v", bound)
+	}
+}
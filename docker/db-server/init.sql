@@ -3,55 +3,152 @@ CREATE DATABASE `msisdn`;
 USE `msisdn`;
 DROP TABLE IF EXISTS `countries`;
 CREATE TABLE `countries` (
+    `id` int NOT NULL AUTO_INCREMENT,
     `country_number_format` varchar(20) NOT NULL,
+    `excluded_format` varchar(60) NOT NULL DEFAULT '',
     `country_code` varchar(6) NOT NULL,
     `country_identifier` varchar(3) NOT NULL,
     `country_code_length` int NOT NULL,
-    PRIMARY KEY (`country_number_format`)
+    `trunk_prefix` varchar(4) NOT NULL DEFAULT '',
+    `international_prefix` varchar(20) NOT NULL DEFAULT '00',
+    `nsn_min_length` int NOT NULL DEFAULT 0,
+    `nsn_max_length` int NOT NULL DEFAULT 0,
+    `number_grouping` varchar(100) NOT NULL DEFAULT '',
+    `priority` int NOT NULL DEFAULT 0,
+    `effective_from` datetime NULL,
+    `effective_to` datetime NULL,
+    PRIMARY KEY (`id`),
+    KEY (`country_number_format`)
 );
-INSERT INTO `countries` VALUES
-    ("^389[0-9]{8}$",389,"mk",3),
-    ("^350[0-9]{5}$",350,"gi",3),
-    ("^242[0-9]{9}$",242,"cg",3),
-    ("^423[0-9]{8}$",423,"li",3),
-    ("^48[0-9]{9}$",48,"pl",2),
-    ("^971[0-9]{10}$",971,"ae",3),
-    ("^850[0-9]{10}$",850,"kp",3),
-    ("^43[0-9]{6,13}$",43,"at",2),
-    ("^351[0-9]{9}$",351,"pt",3),
-    ("^1246[0-9]{10}$",1246,"bb",3),
-    ("^212[0-9]{9}$",212,"ma",3);
+INSERT INTO `countries` (`country_number_format`, `country_code`, `country_identifier`, `country_code_length`, `trunk_prefix`, `international_prefix`, `nsn_min_length`, `nsn_max_length`, `number_grouping`, `priority`) VALUES
+    ("^389[0-9]{8}$",389,"mk",3,"0","00",8,8,"2:X XXX XXXX;XX XXX XXX",0),
+    ("^350[0-9]{5}$",350,"gi",3,"","00",5,5,"XXXXX",0),
+    ("^242[0-9]{9}$",242,"cg",3,"","00",9,9,"XX XXX XXXX",0),
+    ("^423[0-9]{8}$",423,"li",3,"","00",8,8,"XXX XXX XX",0),
+    ("^48[0-9]{9}$",48,"pl",2,"","00",9,9,"XXX XXX XXX",0),
+    ("^971[0-9]{10}$",971,"ae",3,"0","00",10,10,"XX XXX XXXXX",0),
+    ("^850[0-9]{10}$",850,"kp",3,"0","00",10,10,"XXX XXX XXXX",0),
+    ("^43[0-9]{6,13}$",43,"at",2,"0","00",6,13,"XXX XXX;XXX XXXX;XXX XXXXX;XXX XXXXXX;XXX XXXXXXX",0),
+    ("^351[0-9]{9}$",351,"pt",3,"","00",9,9,"XXX XXX XXX",0),
+    ("^1[2-9][0-9]{2}[2-9][0-9]{6}$",1,"us",1,"1","011",10,10,"XXX XXX XXXX",0),
+    ("^1246[0-9]{7}$",1,"bb",1,"1","011",10,10,"XXX XXX XXXX",1),
+    ("^212[0-9]{9}$",212,"ma",3,"0","00",9,9,"XXX-XXXXXX",0);
+
+DROP TABLE IF EXISTS `country_details`;
+CREATE TABLE `country_details` (
+    `country_identifier` varchar(3) NOT NULL,
+    `name` varchar(100) NOT NULL,
+    `native_name` varchar(100) NOT NULL DEFAULT '',
+    `iso_alpha3` varchar(3) NOT NULL,
+    `iso_numeric` varchar(3) NOT NULL,
+    `time_zones` varchar(200) NOT NULL DEFAULT '',
+    `currency` varchar(3) NOT NULL DEFAULT '',
+    `region` varchar(50) NOT NULL DEFAULT '',
+    `continent` varchar(20) NOT NULL DEFAULT '',
+    PRIMARY KEY (`country_identifier`)
+);
+INSERT INTO `country_details` (`country_identifier`, `name`, `native_name`, `iso_alpha3`, `iso_numeric`, `time_zones`, `currency`, `region`, `continent`) VALUES
+    ("mk","North Macedonia","Северна Македонија","MKD","807","Europe/Skopje","MKD","Southern Europe","Europe"),
+    ("gi","Gibraltar","Gibraltar","GIB","292","Europe/Gibraltar","GIP","Southern Europe","Europe"),
+    ("cg","Congo","République du Congo","COG","178","Africa/Brazzaville","XAF","Middle Africa","Africa"),
+    ("li","Liechtenstein","Liechtenstein","LIE","438","Europe/Vaduz","CHF","Western Europe","Europe"),
+    ("pl","Poland","Polska","POL","616","Europe/Warsaw","PLN","Eastern Europe","Europe"),
+    ("ae","United Arab Emirates","الإمارات العربية المتحدة","ARE","784","Asia/Dubai","AED","Western Asia","Asia"),
+    ("kp","North Korea","조선민주주의인민공화국","PRK","408","Asia/Pyongyang","KPW","Eastern Asia","Asia"),
+    ("at","Austria","Österreich","AUT","040","Europe/Vienna","EUR","Western Europe","Europe"),
+    ("pt","Portugal","Portugal","PRT","620","Europe/Lisbon,Atlantic/Madeira,Atlantic/Azores","EUR","Southern Europe","Europe"),
+    ("bb","Barbados","Barbados","BRB","052","America/Barbados","BBD","Caribbean","North America"),
+    ("us","United States","United States","USA","840","America/New_York,America/Chicago,America/Denver,America/Los_Angeles,America/Anchorage,Pacific/Honolulu","USD","Northern America","North America"),
+    ("ma","Morocco","المغرب","MAR","504","Africa/Casablanca","MAD","Northern Africa","Africa");
 
 DROP TABLE IF EXISTS `mobile_operators`;
+DROP TABLE IF EXISTS `network_operators`;
+CREATE TABLE `network_operators` (
+    `id` int NOT NULL AUTO_INCREMENT,
+    `brand_name` varchar(100) NOT NULL,
+    `legal_name` varchar(200) NOT NULL,
+    `status` varchar(20) NOT NULL DEFAULT 'active',
+    `network_codes` varchar(100) NOT NULL DEFAULT '',
+    `host_operator_id` int DEFAULT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY (`brand_name`),
+    FOREIGN KEY (`host_operator_id`) REFERENCES `network_operators` (`id`)
+);
+
+INSERT INTO `network_operators` (`id`, `brand_name`, `legal_name`, `network_codes`) VALUES
+    (1,"Telekom","Makedonski Telekom","294-01"),
+    (2,"A1","A1 Makedonija","294-03"),
+    (3,"Lietuvos","Lietuvos",""),
+    (4,"Orange","Orange Polska S.A.","260-03"),
+    (5,"T-Mobile","T-MOBILE POLSKA S.A.","260-02"),
+    (6,"Plus","Polskie Sieci Cyfrowe Sp. z o.o.","260-01"),
+    (7,"Etisalat","Emirates Telecommunications Group Company PJSC","424-02"),
+    (8,"Du","Emirates Integrated Telecommunications Company PJSC","424-03"),
+    (9,"Koryolink","Cheo Technology JV Company","467-05"),
+    (10,"KangsongNET","KangsongNET",""),
+    (11,"Drei","Hutchison Drei Austria GmbH","232-05,232-10"),
+    (12,"Vodafone","Vodafone Portugal - Comunicações Pessoais, S.A.","268-01"),
+    (13,"MEO","TMN - TELECOMUNICAÇÕES MÓVEIS NACIONAIS, SA","268-06"),
+    (15,"Liberty Latin America","Liberty Latin America","342-600"),
+    (16,"Sunbeach","Sunbeach","342-820"),
+    (17,"Maroc Telecom","Itissalat Al-Maghrib","604-01"),
+    (18,"Orange Maroc","Médi Télécom","604-00");
+
+INSERT INTO `network_operators` (`id`, `brand_name`, `legal_name`, `host_operator_id`) VALUES
+    (14,"CTT","CTT CORREIOS DE PORTUGAL, S.A.",13);
+
 CREATE TABLE `mobile_operators` (
+    `id` int NOT NULL AUTO_INCREMENT,
     `country_identifier` varchar(3) NOT NULL,
     `prefix_format` varchar(60) NOT NULL,
-    `mno`           varchar(100) NOT NULL,
+    `excluded_format` varchar(60) NOT NULL DEFAULT '',
+    `operator_id` int NOT NULL,
     `prefix_length` int NOT NULL,
-    PRIMARY KEY (`country_identifier`, `prefix_format`)
+    `number_type` varchar(20) NOT NULL DEFAULT 'mobile',
+    `priority` int NOT NULL DEFAULT 0,
+    `effective_from` datetime NULL,
+    `effective_to` datetime NULL,
+    PRIMARY KEY (`id`),
+    KEY (`country_identifier`, `prefix_format`),
+    FOREIGN KEY (`operator_id`) REFERENCES `network_operators` (`id`)
 );
 
-INSERT INTO `mobile_operators` VALUES
-    ("mk","^77[0-9]{6}$","A1",2),
-    ("mk","^71[0-9]{6}$", "Telekom",2),
-    ("li","^6[0-9]{7}$", "Lietuvos",1),
-    ("pl","^510[0-9]{6}$", "Mobile telephoOrange",2),
-    ("pl","(?!^5329[0-9]{5}$)(?!^5366[0-9]{5}$)^53[0-7][0-9]{6}$", "Orange Polska S.A",2),
-    ("pl","^53(2|8|9)[0-9]{6}$", "T-MOBILE POLSKA S.A.",2),
-    ("pl","^5366[0-9]{6}$", "Polskie Sieci Cyfrowe Sp. z o.o.",2),
-    ("ae","^(50|56)[0-9]{7}$", "Etisalat",2),
-    ("ae","^(52|55)[0-9]{7}$", "Du",2),
-    ("kp","^(191|192)[0-9]{7}$", "Koryolink",3),
-    ("kp","^195[0-9]{7}$", "KangsongNET",3),
-    ("at","^(660|699)[0-9]{3,10}$", "Hutchinson Drei",3),
-    ("pt","^91[0-9]{7}$", "Vodafone Portugal",2),
-    ("pt","^921[0-9]{6}$", "Vodafone Portugal",3),
-    ("pt","^922[0-2][0-9]{5}$", "CTT CORREIOS DE PORTUGAL, S.A.",3),
-    ("pt","^924[0-4][0-9]{5}$", "TMN - TELECOMUNICAÇÕES MÓVEIS NACIONAIS, SA",3),
-    ("bb","^(23\d|24\d|25[0-4])[0-9]{7}$", "Liberty Latin America",3),
-    ("bb","^(45[0-9])[0-9]{7}$", "Sunbeach",3),
-    ("ma","^(?!^61(2|4|7|9)[0-9]{5}$)611[0-9]{6}$", "Maroc Telecom",3),
-    ("ma","^61(2|4|7|9)[0-9]{6}$", "Orange Maroc",3);
+INSERT INTO `mobile_operators` (`country_identifier`, `prefix_format`, `operator_id`, `prefix_length`) VALUES
+    ("mk","^77[0-9]{6}$",2,2),
+    ("mk","^71[0-9]{6}$",1,2),
+    ("li","^6[0-9]{7}$",3,1),
+    ("pl","^510[0-9]{6}$",4,2),
+    ("pl","^53(2|8|9)[0-9]{6}$",5,2),
+    ("pl","^5366[0-9]{6}$",6,2),
+    ("ae","^(50|56)[0-9]{7}$",7,2),
+    ("ae","^(52|55)[0-9]{7}$",8,2),
+    ("kp","^(191|192)[0-9]{7}$",9,3),
+    ("kp","^195[0-9]{7}$",10,3),
+    ("at","^(660|699)[0-9]{3,10}$",11,3),
+    ("pt","^91[0-9]{7}$",12,2),
+    ("pt","^921[0-9]{6}$",12,3),
+    ("pt","^922[0-2][0-9]{5}$",14,3),
+    ("pt","^924[0-4][0-9]{5}$",13,3),
+    ("bb","^246(23[0-9]|24[0-9]|25[0-4])[0-9]{4}$",15,6),
+    ("bb","^246(45[0-9])[0-9]{4}$",16,6),
+    ("ma","^611[0-9]{6}$",17,3),
+    ("ma","^61(2|4|7|9)[0-9]{6}$",18,3);
+
+INSERT INTO `mobile_operators` (`country_identifier`, `prefix_format`, `operator_id`, `prefix_length`, `number_type`, `priority`) VALUES
+    ("mk","^2[0-9]{7}$",1,1,"fixed_line",0),
+    ("mk","^800[0-9]{5}$",1,3,"toll_free",0),
+    ("pt","^760[0-9]{6}$",13,3,"premium_rate",0),
+    ("pt","^808[0-9]{6}$",13,3,"shared_cost",0);
+
+INSERT INTO `mobile_operators` (`country_identifier`, `prefix_format`, `excluded_format`, `operator_id`, `prefix_length`) VALUES
+    ("pl","^53[0-7][0-9]{6}$", "^5329[0-9]{5}$|^5366[0-9]{5}$",4,2);
+
+DROP TABLE IF EXISTS `ported_numbers`;
+CREATE TABLE `ported_numbers` (
+    `msisdn` varchar(15) NOT NULL,
+    `mno` varchar(100) NOT NULL,
+    PRIMARY KEY (`msisdn`)
+);
 
 DROP TABLE IF EXISTS `users`;
 CREATE TABLE `users` (
@@ -61,4 +158,20 @@ CREATE TABLE `users` (
 	`role` varchar(10) NOT NULL,
 	`refresh_token` varchar(512),
     PRIMARY KEY (`id`)
-);
\ No newline at end of file
+);
+
+DROP TABLE IF EXISTS `lookup_jobs`;
+CREATE TABLE `lookup_jobs` (
+    `id` varchar(36) NOT NULL,
+    `owner` varchar(36) NOT NULL,
+    `file_name` varchar(255) NOT NULL,
+    `status` varchar(10) NOT NULL,
+    `total` int NOT NULL,
+    `processed` int NOT NULL,
+    `failed` int NOT NULL,
+    `error` varchar(512) NOT NULL,
+    `created_at` datetime NOT NULL,
+    `updated_at` datetime NOT NULL,
+    PRIMARY KEY (`id`),
+    KEY (`owner`)
+);
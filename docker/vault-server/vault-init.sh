@@ -26,6 +26,8 @@ create_token () {
 
 set_app_secrets(){
    vault kv put secret/appvars PORT=$PORT \
+   DB_DRIVER=$DB_DRIVER \
+   DB_SOURCE=$DB_SOURCE \
    MYSQL_DRIVER=$MYSQL_DRIVER \
    MYSQL_SOURCE=$MYSQL_SOURCE \
    Secret=$Secret \
@@ -0,0 +1,181 @@
+// Package lookupcache keeps recent number lookup results in memory
+package lookupcache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/robesmi/MSISDNApp/model/dto"
+)
+
+// Cache is a size-bounded cache of lookup results by number. Found numbers are kept for
+// the TTL and numbers that weren't found for the shorter negative TTL, and once the
+// cache is full the least recently used result makes room for the new one
+type Cache struct {
+	mu sync.Mutex
+	size int
+	ttl time.Duration
+	negativeTTL time.Duration
+	entries map[string]*list.Element
+	// recent holds the entries, most recently used first
+	recent *list.List
+	stats Stats
+	// now returns the current time, replaced in tests
+	now func() time.Time
+}
+
+// Stats are the counters of a cache since it was created
+type Stats struct {
+	Entries int
+	Hits uint64
+	Misses uint64
+	// NegativeHits are the hits on numbers that weren't found, included in Hits
+	NegativeHits uint64
+	Evictions uint64
+	Invalidations uint64
+}
+
+// HitRatio returns the share of lookups answered from the cache
+func (s Stats) HitRatio() float64{
+
+	if s.Hits + s.Misses == 0{
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits + s.Misses)
+}
+
+type entry struct {
+	number string
+	response *dto.NumberLookupResponse
+	err error
+	expires time.Time
+}
+
+// New returns a cache of up to size results. A size of 0 returns a nil cache, which
+// never holds anything
+func New(size int, ttl time.Duration, negativeTTL time.Duration) *Cache{
+
+	if size <= 0{
+		return nil
+	}
+	return &Cache{
+		size: size,
+		ttl: ttl,
+		negativeTTL: negativeTTL,
+		entries: make(map[string]*list.Element, size),
+		recent: list.New(),
+		now: time.Now,
+	}
+}
+
+// Get returns the cached result of a number: a copy of the response, or the error
+// it wasn't found with. The boolean reports whether the number was in the cache
+func (c *Cache) Get(number string) (*dto.NumberLookupResponse, bool, error){
+
+	if c == nil{
+		return nil, false, nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[number]
+	if !ok{
+		c.stats.Misses++
+		return nil, false, nil
+	}
+	cached := element.Value.(*entry)
+	if !c.now().Before(cached.expires){
+		c.remove(element)
+		c.stats.Misses++
+		return nil, false, nil
+	}
+	c.recent.MoveToFront(element)
+	c.stats.Hits++
+	if cached.err != nil{
+		c.stats.NegativeHits++
+		return nil, true, cached.err
+	}
+	response := *cached.response
+	return &response, true, nil
+}
+
+// Generation identifies the numbering plan the cached results were looked up with,
+// and changes whenever the cache is invalidated
+func (c *Cache) Generation() uint64{
+
+	if c == nil{
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats.Invalidations
+}
+
+// Put caches the response of a number, or the error of a number that wasn't found,
+// looked up in the generation. Results of an earlier generation are dropped, so that
+// a lookup that overlapped a change of the plan doesn't outlive it
+func (c *Cache) Put(number string, generation uint64, response *dto.NumberLookupResponse, err error){
+
+	if c == nil{
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.stats.Invalidations{
+		return
+	}
+
+	cached := &entry{number: number, err: err, expires: c.now().Add(c.ttl)}
+	if err != nil{
+		cached.expires = c.now().Add(c.negativeTTL)
+	}else{
+		copied := *response
+		cached.response = &copied
+	}
+	if element, ok := c.entries[number]; ok{
+		element.Value = cached
+		c.recent.MoveToFront(element)
+		return
+	}
+	if c.recent.Len() >= c.size{
+		c.remove(c.recent.Back())
+		c.stats.Evictions++
+	}
+	c.entries[number] = c.recent.PushFront(cached)
+}
+
+// Invalidate drops every cached result, for when the numbering plan changes
+func (c *Cache) Invalidate(){
+
+	if c == nil{
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element, c.size)
+	c.recent.Init()
+	c.stats.Invalidations++
+}
+
+// Stats returns the counters of the cache
+func (c *Cache) Stats() Stats{
+
+	if c == nil{
+		return Stats{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = c.recent.Len()
+	return stats
+}
+
+func (c *Cache) remove(element *list.Element){
+
+	c.recent.Remove(element)
+	delete(c.entries, element.Value.(*entry).number)
+}
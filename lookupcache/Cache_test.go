@@ -0,0 +1,125 @@
+package lookupcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robesmi/MSISDNApp/model/dto"
+	"github.com/robesmi/MSISDNApp/model/errs"
+)
+
+// newTestCache returns a cache with a clock the test moves forward
+func newTestCache(size int) (*Cache, *time.Time){
+
+	clock := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	cache := New(size, time.Minute, 10 * time.Second)
+	cache.now = func() time.Time { return clock }
+	return cache, &clock
+}
+
+func TestCacheHitAndExpiry(t *testing.T) {
+
+	//Arrange
+	cache, clock := newTestCache(10)
+	cache.Put("38977123456", cache.Generation(), &dto.NumberLookupResponse{MNO: "A1"}, nil)
+
+	//Act
+	_, missed, _ := cache.Get("38970123456")
+	hit, found, err := cache.Get("38977123456")
+	hit.MNO = "changed"
+	again, _, _ := cache.Get("38977123456")
+	*clock = clock.Add(time.Minute)
+	_, expired, _ := cache.Get("38977123456")
+
+	//Assert
+	if missed || !found || err != nil || expired{
+		t.Errorf("Error in TestCacheHitAndExpiry:\n expected %s\n got %v, %v, %v, %v", "a miss, a hit and an expired entry", missed, found, err, expired)
+	}
+	if again.MNO != "A1"{
+		t.Errorf("Error in TestCacheHitAndExpiry:\n expected %s\n got %s", "a copy of the cached response", again.MNO)
+	}
+	stats := cache.Stats()
+	if stats.Hits != 2 || stats.Misses != 2 || stats.Entries != 0{
+		t.Errorf("Error in TestCacheHitAndExpiry:\n expected %s\n got %+v", "2 hits, 2 misses and no entries", stats)
+	}
+}
+
+func TestCacheNegativeResults(t *testing.T) {
+
+	//Arrange
+	cache, clock := newTestCache(10)
+	cache.Put("6934567890", cache.Generation(), nil, errs.NewNumberNotFoundError())
+
+	//Act
+	_, found, err := cache.Get("6934567890")
+	*clock = clock.Add(10 * time.Second)
+	_, expired, _ := cache.Get("6934567890")
+
+	//Assert
+	if _, ok := err.(*errs.NumberNotFoundError); !found || !ok{
+		t.Errorf("Error in TestCacheNegativeResults:\n expected %s\n got %v, %v", "a cached NumberNotFoundError", found, err)
+	}
+	if expired{
+		t.Error("Error in TestCacheNegativeResults:\n expected the negative result to expire before the TTL")
+	}
+	if cache.Stats().NegativeHits != 1{
+		t.Errorf("Error in TestCacheNegativeResults:\n expected %d\n got %d", 1, cache.Stats().NegativeHits)
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+
+	//Arrange
+	cache, _ := newTestCache(2)
+	generation := cache.Generation()
+	cache.Put("1", generation, &dto.NumberLookupResponse{}, nil)
+	cache.Put("2", generation, &dto.NumberLookupResponse{}, nil)
+	cache.Get("1")
+
+	//Act
+	cache.Put("3", generation, &dto.NumberLookupResponse{}, nil)
+
+	//Assert
+	_, first, _ := cache.Get("1")
+	_, second, _ := cache.Get("2")
+	if !first || second{
+		t.Errorf("Error in TestCacheEvictsLeastRecentlyUsed:\n expected %s\n got %v, %v", "2 evicted", first, second)
+	}
+	if cache.Stats().Evictions != 1{
+		t.Errorf("Error in TestCacheEvictsLeastRecentlyUsed:\n expected %d\n got %d", 1, cache.Stats().Evictions)
+	}
+}
+
+func TestCacheInvalidate(t *testing.T) {
+
+	//Arrange
+	cache, _ := newTestCache(10)
+	before := cache.Generation()
+	cache.Put("38977123456", before, &dto.NumberLookupResponse{MNO: "A1"}, nil)
+
+	//Act
+	cache.Invalidate()
+	cache.Put("38970123456", before, &dto.NumberLookupResponse{MNO: "Telekom"}, nil)
+
+	//Assert
+	_, found, _ := cache.Get("38977123456")
+	_, stale, _ := cache.Get("38970123456")
+	if found || stale{
+		t.Errorf("Error in TestCacheInvalidate:\n expected %s\n got %v, %v", "no results from before the invalidation", found, stale)
+	}
+}
+
+func TestDisabledCache(t *testing.T) {
+
+	//Arrange
+	cache := New(0, time.Minute, time.Minute)
+
+	//Act
+	cache.Put("38977123456", cache.Generation(), &dto.NumberLookupResponse{}, nil)
+	_, found, _ := cache.Get("38977123456")
+
+	//Assert
+	if found{
+		t.Error("Error in TestDisabledCache:\n expected a cache of size 0 to hold nothing")
+	}
+}
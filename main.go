@@ -1,10 +1,15 @@
 package main
 
 import "log"
+import "os"
 import "github.com/robesmi/MSISDNApp/web"
 
 
 func main(){
+	// Arguments run a maintenance command instead of the server
+	if len(os.Args) > 1{
+		os.Exit(web.RunCommand(os.Args[1:]))
+	}
 	log.Print("Log starting")
 	web.Start()
-}
\ No newline at end of file
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/robesmi/MSISDNApp/apiformat"
 	"github.com/robesmi/MSISDNApp/model/errs"
 	"github.com/robesmi/MSISDNApp/utils"
 	"github.com/robesmi/MSISDNApp/vault"
@@ -74,6 +75,7 @@ func ValidateTokenUserSection(vault vault.VaultInterface) gin.HandlerFunc{
 			// Check if token contains appropriate role
 			role := claims["role"]
 			if role == "user" || role == "admin"{
+				setClaims(c, claims)
 				c.Next()
 				return
 			}else{
@@ -145,6 +147,7 @@ func ValidateTokenAdminSection(vault vault.VaultInterface) gin.HandlerFunc {
 		// Check if token has appropriate role
 		role := claims["role"]
 		if role == "admin"{
+			setClaims(c, claims)
 			c.Next()
 		}else{
 			c.Redirect(http.StatusTemporaryRedirect, "/?error=Unauthorized")
@@ -166,7 +169,7 @@ func ValidateApiTokenUserSection(vault vault.VaultInterface) gin.HandlerFunc{
 		}
 		// If there's no token, kick user back
 		if access_token == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
+			apiformat.Write(c, http.StatusUnauthorized, gin.H{
 				"error": "No auth token",
 			})
 			c.Abort()
@@ -180,7 +183,7 @@ func ValidateApiTokenUserSection(vault vault.VaultInterface) gin.HandlerFunc{
 		if err != nil{
 			if _,ok := err.(*errs.ExpiredTokenError); ok{
 				// Redirect to refresh handler
-				c.JSON(http.StatusUnauthorized, gin.H{
+				apiformat.Write(c, http.StatusUnauthorized, gin.H{
 					"error": "Authorization token expired",
 				})
 				c.Abort()
@@ -188,7 +191,7 @@ func ValidateApiTokenUserSection(vault vault.VaultInterface) gin.HandlerFunc{
 
 			}else{
 				log.Println("Token error " + err.Error())
-				c.JSON(http.StatusInternalServerError, gin.H{
+				apiformat.Write(c, http.StatusInternalServerError, gin.H{
 					"error": "Internal error",
 				})
 				c.Abort()
@@ -199,14 +202,25 @@ func ValidateApiTokenUserSection(vault vault.VaultInterface) gin.HandlerFunc{
 		// Check if token contains appropriate role
 		role := claims["role"]
 		if role == "user"{
+			setClaims(c, claims)
 			c.Next()
 			return
 		}else{
-			c.JSON(http.StatusBadRequest,gin.H{
+			apiformat.Write(c, http.StatusBadRequest,gin.H{
 				"error":"Invalid access token, reauthorize.",
 			})
 			c.Abort()
 			return
 		}
 	}
-}
\ No newline at end of file
+}
+
+// setClaims makes the id and role of the token bearer available to the handlers
+func setClaims(c *gin.Context, claims jwt.MapClaims){
+	if id, ok := claims["id"].(string); ok{
+		c.Set("id", id)
+	}
+	if role, ok := claims["role"].(string); ok{
+		c.Set("role", role)
+	}
+}
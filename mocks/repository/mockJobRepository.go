@@ -0,0 +1,122 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/robesmi/MSISDNApp/repository (interfaces: JobRepository)
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/robesmi/MSISDNApp/model"
+)
+
+// MockJobRepository is a mock of JobRepository interface.
+type MockJobRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobRepositoryMockRecorder
+}
+
+// MockJobRepositoryMockRecorder is the mock recorder for MockJobRepository.
+type MockJobRepositoryMockRecorder struct {
+	mock *MockJobRepository
+}
+
+// NewMockJobRepository creates a new mock instance.
+func NewMockJobRepository(ctrl *gomock.Controller) *MockJobRepository {
+	mock := &MockJobRepository{ctrl: ctrl}
+	mock.recorder = &MockJobRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobRepository) EXPECT() *MockJobRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateJob mocks base method.
+func (m *MockJobRepository) CreateJob(arg0 *model.LookupJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJob", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateJob indicates an expected call of CreateJob.
+func (mr *MockJobRepositoryMockRecorder) CreateJob(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJob", reflect.TypeOf((*MockJobRepository)(nil).CreateJob), arg0)
+}
+
+// GetJobById mocks base method.
+func (m *MockJobRepository) GetJobById(arg0 string) (*model.LookupJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobById", arg0)
+	ret0, _ := ret[0].(*model.LookupJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobById indicates an expected call of GetJobById.
+func (mr *MockJobRepositoryMockRecorder) GetJobById(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobById", reflect.TypeOf((*MockJobRepository)(nil).GetJobById), arg0)
+}
+
+// GetJobsByOwner mocks base method.
+func (m *MockJobRepository) GetJobsByOwner(arg0 string) (*[]model.LookupJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobsByOwner", arg0)
+	ret0, _ := ret[0].(*[]model.LookupJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobsByOwner indicates an expected call of GetJobsByOwner.
+func (mr *MockJobRepositoryMockRecorder) GetJobsByOwner(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobsByOwner", reflect.TypeOf((*MockJobRepository)(nil).GetJobsByOwner), arg0)
+}
+
+// GetUnfinishedJobs mocks base method.
+func (m *MockJobRepository) GetUnfinishedJobs() (*[]model.LookupJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnfinishedJobs")
+	ret0, _ := ret[0].(*[]model.LookupJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnfinishedJobs indicates an expected call of GetUnfinishedJobs.
+func (mr *MockJobRepositoryMockRecorder) GetUnfinishedJobs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnfinishedJobs", reflect.TypeOf((*MockJobRepository)(nil).GetUnfinishedJobs))
+}
+
+// UpdateJobProgress mocks base method.
+func (m *MockJobRepository) UpdateJobProgress(arg0 string, arg1, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJobProgress", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateJobProgress indicates an expected call of UpdateJobProgress.
+func (mr *MockJobRepositoryMockRecorder) UpdateJobProgress(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJobProgress", reflect.TypeOf((*MockJobRepository)(nil).UpdateJobProgress), arg0, arg1, arg2)
+}
+
+// UpdateJobStatus mocks base method.
+func (m *MockJobRepository) UpdateJobStatus(arg0, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJobStatus", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateJobStatus indicates an expected call of UpdateJobStatus.
+func (mr *MockJobRepositoryMockRecorder) UpdateJobStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJobStatus", reflect.TypeOf((*MockJobRepository)(nil).UpdateJobStatus), arg0, arg1, arg2)
+}
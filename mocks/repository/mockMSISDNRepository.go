@@ -6,6 +6,7 @@ package repository
 
 import (
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/robesmi/MSISDNApp/model"
@@ -36,31 +37,45 @@ func (m *MockMSISDNRepository) EXPECT() *MockMSISDNRepositoryMockRecorder {
 }
 
 // AddNewCountry mocks base method.
-func (m *MockMSISDNRepository) AddNewCountry(arg0, arg1, arg2 string, arg3 int) error {
+func (m *MockMSISDNRepository) AddNewCountry(arg0 *model.Country) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddNewCountry", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "AddNewCountry", arg0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddNewCountry indicates an expected call of AddNewCountry.
-func (mr *MockMSISDNRepositoryMockRecorder) AddNewCountry(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockMSISDNRepositoryMockRecorder) AddNewCountry(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNewCountry", reflect.TypeOf((*MockMSISDNRepository)(nil).AddNewCountry), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNewCountry", reflect.TypeOf((*MockMSISDNRepository)(nil).AddNewCountry), arg0)
 }
 
 // AddNewMobileOperator mocks base method.
-func (m *MockMSISDNRepository) AddNewMobileOperator(arg0, arg1, arg2 string, arg3 int) error {
+func (m *MockMSISDNRepository) AddNewMobileOperator(arg0 *model.MobileOperator) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddNewMobileOperator", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "AddNewMobileOperator", arg0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddNewMobileOperator indicates an expected call of AddNewMobileOperator.
-func (mr *MockMSISDNRepositoryMockRecorder) AddNewMobileOperator(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockMSISDNRepositoryMockRecorder) AddNewMobileOperator(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNewMobileOperator", reflect.TypeOf((*MockMSISDNRepository)(nil).AddNewMobileOperator), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNewMobileOperator", reflect.TypeOf((*MockMSISDNRepository)(nil).AddNewMobileOperator), arg0)
+}
+
+// AddNewNetworkOperator mocks base method.
+func (m *MockMSISDNRepository) AddNewNetworkOperator(arg0 *model.NetworkOperator) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddNewNetworkOperator", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddNewNetworkOperator indicates an expected call of AddNewNetworkOperator.
+func (mr *MockMSISDNRepositoryMockRecorder) AddNewNetworkOperator(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNewNetworkOperator", reflect.TypeOf((*MockMSISDNRepository)(nil).AddNewNetworkOperator), arg0)
 }
 
 // GetAllCountries mocks base method.
@@ -78,6 +93,21 @@ func (mr *MockMSISDNRepositoryMockRecorder) GetAllCountries() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllCountries", reflect.TypeOf((*MockMSISDNRepository)(nil).GetAllCountries))
 }
 
+// GetAllCountryDetails mocks base method.
+func (m *MockMSISDNRepository) GetAllCountryDetails() (*[]model.CountryDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllCountryDetails")
+	ret0, _ := ret[0].(*[]model.CountryDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllCountryDetails indicates an expected call of GetAllCountryDetails.
+func (mr *MockMSISDNRepositoryMockRecorder) GetAllCountryDetails() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllCountryDetails", reflect.TypeOf((*MockMSISDNRepository)(nil).GetAllCountryDetails))
+}
+
 // GetAllMobileOperators mocks base method.
 func (m *MockMSISDNRepository) GetAllMobileOperators() (*[]model.MobileOperator, error) {
 	m.ctrl.T.Helper()
@@ -93,60 +123,192 @@ func (mr *MockMSISDNRepositoryMockRecorder) GetAllMobileOperators() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMobileOperators", reflect.TypeOf((*MockMSISDNRepository)(nil).GetAllMobileOperators))
 }
 
+// GetAllNetworkOperators mocks base method.
+func (m *MockMSISDNRepository) GetAllNetworkOperators() (*[]model.NetworkOperator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllNetworkOperators")
+	ret0, _ := ret[0].(*[]model.NetworkOperator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllNetworkOperators indicates an expected call of GetAllNetworkOperators.
+func (mr *MockMSISDNRepositoryMockRecorder) GetAllNetworkOperators() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllNetworkOperators", reflect.TypeOf((*MockMSISDNRepository)(nil).GetAllNetworkOperators))
+}
+
+// GetCountryByIdentifier mocks base method.
+func (m *MockMSISDNRepository) GetCountryByIdentifier(arg0 string) (*model.Country, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCountryByIdentifier", arg0)
+	ret0, _ := ret[0].(*model.Country)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCountryByIdentifier indicates an expected call of GetCountryByIdentifier.
+func (mr *MockMSISDNRepositoryMockRecorder) GetCountryByIdentifier(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCountryByIdentifier", reflect.TypeOf((*MockMSISDNRepository)(nil).GetCountryByIdentifier), arg0)
+}
+
+// GetCountryDetails mocks base method.
+func (m *MockMSISDNRepository) GetCountryDetails(arg0 string) (*model.CountryDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCountryDetails", arg0)
+	ret0, _ := ret[0].(*model.CountryDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCountryDetails indicates an expected call of GetCountryDetails.
+func (mr *MockMSISDNRepositoryMockRecorder) GetCountryDetails(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCountryDetails", reflect.TypeOf((*MockMSISDNRepository)(nil).GetCountryDetails), arg0)
+}
+
+// GetNetworkOperatorByName mocks base method.
+func (m *MockMSISDNRepository) GetNetworkOperatorByName(arg0 string) (*model.NetworkOperator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNetworkOperatorByName", arg0)
+	ret0, _ := ret[0].(*model.NetworkOperator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNetworkOperatorByName indicates an expected call of GetNetworkOperatorByName.
+func (mr *MockMSISDNRepositoryMockRecorder) GetNetworkOperatorByName(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNetworkOperatorByName", reflect.TypeOf((*MockMSISDNRepository)(nil).GetNetworkOperatorByName), arg0)
+}
+
+// LookupCallingCode mocks base method.
+func (m *MockMSISDNRepository) LookupCallingCode(arg0 string, arg1 time.Time) (*model.Country, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LookupCallingCode", arg0, arg1)
+	ret0, _ := ret[0].(*model.Country)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LookupCallingCode indicates an expected call of LookupCallingCode.
+func (mr *MockMSISDNRepositoryMockRecorder) LookupCallingCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupCallingCode", reflect.TypeOf((*MockMSISDNRepository)(nil).LookupCallingCode), arg0, arg1)
+}
+
 // LookupCountryCode mocks base method.
-func (m *MockMSISDNRepository) LookupCountryCode(arg0 string) (*dto.CountryLookupResponse, error) {
+func (m *MockMSISDNRepository) LookupCountryCode(arg0 string, arg1 time.Time) (*dto.CountryLookupResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LookupCountryCode", arg0)
+	ret := m.ctrl.Call(m, "LookupCountryCode", arg0, arg1)
 	ret0, _ := ret[0].(*dto.CountryLookupResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // LookupCountryCode indicates an expected call of LookupCountryCode.
-func (mr *MockMSISDNRepositoryMockRecorder) LookupCountryCode(arg0 interface{}) *gomock.Call {
+func (mr *MockMSISDNRepositoryMockRecorder) LookupCountryCode(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupCountryCode", reflect.TypeOf((*MockMSISDNRepository)(nil).LookupCountryCode), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupCountryCode", reflect.TypeOf((*MockMSISDNRepository)(nil).LookupCountryCode), arg0, arg1)
 }
 
 // LookupMobileOperator mocks base method.
-func (m *MockMSISDNRepository) LookupMobileOperator(arg0, arg1 string) (*dto.MobileOperatorLookupResponse, error) {
+func (m *MockMSISDNRepository) LookupMobileOperator(arg0, arg1 string, arg2 time.Time) (*dto.MobileOperatorLookupResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LookupMobileOperator", arg0, arg1)
+	ret := m.ctrl.Call(m, "LookupMobileOperator", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*dto.MobileOperatorLookupResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // LookupMobileOperator indicates an expected call of LookupMobileOperator.
-func (mr *MockMSISDNRepositoryMockRecorder) LookupMobileOperator(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockMSISDNRepositoryMockRecorder) LookupMobileOperator(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupMobileOperator", reflect.TypeOf((*MockMSISDNRepository)(nil).LookupMobileOperator), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupMobileOperator", reflect.TypeOf((*MockMSISDNRepository)(nil).LookupMobileOperator), arg0, arg1, arg2)
+}
+
+// LookupPartial mocks base method.
+func (m *MockMSISDNRepository) LookupPartial(arg0 string, arg1 time.Time) (*model.PartialMatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LookupPartial", arg0, arg1)
+	ret0, _ := ret[0].(*model.PartialMatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LookupPartial indicates an expected call of LookupPartial.
+func (mr *MockMSISDNRepositoryMockRecorder) LookupPartial(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupPartial", reflect.TypeOf((*MockMSISDNRepository)(nil).LookupPartial), arg0, arg1)
 }
 
 // RemoveCountry mocks base method.
-func (m *MockMSISDNRepository) RemoveCountry(arg0 string) error {
+func (m *MockMSISDNRepository) RemoveCountry(arg0 string, arg1 time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveCountry", arg0)
+	ret := m.ctrl.Call(m, "RemoveCountry", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RemoveCountry indicates an expected call of RemoveCountry.
-func (mr *MockMSISDNRepositoryMockRecorder) RemoveCountry(arg0 interface{}) *gomock.Call {
+func (mr *MockMSISDNRepositoryMockRecorder) RemoveCountry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCountry", reflect.TypeOf((*MockMSISDNRepository)(nil).RemoveCountry), arg0, arg1)
+}
+
+// RemoveNetworkOperator mocks base method.
+func (m *MockMSISDNRepository) RemoveNetworkOperator(arg0 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveNetworkOperator", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveNetworkOperator indicates an expected call of RemoveNetworkOperator.
+func (mr *MockMSISDNRepositoryMockRecorder) RemoveNetworkOperator(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCountry", reflect.TypeOf((*MockMSISDNRepository)(nil).RemoveCountry), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveNetworkOperator", reflect.TypeOf((*MockMSISDNRepository)(nil).RemoveNetworkOperator), arg0)
 }
 
 // RemoveOperator mocks base method.
-func (m *MockMSISDNRepository) RemoveOperator(arg0 string) error {
+func (m *MockMSISDNRepository) RemoveOperator(arg0 string, arg1 time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveOperator", arg0)
+	ret := m.ctrl.Call(m, "RemoveOperator", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RemoveOperator indicates an expected call of RemoveOperator.
-func (mr *MockMSISDNRepositoryMockRecorder) RemoveOperator(arg0 interface{}) *gomock.Call {
+func (mr *MockMSISDNRepositoryMockRecorder) RemoveOperator(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveOperator", reflect.TypeOf((*MockMSISDNRepository)(nil).RemoveOperator), arg0, arg1)
+}
+
+// SaveCountryDetails mocks base method.
+func (m *MockMSISDNRepository) SaveCountryDetails(arg0 *model.CountryDetails) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCountryDetails", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCountryDetails indicates an expected call of SaveCountryDetails.
+func (mr *MockMSISDNRepositoryMockRecorder) SaveCountryDetails(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCountryDetails", reflect.TypeOf((*MockMSISDNRepository)(nil).SaveCountryDetails), arg0)
+}
+
+// UpdatePlan mocks base method.
+func (m *MockMSISDNRepository) UpdatePlan(arg0 *model.PlanUpdate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePlan", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePlan indicates an expected call of UpdatePlan.
+func (mr *MockMSISDNRepositoryMockRecorder) UpdatePlan(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveOperator", reflect.TypeOf((*MockMSISDNRepository)(nil).RemoveOperator), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePlan", reflect.TypeOf((*MockMSISDNRepository)(nil).UpdatePlan), arg0)
 }
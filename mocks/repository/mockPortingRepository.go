@@ -0,0 +1,63 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/robesmi/MSISDNApp/repository (interfaces: PortingRepository)
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/robesmi/MSISDNApp/model"
+)
+
+// MockPortingRepository is a mock of PortingRepository interface.
+type MockPortingRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPortingRepositoryMockRecorder
+}
+
+// MockPortingRepositoryMockRecorder is the mock recorder for MockPortingRepository.
+type MockPortingRepositoryMockRecorder struct {
+	mock *MockPortingRepository
+}
+
+// NewMockPortingRepository creates a new mock instance.
+func NewMockPortingRepository(ctrl *gomock.Controller) *MockPortingRepository {
+	mock := &MockPortingRepository{ctrl: ctrl}
+	mock.recorder = &MockPortingRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPortingRepository) EXPECT() *MockPortingRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ApplyPortedNumbers mocks base method.
+func (m *MockPortingRepository) ApplyPortedNumbers(arg0 bool, arg1 func() ([]model.PortedNumber, error)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyPortedNumbers", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplyPortedNumbers indicates an expected call of ApplyPortedNumbers.
+func (mr *MockPortingRepositoryMockRecorder) ApplyPortedNumbers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyPortedNumbers", reflect.TypeOf((*MockPortingRepository)(nil).ApplyPortedNumbers), arg0, arg1)
+}
+
+// GetAllPortedNumbers mocks base method.
+func (m *MockPortingRepository) GetAllPortedNumbers(arg0 func(model.PortedNumber) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllPortedNumbers", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetAllPortedNumbers indicates an expected call of GetAllPortedNumbers.
+func (mr *MockPortingRepositoryMockRecorder) GetAllPortedNumbers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllPortedNumbers", reflect.TypeOf((*MockPortingRepository)(nil).GetAllPortedNumbers), arg0)
+}
@@ -6,10 +6,13 @@ package service
 
 import (
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
+	lookupcache "github.com/robesmi/MSISDNApp/lookupcache"
 	model "github.com/robesmi/MSISDNApp/model"
 	dto "github.com/robesmi/MSISDNApp/model/dto"
+	normalize "github.com/robesmi/MSISDNApp/normalize"
 )
 
 // MockMSISDNService is a mock of MSISDNService interface.
@@ -63,6 +66,35 @@ func (mr *MockMSISDNServiceMockRecorder) AddNewMobileOperator(arg0 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNewMobileOperator", reflect.TypeOf((*MockMSISDNService)(nil).AddNewMobileOperator), arg0)
 }
 
+// AddNewNetworkOperator mocks base method.
+func (m *MockMSISDNService) AddNewNetworkOperator(arg0 *dto.NetworkOperatorRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddNewNetworkOperator", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddNewNetworkOperator indicates an expected call of AddNewNetworkOperator.
+func (mr *MockMSISDNServiceMockRecorder) AddNewNetworkOperator(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNewNetworkOperator", reflect.TypeOf((*MockMSISDNService)(nil).AddNewNetworkOperator), arg0)
+}
+
+// ExtractMSISDNs mocks base method.
+func (m *MockMSISDNService) ExtractMSISDNs(arg0, arg1 string) (*dto.ExtractionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtractMSISDNs", arg0, arg1)
+	ret0, _ := ret[0].(*dto.ExtractionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExtractMSISDNs indicates an expected call of ExtractMSISDNs.
+func (mr *MockMSISDNServiceMockRecorder) ExtractMSISDNs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtractMSISDNs", reflect.TypeOf((*MockMSISDNService)(nil).ExtractMSISDNs), arg0, arg1)
+}
+
 // GetAllCountries mocks base method.
 func (m *MockMSISDNService) GetAllCountries() (*[]model.Country, error) {
 	m.ctrl.T.Helper()
@@ -78,6 +110,21 @@ func (mr *MockMSISDNServiceMockRecorder) GetAllCountries() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllCountries", reflect.TypeOf((*MockMSISDNService)(nil).GetAllCountries))
 }
 
+// GetAllCountryDetails mocks base method.
+func (m *MockMSISDNService) GetAllCountryDetails() (*[]dto.CountryDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllCountryDetails")
+	ret0, _ := ret[0].(*[]dto.CountryDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllCountryDetails indicates an expected call of GetAllCountryDetails.
+func (mr *MockMSISDNServiceMockRecorder) GetAllCountryDetails() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllCountryDetails", reflect.TypeOf((*MockMSISDNService)(nil).GetAllCountryDetails))
+}
+
 // GetAllMobileOperators mocks base method.
 func (m *MockMSISDNService) GetAllMobileOperators() (*[]model.MobileOperator, error) {
 	m.ctrl.T.Helper()
@@ -93,6 +140,80 @@ func (mr *MockMSISDNServiceMockRecorder) GetAllMobileOperators() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMobileOperators", reflect.TypeOf((*MockMSISDNService)(nil).GetAllMobileOperators))
 }
 
+// GetAllNetworkOperators mocks base method.
+func (m *MockMSISDNService) GetAllNetworkOperators() (*[]model.NetworkOperator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllNetworkOperators")
+	ret0, _ := ret[0].(*[]model.NetworkOperator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllNetworkOperators indicates an expected call of GetAllNetworkOperators.
+func (mr *MockMSISDNServiceMockRecorder) GetAllNetworkOperators() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllNetworkOperators", reflect.TypeOf((*MockMSISDNService)(nil).GetAllNetworkOperators))
+}
+
+// GetCacheStats mocks base method.
+func (m *MockMSISDNService) GetCacheStats() lookupcache.Stats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCacheStats")
+	ret0, _ := ret[0].(lookupcache.Stats)
+	return ret0
+}
+
+// GetCacheStats indicates an expected call of GetCacheStats.
+func (mr *MockMSISDNServiceMockRecorder) GetCacheStats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCacheStats", reflect.TypeOf((*MockMSISDNService)(nil).GetCacheStats))
+}
+
+// GetCountryDetails mocks base method.
+func (m *MockMSISDNService) GetCountryDetails(arg0 string) (*dto.CountryDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCountryDetails", arg0)
+	ret0, _ := ret[0].(*dto.CountryDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCountryDetails indicates an expected call of GetCountryDetails.
+func (mr *MockMSISDNServiceMockRecorder) GetCountryDetails(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCountryDetails", reflect.TypeOf((*MockMSISDNService)(nil).GetCountryDetails), arg0)
+}
+
+// GetOverlaps mocks base method.
+func (m *MockMSISDNService) GetOverlaps() (*[]model.RuleOverlap, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOverlaps")
+	ret0, _ := ret[0].(*[]model.RuleOverlap)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOverlaps indicates an expected call of GetOverlaps.
+func (mr *MockMSISDNServiceMockRecorder) GetOverlaps() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOverlaps", reflect.TypeOf((*MockMSISDNService)(nil).GetOverlaps))
+}
+
+// GetRegion mocks base method.
+func (m *MockMSISDNService) GetRegion(arg0 string) (*normalize.Region, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegion", arg0)
+	ret0, _ := ret[0].(*normalize.Region)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRegion indicates an expected call of GetRegion.
+func (mr *MockMSISDNServiceMockRecorder) GetRegion(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegion", reflect.TypeOf((*MockMSISDNService)(nil).GetRegion), arg0)
+}
+
 // LookupMSISDN mocks base method.
 func (m *MockMSISDNService) LookupMSISDN(arg0 string) (*dto.NumberLookupResponse, error) {
 	m.ctrl.T.Helper()
@@ -108,30 +229,148 @@ func (mr *MockMSISDNServiceMockRecorder) LookupMSISDN(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupMSISDN", reflect.TypeOf((*MockMSISDNService)(nil).LookupMSISDN), arg0)
 }
 
+// LookupMSISDNAt mocks base method.
+func (m *MockMSISDNService) LookupMSISDNAt(arg0 string, arg1 time.Time) (*dto.NumberLookupResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LookupMSISDNAt", arg0, arg1)
+	ret0, _ := ret[0].(*dto.NumberLookupResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LookupMSISDNAt indicates an expected call of LookupMSISDNAt.
+func (mr *MockMSISDNServiceMockRecorder) LookupMSISDNAt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupMSISDNAt", reflect.TypeOf((*MockMSISDNService)(nil).LookupMSISDNAt), arg0, arg1)
+}
+
+// LookupPartial mocks base method.
+func (m *MockMSISDNService) LookupPartial(arg0 string) (*dto.PartialLookupResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LookupPartial", arg0)
+	ret0, _ := ret[0].(*dto.PartialLookupResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LookupPartial indicates an expected call of LookupPartial.
+func (mr *MockMSISDNServiceMockRecorder) LookupPartial(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupPartial", reflect.TypeOf((*MockMSISDNService)(nil).LookupPartial), arg0)
+}
+
+// LookupPartialAt mocks base method.
+func (m *MockMSISDNService) LookupPartialAt(arg0 string, arg1 time.Time) (*dto.PartialLookupResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LookupPartialAt", arg0, arg1)
+	ret0, _ := ret[0].(*dto.PartialLookupResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LookupPartialAt indicates an expected call of LookupPartialAt.
+func (mr *MockMSISDNServiceMockRecorder) LookupPartialAt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupPartialAt", reflect.TypeOf((*MockMSISDNService)(nil).LookupPartialAt), arg0, arg1)
+}
+
 // RemoveCountry mocks base method.
-func (m *MockMSISDNService) RemoveCountry(arg0 string) error {
+func (m *MockMSISDNService) RemoveCountry(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveCountry", arg0)
+	ret := m.ctrl.Call(m, "RemoveCountry", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RemoveCountry indicates an expected call of RemoveCountry.
-func (mr *MockMSISDNServiceMockRecorder) RemoveCountry(arg0 interface{}) *gomock.Call {
+func (mr *MockMSISDNServiceMockRecorder) RemoveCountry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCountry", reflect.TypeOf((*MockMSISDNService)(nil).RemoveCountry), arg0, arg1)
+}
+
+// RemoveNetworkOperator mocks base method.
+func (m *MockMSISDNService) RemoveNetworkOperator(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveNetworkOperator", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveNetworkOperator indicates an expected call of RemoveNetworkOperator.
+func (mr *MockMSISDNServiceMockRecorder) RemoveNetworkOperator(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCountry", reflect.TypeOf((*MockMSISDNService)(nil).RemoveCountry), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveNetworkOperator", reflect.TypeOf((*MockMSISDNService)(nil).RemoveNetworkOperator), arg0)
 }
 
 // RemoveOperator mocks base method.
-func (m *MockMSISDNService) RemoveOperator(arg0 string) error {
+func (m *MockMSISDNService) RemoveOperator(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveOperator", arg0)
+	ret := m.ctrl.Call(m, "RemoveOperator", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RemoveOperator indicates an expected call of RemoveOperator.
-func (mr *MockMSISDNServiceMockRecorder) RemoveOperator(arg0 interface{}) *gomock.Call {
+func (mr *MockMSISDNServiceMockRecorder) RemoveOperator(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveOperator", reflect.TypeOf((*MockMSISDNService)(nil).RemoveOperator), arg0, arg1)
+}
+
+// SaveCountryDetails mocks base method.
+func (m *MockMSISDNService) SaveCountryDetails(arg0 *dto.CountryDetailsRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCountryDetails", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCountryDetails indicates an expected call of SaveCountryDetails.
+func (mr *MockMSISDNServiceMockRecorder) SaveCountryDetails(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCountryDetails", reflect.TypeOf((*MockMSISDNService)(nil).SaveCountryDetails), arg0)
+}
+
+// SelfCheck mocks base method.
+func (m *MockMSISDNService) SelfCheck() (*[]model.RuleCheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelfCheck")
+	ret0, _ := ret[0].(*[]model.RuleCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelfCheck indicates an expected call of SelfCheck.
+func (mr *MockMSISDNServiceMockRecorder) SelfCheck() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelfCheck", reflect.TypeOf((*MockMSISDNService)(nil).SelfCheck))
+}
+
+// ValidateMSISDN mocks base method.
+func (m *MockMSISDNService) ValidateMSISDN(arg0 string) (*dto.ValidationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateMSISDN", arg0)
+	ret0, _ := ret[0].(*dto.ValidationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateMSISDN indicates an expected call of ValidateMSISDN.
+func (mr *MockMSISDNServiceMockRecorder) ValidateMSISDN(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateMSISDN", reflect.TypeOf((*MockMSISDNService)(nil).ValidateMSISDN), arg0)
+}
+
+// ValidateMSISDNAt mocks base method.
+func (m *MockMSISDNService) ValidateMSISDNAt(arg0 string, arg1 time.Time) (*dto.ValidationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateMSISDNAt", arg0, arg1)
+	ret0, _ := ret[0].(*dto.ValidationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateMSISDNAt indicates an expected call of ValidateMSISDNAt.
+func (mr *MockMSISDNServiceMockRecorder) ValidateMSISDNAt(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveOperator", reflect.TypeOf((*MockMSISDNService)(nil).RemoveOperator), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateMSISDNAt", reflect.TypeOf((*MockMSISDNService)(nil).ValidateMSISDNAt), arg0, arg1)
 }
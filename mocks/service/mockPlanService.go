@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/robesmi/MSISDNApp/service (interfaces: PlanService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	io "io"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/robesmi/MSISDNApp/model"
+)
+
+// MockPlanService is a mock of PlanService interface.
+type MockPlanService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPlanServiceMockRecorder
+}
+
+// MockPlanServiceMockRecorder is the mock recorder for MockPlanService.
+type MockPlanServiceMockRecorder struct {
+	mock *MockPlanService
+}
+
+// NewMockPlanService creates a new mock instance.
+func NewMockPlanService(ctrl *gomock.Controller) *MockPlanService {
+	mock := &MockPlanService{ctrl: ctrl}
+	mock.recorder = &MockPlanServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPlanService) EXPECT() *MockPlanServiceMockRecorder {
+	return m.recorder
+}
+
+// ExportPlan mocks base method.
+func (m *MockPlanService) ExportPlan(arg0 io.Writer, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportPlan", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportPlan indicates an expected call of ExportPlan.
+func (mr *MockPlanServiceMockRecorder) ExportPlan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportPlan", reflect.TypeOf((*MockPlanService)(nil).ExportPlan), arg0, arg1)
+}
+
+// ImportPlan mocks base method.
+func (m *MockPlanService) ImportPlan(arg0 io.Reader, arg1 string, arg2 bool) (*model.PlanDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportPlan", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.PlanDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportPlan indicates an expected call of ImportPlan.
+func (mr *MockPlanServiceMockRecorder) ImportPlan(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportPlan", reflect.TypeOf((*MockPlanService)(nil).ImportPlan), arg0, arg1, arg2)
+}
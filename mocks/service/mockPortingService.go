@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/robesmi/MSISDNApp/service (interfaces: PortingService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	io "io"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPortingService is a mock of PortingService interface.
+type MockPortingService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPortingServiceMockRecorder
+}
+
+// MockPortingServiceMockRecorder is the mock recorder for MockPortingService.
+type MockPortingServiceMockRecorder struct {
+	mock *MockPortingService
+}
+
+// NewMockPortingService creates a new mock instance.
+func NewMockPortingService(ctrl *gomock.Controller) *MockPortingService {
+	mock := &MockPortingService{ctrl: ctrl}
+	mock.recorder = &MockPortingServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPortingService) EXPECT() *MockPortingServiceMockRecorder {
+	return m.recorder
+}
+
+// CountPortedNumbers mocks base method.
+func (m *MockPortingService) CountPortedNumbers() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountPortedNumbers")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// CountPortedNumbers indicates an expected call of CountPortedNumbers.
+func (mr *MockPortingServiceMockRecorder) CountPortedNumbers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountPortedNumbers", reflect.TypeOf((*MockPortingService)(nil).CountPortedNumbers))
+}
+
+// LoadPortedNumbers mocks base method.
+func (m *MockPortingService) LoadPortedNumbers(arg0 io.Reader, arg1 bool) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadPortedNumbers", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadPortedNumbers indicates an expected call of LoadPortedNumbers.
+func (mr *MockPortingServiceMockRecorder) LoadPortedNumbers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadPortedNumbers", reflect.TypeOf((*MockPortingService)(nil).LoadPortedNumbers), arg0, arg1)
+}
+
+// Reload mocks base method.
+func (m *MockPortingService) Reload() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reload")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reload indicates an expected call of Reload.
+func (mr *MockPortingServiceMockRecorder) Reload() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reload", reflect.TypeOf((*MockPortingService)(nil).Reload))
+}
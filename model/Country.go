@@ -1,11 +1,20 @@
 package model
 
-import "github.com/robesmi/MSISDNApp/model/dto"
+import (
+	"time"
+
+	"github.com/robesmi/MSISDNApp/model/dto"
+)
 
 type Country struct {
+	// ID identifies the rule, as a pattern can have several rules in effect at different times
+	ID int						`db:"id"`
 	// CountryNumberFormat is a regex representing the country code and format
 	// of each country, identified by the first 1-4 digits
 	CountryNumberFormat string	`db:"country_number_format"`
+	// ExcludedFormat is a regex matching the numbers within CountryNumberFormat
+	// that don't belong to the country, empty if there are none
+	ExcludedFormat string		`db:"excluded_format"`
 	// CountryCode is an int with the country code needed to dial the country
 	CountryCode string			`db:"country_code"`
 	// CountryIdentifier is a ISO 3166-1-alpha-2 format of the country
@@ -14,6 +23,33 @@ type Country struct {
 	// CountryCodeLength is the length of the CountryCode, used to
 	// trim away the unneeded country code in following queries
 	CountryCodeLength int		`db:"country_code_length"`
+	// TrunkPrefix is dialed in front of national numbers within the country,
+	// empty if the country doesn't use one
+	TrunkPrefix string			`db:"trunk_prefix"`
+	// InternationalPrefix is a comma separated list of the prefixes dialed
+	// from the country in front of a foreign calling code
+	InternationalPrefix string	`db:"international_prefix"`
+	// NSNMinLength and NSNMaxLength are the shortest and longest national
+	// significant numbers of the country, 0 when not known
+	NSNMinLength int			`db:"nsn_min_length"`
+	NSNMaxLength int			`db:"nsn_max_length"`
+	// NumberGrouping is a semicolon separated list of patterns for displaying national
+	// significant numbers, with an X for every digit, e.g. "XX XXX XXX". A pattern can be
+	// limited to numbers starting with a prefix like "2:X XXX XXXX", and the first one
+	// with as many digits as the number is used
+	NumberGrouping string		`db:"number_grouping"`
+	// Priority decides which country a number belongs to when the patterns of several
+	// countries match it, the highest one wins
+	Priority int				`db:"priority"`
+	// EffectiveFrom and EffectiveTo bound the period the rule is in effect, from
+	// EffectiveFrom included up to EffectiveTo excluded. Nil means unbounded
+	EffectiveFrom *time.Time	`db:"effective_from"`
+	EffectiveTo *time.Time		`db:"effective_to"`
+}
+
+// EffectiveAt reports whether the rule is in effect at the time
+func (c *Country) EffectiveAt(at time.Time) bool{
+	return effectiveAt(c.EffectiveFrom, c.EffectiveTo, at)
 }
 
 func (c *Country) toDto() dto.CountryLookupResponse{ 
@@ -21,5 +57,7 @@ func (c *Country) toDto() dto.CountryLookupResponse{
 		CountryCode: c.CountryCode,
 		CountryIdentifier: c.CountryIdentifier,
 		CountryCodeLength: c.CountryCodeLength,
+		TrunkPrefix: c.TrunkPrefix,
+		NumberGrouping: c.NumberGrouping,
 	}
 }
\ No newline at end of file
@@ -0,0 +1,22 @@
+package model
+
+// CountryDetails describes a country of the numbering plan, whose rules refer to it
+// by its ISO 3166-1-alpha-2 identifier
+type CountryDetails struct {
+	// CountryIdentifier is the ISO 3166-1-alpha-2 code of the country in lowercase, e.g. "mk"
+	CountryIdentifier string	`db:"country_identifier"`
+	// Name is the English short name of the country and NativeName the one used in the country
+	Name string					`db:"name"`
+	NativeName string			`db:"native_name"`
+	// Alpha3 is the ISO 3166-1-alpha-3 code, e.g. "MKD"
+	Alpha3 string				`db:"iso_alpha3"`
+	// Numeric is the ISO 3166-1 numeric code, three digits with leading zeros, e.g. "040"
+	Numeric string				`db:"iso_numeric"`
+	// TimeZones is a comma separated list of the IANA time zones of the country
+	TimeZones string			`db:"time_zones"`
+	// Currency is the ISO 4217 code of the country's currency
+	Currency string				`db:"currency"`
+	// Region is the UN geoscheme subregion, e.g. "Southern Europe", and Continent the continent
+	Region string				`db:"region"`
+	Continent string			`db:"continent"`
+}
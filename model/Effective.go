@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// effectiveAt reports whether a moment falls within an effective period, which
+// starts at from and ends just before to, with nil meaning unbounded
+func effectiveAt(from *time.Time, to *time.Time, at time.Time) bool{
+	return (from == nil || !at.Before(*from)) && (to == nil || at.Before(*to))
+}
+
+// PeriodsIntersect reports whether two effective periods, with nil meaning
+// unbounded, have a moment in common
+func PeriodsIntersect(fromA *time.Time, toA *time.Time, fromB *time.Time, toB *time.Time) bool{
+	return (toB == nil || fromA == nil || fromA.Before(*toB)) && (toA == nil || fromB == nil || fromB.Before(*toA))
+}
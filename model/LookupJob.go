@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+const (
+	JobQueued = "queued"
+	JobRunning = "running"
+	JobCompleted = "completed"
+	JobFailed = "failed"
+	JobCancelled = "cancelled"
+)
+
+type LookupJob struct {
+	ID string				`db:"id" json:"id" xml:"id"`
+	// Owner is the uuid of the user that uploaded the file
+	Owner string			`db:"owner" json:"-"`
+	FileName string			`db:"file_name" json:"file_name" xml:"file_name"`
+	Status string			`db:"status" json:"status" xml:"status"`
+	// Total is the amount of numbers in the uploaded file
+	Total int				`db:"total" json:"total" xml:"total"`
+	// Processed is the amount of numbers already written to the results file
+	Processed int			`db:"processed" json:"processed" xml:"processed"`
+	// Failed is the amount of processed numbers that couldn't be looked up
+	Failed int				`db:"failed" json:"failed" xml:"failed"`
+	Error string			`db:"error" json:"error,omitempty" xml:"error,omitempty"`
+	CreatedAt time.Time		`db:"created_at" json:"created_at" xml:"created_at"`
+	UpdatedAt time.Time		`db:"updated_at" json:"updated_at" xml:"updated_at"`
+}
+
+// Finished reports whether the job has stopped for good
+func (j LookupJob) Finished() bool{
+	return j.Status == JobCompleted || j.Status == JobFailed || j.Status == JobCancelled
+}
@@ -1,24 +1,57 @@
 package model
 
-import "github.com/robesmi/MSISDNApp/model/dto"
+import (
+	"time"
+
+	"github.com/robesmi/MSISDNApp/model/dto"
+)
 
 type MobileOperator struct {
+	// ID identifies the range, as a pattern can have several ranges in effect at different times
+	ID int						`db:"id"`
 	// CountryIdentifier is a ISO 3166-1-alpha-2 format of the country
 	// the MSISDN belongs to
 	CountryIdentifier string	`db:"country_identifier"`
 	// Prefix is a regex to distinguish the NCD and format of numbers
 	// for each MNO
 	PrefixFormat string			`db:"prefix_format"`
-	// MNO is the name of the MNO the regex applies to
+	// ExcludedFormat is a regex matching the numbers within PrefixFormat that
+	// don't belong to the MNO, empty if there are none
+	ExcludedFormat string		`db:"excluded_format"`
+	// OperatorID is the network operator the range is assigned to
+	OperatorID int				`db:"operator_id"`
+	// MNO is the brand name of the operator the regex applies to
 	MNO string					`db:"mno"`
+	// NetworkCodes are the MCC-MNC pairs of the operator
+	NetworkCodes string			`db:"network_codes"`
+	// HostNetwork is the brand name of the operator hosting an MVNO
+	HostNetwork string			`db:"host_network"`
 	// PrefixLength is the length of the MNO's carrier code, used to
 	// trim away the unneeded carrier code to isolate the subscriber number
 	PrefixLength int			`db:"prefix_length"`
+	// NumberType is the type of the numbers in the range, one of NumberTypes
+	NumberType string			`db:"number_type"`
+	// Priority decides which operator of the country a number belongs to when
+	// the patterns of several operators match it, the highest one wins
+	Priority int				`db:"priority"`
+	// EffectiveFrom and EffectiveTo bound the period the range is in effect, from
+	// EffectiveFrom included up to EffectiveTo excluded. Nil means unbounded
+	EffectiveFrom *time.Time	`db:"effective_from"`
+	EffectiveTo *time.Time		`db:"effective_to"`
+}
+
+// EffectiveAt reports whether the range is in effect at the time
+func (m *MobileOperator) EffectiveAt(at time.Time) bool{
+	return effectiveAt(m.EffectiveFrom, m.EffectiveTo, at)
 }
 
-func (m *MobileOperator) toDto() dto.MobileOperatorLookupResponse{
+func (m *MobileOperator) ToDto() dto.MobileOperatorLookupResponse{
 	return dto.MobileOperatorLookupResponse{
 		MNO: m.MNO,
+		OperatorID: m.OperatorID,
+		NetworkCodes: m.NetworkCodes,
+		HostNetwork: m.HostNetwork,
 		PrefixLength: m.PrefixLength,
+		NumberType: m.NumberType,
 	}
 }
\ No newline at end of file
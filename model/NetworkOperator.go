@@ -0,0 +1,45 @@
+package model
+
+// Operating statuses of a network operator
+const (
+	OperatorStatusActive = "active"
+	OperatorStatusPlanned = "planned"
+	OperatorStatusInactive = "inactive"
+)
+
+// OperatorStatuses lists every supported operating status
+var OperatorStatuses = []string{
+	OperatorStatusActive,
+	OperatorStatusPlanned,
+	OperatorStatusInactive,
+}
+
+// IsOperatorStatus reports whether the string is one of the supported operating statuses
+func IsOperatorStatus(s string) bool{
+
+	for _, status := range OperatorStatuses{
+		if s == status{
+			return true
+		}
+	}
+	return false
+}
+
+// NetworkOperator is an operator the number ranges of the numbering plan are assigned to
+type NetworkOperator struct {
+	ID int						`db:"id"`
+	// BrandName is the name the operator is known by, e.g. "Vodafone"
+	BrandName string			`db:"brand_name"`
+	// LegalName is the registered name of the company running the operator
+	LegalName string			`db:"legal_name"`
+	// Status is the operating status of the operator, one of OperatorStatuses
+	Status string				`db:"status"`
+	// NetworkCodes is a comma separated list of the operator's MCC-MNC pairs, e.g. "268-01"
+	NetworkCodes string			`db:"network_codes"`
+	// HostOperatorID is the operator whose network an MVNO runs on, 0 for operators
+	// with their own network
+	HostOperatorID int			`db:"host_operator_id"`
+	// HostNetwork is the brand name of the host operator, empty for operators
+	// with their own network
+	HostNetwork string			`db:"host_network"`
+}
@@ -0,0 +1,36 @@
+package model
+
+// Types a number range of the numbering plan can be classified as
+const (
+	NumberTypeMobile = "mobile"
+	NumberTypeFixedLine = "fixed_line"
+	NumberTypeTollFree = "toll_free"
+	NumberTypePremiumRate = "premium_rate"
+	NumberTypeSharedCost = "shared_cost"
+	NumberTypeVoIP = "voip"
+	NumberTypeM2M = "m2m"
+	NumberTypePager = "pager"
+)
+
+// NumberTypes lists every supported number type
+var NumberTypes = []string{
+	NumberTypeMobile,
+	NumberTypeFixedLine,
+	NumberTypeTollFree,
+	NumberTypePremiumRate,
+	NumberTypeSharedCost,
+	NumberTypeVoIP,
+	NumberTypeM2M,
+	NumberTypePager,
+}
+
+// IsNumberType reports whether the string is one of the supported number types
+func IsNumberType(t string) bool{
+
+	for _, numberType := range NumberTypes{
+		if t == numberType{
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package model
+
+// PartialMatch is what a number still being typed can turn out to be
+type PartialMatch struct {
+	// Complete is set when the digits already look up to a country and one of its ranges
+	Complete bool
+	// MinRemaining and MaxRemaining bound how many more digits the number can take
+	MinRemaining int
+	MaxRemaining int
+	Countries []PartialCountry
+	Operators []PartialOperator
+}
+
+// PartialCountry is a country a number being typed can still belong to
+type PartialCountry struct {
+	Country Country
+	MinRemaining int
+	MaxRemaining int
+}
+
+// PartialOperator is a range a number being typed can still belong to
+type PartialOperator struct {
+	Operator MobileOperator
+	MinRemaining int
+	MaxRemaining int
+}
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// Changes an import makes to a numbering plan rule
+const (
+	PlanChangeAdded = "added"
+	PlanChangeChanged = "changed"
+	PlanChangeRemoved = "removed"
+	PlanChangeInvalid = "invalid"
+)
+
+// PlanChange is a difference between an imported numbering plan and the saved one
+type PlanChange struct {
+	// Change is one of PlanChangeAdded, PlanChangeChanged, PlanChangeRemoved or PlanChangeInvalid
+	Change string
+	// Kind is either RuleKindCountry or RuleKindOperator
+	Kind string
+	// Row is the row of the file the change comes from, 0 for removed rules
+	Row int
+	CountryIdentifier string
+	Pattern string
+	EffectiveFrom *time.Time
+	// Details lists the fields that changed, or why an invalid row was rejected
+	Details string
+}
+
+// PlanDiff is the outcome of importing a numbering plan
+type PlanDiff struct {
+	Changes []PlanChange
+	Added int
+	Changed int
+	Removed int
+	Invalid int
+	// Overlaps are the pairs of overlapping rules of the imported plan
+	Overlaps []RuleOverlap
+	// Applied is set once the valid rows were saved
+	Applied bool
+}
+
+// PlanUpdate holds the rules an import adds, changes and removes. Changed and
+// removed rules are identified by their ID
+type PlanUpdate struct {
+	AddedCountries []Country
+	ChangedCountries []Country
+	RemovedCountries []int
+	AddedOperators []MobileOperator
+	ChangedOperators []MobileOperator
+	RemovedOperators []int
+}
@@ -0,0 +1,9 @@
+package model
+
+type PortedNumber struct {
+	// MSISDN is the full number that was ported
+	MSISDN string	`db:"msisdn"`
+	// MNO is the operator the number was ported to, an empty MNO
+	// in an incremental update means the number is no longer ported
+	MNO string		`db:"mno"`
+}
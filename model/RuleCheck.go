@@ -0,0 +1,34 @@
+package model
+
+// Problems the self-check finds with a numbering plan rule
+const (
+	// RuleProblemUnsupported is a rule whose pattern the lookup index can't compile
+	RuleProblemUnsupported = "unsupported"
+	// RuleProblemUnreachable is a rule that matches no number, or for a range, no number of its country
+	RuleProblemUnreachable = "unreachable"
+	// RuleProblemShadowed is a rule whose every number resolves to another rule
+	RuleProblemShadowed = "shadowed"
+	// RuleProblemOutsideCountry is a range matching numbers its country's pattern doesn't
+	RuleProblemOutsideCountry = "outside_country"
+	// RuleProblemLookupMismatch is a rule whose example number doesn't look up to it
+	RuleProblemLookupMismatch = "lookup_mismatch"
+)
+
+// RuleCheck is the outcome of the self-check of a numbering plan rule in effect
+type RuleCheck struct {
+	// Kind is either RuleKindCountry or RuleKindOperator
+	Kind string
+	CountryIdentifier string
+	Pattern string
+	Priority int
+	// MNO is the operator a range is assigned to
+	MNO string
+	// Example is a full number resolving to the rule, or when there's none, a number the rule matches
+	Example string
+	// Problems lists what's wrong with the rule, empty when it checked out
+	Problems []string
+	// Winner is the rule numbers of a shadowed rule resolve to
+	Winner string
+	// Detail explains the problems
+	Detail string
+}
@@ -0,0 +1,23 @@
+package model
+
+// Kinds of numbering plan rules
+const (
+	RuleKindCountry = "country"
+	RuleKindOperator = "operator"
+)
+
+// RuleOverlap is a pair of numbering plan rules that both match some number
+type RuleOverlap struct {
+	// Kind is either RuleKindCountry or RuleKindOperator
+	Kind string
+	// CountryIdentifier is the country of two overlapping operator rules
+	CountryIdentifier string
+	First string
+	FirstPriority int
+	Second string
+	SecondPriority int
+	// Example is the shortest number matched by both rules
+	Example string
+	// Winner is the rule the example number resolves to
+	Winner string
+}
@@ -0,0 +1,18 @@
+package model
+
+// Verdicts of a number validity check
+const (
+	// ValidityValid means the number belongs to a known range of its country
+	ValidityValid = "valid"
+	// ValidityInvalidCountryCode means the number doesn't start with a known calling code
+	ValidityInvalidCountryCode = "invalid_country_code"
+	// ValidityTooShort means the national significant number is shorter than the country allows
+	ValidityTooShort = "too_short"
+	// ValidityTooLong means the national significant number is longer than the country allows
+	ValidityTooLong = "too_long"
+	// ValidityUnknownRange means the number fits its country but no range of the country contains it
+	ValidityUnknownRange = "unknown_range"
+	// ValidityPossiblyValid means the number has a valid length for its country but doesn't match
+	// the country's format, so it can't be confirmed either way
+	ValidityPossiblyValid = "possibly_valid"
+)
@@ -0,0 +1,11 @@
+package dto
+
+type BatchLookupItem struct {
+	Number string					`json:"number" xml:"number"`
+	Result *NumberLookupResponse	`json:"result,omitempty" xml:"result,omitempty"`
+	Error string					`json:"error,omitempty" xml:"error,omitempty"`
+}
+
+type BatchLookupResponse struct {
+	Results []BatchLookupItem	`json:"results" xml:"results>item"`
+}
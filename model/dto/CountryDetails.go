@@ -0,0 +1,14 @@
+package dto
+
+// CountryDetails describes the country of a looked up number
+type CountryDetails struct {
+	Name string	`json:"Name" xml:"name"`
+	NativeName string	`json:"Native Name,omitempty" xml:"native_name,omitempty"`
+	Alpha2 string	`json:"ISO Alpha-2" xml:"iso_alpha2"`
+	Alpha3 string	`json:"ISO Alpha-3" xml:"iso_alpha3"`
+	Numeric string	`json:"ISO Numeric" xml:"iso_numeric"`
+	TimeZones []string	`json:"Time Zones,omitempty" xml:"time_zone,omitempty"`
+	Currency string	`json:"Currency,omitempty" xml:"currency,omitempty"`
+	Region string	`json:"Region,omitempty" xml:"region,omitempty"`
+	Continent string	`json:"Continent,omitempty" xml:"continent,omitempty"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+type CountryDetailsRequest struct {
+	CountryIdentifier	string	`form:"countryidentifier"`
+	Name				string	`form:"name"`
+	NativeName			string	`form:"nativename"`
+	Alpha3				string	`form:"alpha3"`
+	Numeric				string	`form:"numeric"`
+	TimeZones			string	`form:"timezones"`
+	Currency			string	`form:"currency"`
+	Region				string	`form:"region"`
+	Continent			string	`form:"continent"`
+}
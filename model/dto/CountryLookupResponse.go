@@ -4,4 +4,6 @@ type CountryLookupResponse struct {
 	CountryCode string	`db:"country_code"`
 	CountryIdentifier string	`db:"country_identifier"`
 	CountryCodeLength int		`db:"country_code_length"`
+	TrunkPrefix string	`db:"trunk_prefix"`
+	NumberGrouping string	`db:"number_grouping"`
 }
\ No newline at end of file
@@ -2,7 +2,16 @@ package dto
 
 type CountryRequest struct {
 	CountryNumberFormat	string	`form:"countryformat"`
+	ExcludedFormat		string	`form:"excludedformat"`
 	CountryCode			string	`form:"countrycode"`
 	CountryIdentifier	string	`form:"countryidentifier"`
 	CountryCodeLength	string	`form:"countrycodelength"`
+	TrunkPrefix			string	`form:"trunkprefix"`
+	InternationalPrefix	string	`form:"internationalprefix"`
+	NSNMinLength		string	`form:"nsnminlength"`
+	NSNMaxLength		string	`form:"nsnmaxlength"`
+	NumberGrouping		string	`form:"numbergrouping"`
+	Priority			string	`form:"priority"`
+	EffectiveFrom		string	`form:"effectivefrom"`
+	EffectiveTo			string	`form:"effectiveto"`
 }
\ No newline at end of file
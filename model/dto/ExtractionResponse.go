@@ -0,0 +1,16 @@
+package dto
+
+type ExtractedNumber struct {
+	// Text is the number the way it's written, found between the Start and End character offsets
+	Text string						`json:"text" xml:"text"`
+	Start int						`json:"start" xml:"start"`
+	End int							`json:"end" xml:"end"`
+	Number string					`json:"number" xml:"number"`
+	Normalization []string			`json:"normalization_steps,omitempty" xml:"normalization_step,omitempty"`
+	Result *NumberLookupResponse	`json:"result,omitempty" xml:"result,omitempty"`
+	Error string					`json:"error,omitempty" xml:"error,omitempty"`
+}
+
+type ExtractionResponse struct {
+	Numbers []ExtractedNumber	`json:"numbers" xml:"numbers>item"`
+}
@@ -3,4 +3,8 @@ package dto
 type MobileOperatorLookupResponse struct {
 	MNO string	`db:"mno"`
 	PrefixLength int	`db:"prefix_length"`
+	NumberType string	`db:"number_type"`
+	OperatorID int	`db:"operator_id"`
+	NetworkCodes string	`db:"network_codes"`
+	HostNetwork string	`db:"host_network"`
 }
\ No newline at end of file
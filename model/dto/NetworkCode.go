@@ -0,0 +1,7 @@
+package dto
+
+// NetworkCode identifies an operator's network by its mobile country and network codes
+type NetworkCode struct {
+	MCC string	`json:"MCC" xml:"mcc"`
+	MNC string	`json:"MNC" xml:"mnc"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+type NetworkOperatorRequest struct {
+	BrandName		string	`form:"brandname"`
+	LegalName		string	`form:"legalname"`
+	Status			string	`form:"status"`
+	NetworkCodes	string	`form:"networkcodes"`
+	HostOperatorID	string	`form:"hostoperatorid"`
+}
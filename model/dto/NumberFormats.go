@@ -0,0 +1,8 @@
+package dto
+
+type NumberFormats struct {
+	E164 string				`json:"E164" xml:"e164"`
+	International string	`json:"International" xml:"international"`
+	National string			`json:"National" xml:"national"`
+	RFC3966 string			`json:"RFC3966" xml:"rfc3966"`
+}
@@ -1,12 +1,30 @@
 package dto
 
+import "time"
+
 type NumberLookupResponse struct{
-	MNO string	`json:"MNO identifier" db:"mno"`
-	CC string	`json:"Country Code" db:"country_code"`
-	SN string	`json:"Subscriber Number"`
-	CI string	`json:"Country Identifier" db:"country_identifier"`
+	MNO string	`json:"MNO identifier" db:"mno" xml:"mno"`
+	OperatorID int	`json:"Operator ID,omitempty" xml:"operator_id,omitempty"`
+	// NetworkCodes are the MCC/MNC pairs of the MNO
+	NetworkCodes []NetworkCode	`json:"MCC/MNC,omitempty" xml:"network_code,omitempty"`
+	// HostNetwork is the operator whose network the MNO runs on when it's an MVNO
+	HostNetwork string	`json:"Host Network,omitempty" xml:"host_network,omitempty"`
+	CC string	`json:"Country Code" db:"country_code" xml:"country_code"`
+	SN string	`json:"Subscriber Number" xml:"subscriber_number"`
+	CI string	`json:"Country Identifier" db:"country_identifier" xml:"country_identifier"`
+	Type string	`json:"Number Type" db:"number_type" xml:"number_type"`
+	// Country describes the country of the number, when its details are known
+	Country *CountryDetails	`json:"Country,omitempty" xml:"country,omitempty"`
+	Ported bool	`json:"Ported" xml:"ported"`
+	// RangeHolder is the MNO holding the range of a ported number
+	RangeHolder string	`json:"Original Range Holder,omitempty" xml:"range_holder,omitempty"`
+	Formats NumberFormats	`json:"Formats" xml:"formats"`
+	// Normalization lists the steps taken to turn the input into the MSISDN
+	Normalization []string	`json:"Normalization Steps,omitempty" xml:"normalization_step,omitempty"`
+	// AsOf is the time the numbering plan was looked up at, when it wasn't the current one
+	AsOf *time.Time	`json:"As Of,omitempty" xml:"as_of,omitempty"`
 }
 
 func (r NumberLookupResponse) Compare(a NumberLookupResponse) bool {
-	return r.MNO == a.MNO && r.CC == a.CC && r.SN == a.SN && r.CI == a.CI
+	return r.MNO == a.MNO && r.OperatorID == a.OperatorID && r.HostNetwork == a.HostNetwork && r.CC == a.CC && r.SN == a.SN && r.CI == a.CI && r.Type == a.Type && r.Ported == a.Ported && r.RangeHolder == a.RangeHolder
 }
\ No newline at end of file
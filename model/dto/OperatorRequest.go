@@ -3,6 +3,11 @@ package dto
 type OperatorRequest struct {
 	CountryIdentifier	string	`form:"countryidentifier"`
 	PrefixFormat		string	`form:"prefixformat"`
-	MNO					string	`form:"mno"`
+	ExcludedFormat		string	`form:"excludedformat"`
+	OperatorID			string	`form:"operatorid"`
 	PrefixLength		string	`form:"prefixlength"`
+	NumberType			string	`form:"numbertype"`
+	Priority			string	`form:"priority"`
+	EffectiveFrom		string	`form:"effectivefrom"`
+	EffectiveTo			string	`form:"effectiveto"`
 }
\ No newline at end of file
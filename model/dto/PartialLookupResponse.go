@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+type PartialLookupResponse struct {
+	Number string						`json:"number" xml:"number"`
+	// Complete is set when the number can already be looked up, even if it can take more digits
+	Complete bool						`json:"complete" xml:"complete"`
+	MinRemaining int					`json:"min_remaining_digits" xml:"min_remaining_digits"`
+	MaxRemaining int					`json:"max_remaining_digits" xml:"max_remaining_digits"`
+	Countries []PartialCountry			`json:"countries" xml:"countries>country"`
+	Operators []PartialOperator			`json:"operators" xml:"operators>operator"`
+	// Normalization lists the steps taken to turn the input into the digits
+	Normalization []string				`json:"normalization_steps,omitempty" xml:"normalization_step,omitempty"`
+	// AsOf is the time the numbering plan was looked up at, when it wasn't the current one
+	AsOf *time.Time						`json:"as_of,omitempty" xml:"as_of,omitempty"`
+}
+
+type PartialCountry struct {
+	CountryIdentifier string	`json:"country_identifier" xml:"country_identifier"`
+	CountryCode string			`json:"country_code" xml:"country_code"`
+	MinRemaining int			`json:"min_remaining_digits" xml:"min_remaining_digits"`
+	MaxRemaining int			`json:"max_remaining_digits" xml:"max_remaining_digits"`
+}
+
+type PartialOperator struct {
+	CountryIdentifier string	`json:"country_identifier" xml:"country_identifier"`
+	MNO string					`json:"mno" xml:"mno"`
+	OperatorID int				`json:"operator_id,omitempty" xml:"operator_id,omitempty"`
+	Type string					`json:"number_type" xml:"number_type"`
+	MinRemaining int			`json:"min_remaining_digits" xml:"min_remaining_digits"`
+	MaxRemaining int			`json:"max_remaining_digits" xml:"max_remaining_digits"`
+}
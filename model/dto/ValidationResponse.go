@@ -0,0 +1,9 @@
+package dto
+
+type ValidationResponse struct {
+	Number string					`json:"number" xml:"number"`
+	Verdict string					`json:"verdict" xml:"verdict"`
+	Reason string					`json:"reason" xml:"reason"`
+	CountryIdentifier string		`json:"country_identifier,omitempty" xml:"country_identifier,omitempty"`
+	Result *NumberLookupResponse	`json:"result,omitempty" xml:"result,omitempty"`
+}
@@ -168,3 +168,171 @@ func NewEncryptionError(msg string) *EncryptionError{
 		Message: "Encryption error: " + msg,
 	}
 }
+
+type InvalidNumberError struct{
+	Message string
+}
+
+func(u InvalidNumberError) Error() string{
+	return u.Message
+}
+
+func NewInvalidNumberError(msg string) *InvalidNumberError{
+	return &InvalidNumberError{
+		Message: msg,
+	}
+}
+
+type InvalidFileError struct{
+	Message string
+}
+
+func(u InvalidFileError) Error() string{
+	return u.Message
+}
+
+func NewInvalidFileError(msg string) *InvalidFileError{
+	return &InvalidFileError{
+		Message: "Invalid file: " + msg,
+	}
+}
+
+type JobNotFoundError struct{
+	Message string
+}
+
+func(u JobNotFoundError) Error() string{
+	return u.Message
+}
+
+func NewJobNotFoundError() *JobNotFoundError{
+	return &JobNotFoundError{
+		Message: "Job not found",
+	}
+}
+
+type JobNotFinishedError struct{
+	Message string
+}
+
+func(u JobNotFinishedError) Error() string{
+	return u.Message
+}
+
+func NewJobNotFinishedError() *JobNotFinishedError{
+	return &JobNotFinishedError{
+		Message: "Job has not completed",
+	}
+}
+
+type JobFinishedError struct{
+	Message string
+}
+
+func(u JobFinishedError) Error() string{
+	return u.Message
+}
+
+func NewJobFinishedError() *JobFinishedError{
+	return &JobFinishedError{
+		Message: "Job has already finished",
+	}
+}
+
+type CountryNotFoundError struct{
+	Message string
+}
+
+func(u CountryNotFoundError) Error() string{
+	return u.Message
+}
+
+func NewCountryNotFoundError() *CountryNotFoundError{
+	return &CountryNotFoundError{
+		Message: "Unknown country identifier",
+	}
+}
+
+type RuleOverlapError struct{
+	Message string
+}
+
+func(u RuleOverlapError) Error() string{
+	return u.Message
+}
+
+func NewRuleOverlapError(message string) *RuleOverlapError{
+	return &RuleOverlapError{
+		Message: message,
+	}
+}
+
+type InvalidPatternError struct{
+	Message string
+}
+
+func(u InvalidPatternError) Error() string{
+	return u.Message
+}
+
+func NewInvalidPatternError(message string) *InvalidPatternError{
+	return &InvalidPatternError{
+		Message: message,
+	}
+}
+
+type OperatorNotFoundError struct{
+	Message string
+}
+
+func(u OperatorNotFoundError) Error() string{
+	return u.Message
+}
+
+func NewOperatorNotFoundError() *OperatorNotFoundError{
+	return &OperatorNotFoundError{
+		Message: "Unknown network operator",
+	}
+}
+
+type InvalidPeriodError struct{
+	Message string
+}
+
+func(u InvalidPeriodError) Error() string{
+	return u.Message
+}
+
+func NewInvalidPeriodError(message string) *InvalidPeriodError{
+	return &InvalidPeriodError{
+		Message: message,
+	}
+}
+
+type ReadOnlyPlanError struct{
+	Message string
+}
+
+func(u ReadOnlyPlanError) Error() string{
+	return u.Message
+}
+
+func NewReadOnlyPlanError() *ReadOnlyPlanError{
+	return &ReadOnlyPlanError{
+		Message: "The numbering plan is served from a snapshot file and can't be changed here",
+	}
+}
+
+type InvalidCountryDetailsError struct{
+	Message string
+}
+
+func(u InvalidCountryDetailsError) Error() string{
+	return u.Message
+}
+
+func NewInvalidCountryDetailsError(message string) *InvalidCountryDetailsError{
+	return &InvalidCountryDetailsError{
+		Message: message,
+	}
+}
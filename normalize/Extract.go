@@ -0,0 +1,87 @@
+package normalize
+
+import "unicode"
+
+const (
+	// minCandidateDigits is the fewest digits a number found in text can have
+	minCandidateDigits = 7
+	// maxCandidateDigits is the most digits a number found in text can have, enough
+	// for the longest number behind a three digit international prefix
+	maxCandidateDigits = 18
+	// maxSeparators is how many formatting characters can follow each other within a number
+	maxSeparators = 2
+)
+
+// Candidate is a stretch of text that looks like a phone number
+type Candidate struct {
+	Text string
+	// Start and End are the offsets of the candidate in the text in characters, with End excluded
+	Start int
+	End int
+}
+
+// FindCandidates returns the stretches of the text that look like phone numbers: an optional
+// "+" or opening bracket followed by 7-18 digits, which can be grouped with spaces, dashes,
+// dots, slashes and brackets. Digits that are part of a word aren't taken as a number
+func FindCandidates(text string) []Candidate{
+
+	runes := []rune(text)
+	var found []Candidate
+	for i := 0; i < len(runes); {
+		if !startsCandidate(runes, i){
+			i++
+			continue
+		}
+		j := i
+		if !isDigit(runes[j]){
+			j++
+		}
+		end, digits, separators := j, 0, 0
+		for ; j < len(runes); j++ {
+			if isDigit(runes[j]){
+				digits++
+				separators = 0
+				end = j + 1
+				continue
+			}
+			if !isSeparator(runes[j]) || separators == maxSeparators{
+				break
+			}
+			separators++
+		}
+		if digits >= minCandidateDigits && digits <= maxCandidateDigits && (end == len(runes) || !isWordRune(runes[end])){
+			found = append(found, Candidate{Text: string(runes[i:end]), Start: i, End: end})
+		}
+		i = j
+	}
+	return found
+}
+
+// startsCandidate reports whether a number can start at the position of the text
+func startsCandidate(runes []rune, i int) bool{
+
+	if i > 0 && (isWordRune(runes[i - 1]) || runes[i - 1] == '+'){
+		return false
+	}
+	if isDigit(runes[i]){
+		return true
+	}
+	return (runes[i] == '+' || runes[i] == '(') && i + 1 < len(runes) && isDigit(runes[i + 1])
+}
+
+func isDigit(r rune) bool{
+	return r >= '0' && r <= '9'
+}
+
+func isSeparator(r rune) bool{
+
+	switch r {
+	case ' ', '\u00a0', '-', '.', '/', '(', ')':
+		return true
+	}
+	return false
+}
+
+func isWordRune(r rune) bool{
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,60 @@
+package normalize
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindCandidates(t *testing.T) {
+
+	tt := []struct{
+		Name string
+		Text string
+		Expected []Candidate
+	}{
+		{
+			Name:		"Number in a sentence",
+			Text:		"call me on +389 70-123-456 after 5",
+			Expected:	[]Candidate{{Text: "+389 70-123-456", Start: 11, End: 26}},
+		},
+		{
+			Name:		"Several numbers",
+			Text:		"Office (02) 3123 456, mobile 070.123.456.",
+			Expected:	[]Candidate{
+				{Text: "(02) 3123 456", Start: 7, End: 20},
+				{Text: "070.123.456", Start: 29, End: 40},
+			},
+		},
+		{
+			Name:		"Offsets in characters",
+			Text:		"Тел: 38977123456",
+			Expected:	[]Candidate{{Text: "38977123456", Start: 5, End: 16}},
+		},
+		{
+			Name:		"Too short",
+			Text:		"room 1234, floor 5",
+		},
+		{
+			Name:		"Part of a word",
+			Text:		"order AB38977123456 and 38977123456X",
+		},
+		{
+			Name:		"Too many separators",
+			Text:		"070 -- 123 456",
+		},
+	}
+
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Act
+			found := FindCandidates(test.Text)
+
+			//Assert
+			if !reflect.DeepEqual(found, test.Expected){
+				t.Errorf("Error in TestFindCandidates:\n expected %v\n got %v", test.Expected, found)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
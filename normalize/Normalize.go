@@ -0,0 +1,187 @@
+// Package normalize turns phone numbers the way users type them into MSISDNs in
+// E.164 format without the leading plus sign
+package normalize
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/robesmi/MSISDNApp/model/errs"
+)
+
+// Steps reported in Result.Steps, in the order they can be applied
+const (
+	StepVanityLetters = "vanity_letters"
+	StepFormattingRemoved = "formatting_removed"
+	StepPlusPrefix = "plus_prefix"
+	StepInternationalPrefix = "international_prefix"
+	StepTrunkPrefix = "trunk_prefix"
+	StepCallingCodeAdded = "calling_code_added"
+	StepLeadingZerosRemoved = "leading_zeros_removed"
+)
+
+// DefaultInternationalPrefixes are recognized when no region is given
+var DefaultInternationalPrefixes = []string{"00", "011"}
+
+// Region holds the dialing rules of a country needed to read numbers written in
+// its national format or dialed from it
+type Region struct {
+	// CallingCode is the country calling code, e.g. "389"
+	CallingCode string
+	// TrunkPrefix is dialed in front of national numbers, e.g. "0"
+	TrunkPrefix string
+	// InternationalPrefixes are dialed in front of a calling code to call abroad, e.g. "00"
+	InternationalPrefixes []string
+}
+
+type Result struct {
+	// Number is the normalized MSISDN
+	Number string
+	// Steps lists what had to be done to the input to get the number
+	Steps []string
+}
+
+// keypad maps letters to the digits they share a key with
+var keypad = map[rune]rune{
+	'A': '2', 'B': '2', 'C': '2',
+	'D': '3', 'E': '3', 'F': '3',
+	'G': '4', 'H': '4', 'I': '4',
+	'J': '5', 'K': '5', 'L': '5',
+	'M': '6', 'N': '6', 'O': '6',
+	'P': '7', 'Q': '7', 'R': '7', 'S': '7',
+	'T': '8', 'U': '8', 'V': '8',
+	'W': '9', 'X': '9', 'Y': '9', 'Z': '9',
+}
+
+// Normalize takes a number as typed by a user and an optional region and returns the
+// MSISDN along with the steps applied to get it, or an InvalidNumberError.
+// Numbers starting with "+" or an international call prefix are read as international,
+// the rest are read as national numbers of the region when one is given
+func Normalize(input string, region *Region) (*Result, error){
+
+	result, err := read(input, region)
+	if err != nil{
+		return nil, err
+	}
+	if !validLength(result.Number){
+		return nil, errs.NewInvalidNumberError("The MSISDN must only contain digits and be 7-15 digits long")
+	}
+	return result, nil
+}
+
+// NormalizePartial reads the start of a number still being typed the way Normalize reads
+// a whole one, without requiring it to be long enough yet
+func NormalizePartial(input string, region *Region) (*Result, error){
+
+	result, err := read(input, region)
+	if err != nil{
+		return nil, err
+	}
+	if result.Number == "" || len(result.Number) > 15{
+		return nil, errs.NewInvalidNumberError("The MSISDN must only contain digits and be at most 15 digits long")
+	}
+	return result, nil
+}
+
+// read turns the input into digits, removing formatting and the dialed prefixes
+func read(input string, region *Region) (*Result, error){
+
+	var result Result
+	input = strings.TrimSpace(input)
+	if input == ""{
+		return nil, errs.NewInvalidNumberError("Please enter a MSISDN")
+	}
+
+	// Letters are only read as vanity digits when the input starts like a number,
+	// so that text isn't mistaken for one
+	vanity := false
+	for _, r := range input{
+		if unicode.IsLetter(r){
+			vanity = false
+			break
+		}
+		if unicode.IsDigit(r) || r == '+'{
+			vanity = true
+			break
+		}
+	}
+
+	plus := false
+	var digits strings.Builder
+	for _, r := range input{
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' && digits.Len() == 0 && !plus:
+			plus = true
+		case vanity && keypad[unicode.ToUpper(r)] != 0:
+			digits.WriteRune(keypad[unicode.ToUpper(r)])
+			result.addStep(StepVanityLetters)
+		default:
+			result.addStep(StepFormattingRemoved)
+		}
+	}
+	number := digits.String()
+
+	if plus{
+		result.addStep(StepPlusPrefix)
+	}else{
+		number = result.readDialed(number, region)
+	}
+	result.Number = number
+	return &result, nil
+}
+
+// readDialed removes the prefixes dialed in front of a number typed without a plus sign
+func (res *Result) readDialed(number string, region *Region) string{
+
+	prefixes := DefaultInternationalPrefixes
+	if region != nil && len(region.InternationalPrefixes) != 0{
+		prefixes = region.InternationalPrefixes
+	}
+	for _, prefix := range prefixes{
+		if prefix != "" && strings.HasPrefix(number, prefix){
+			res.addStep(StepInternationalPrefix)
+			return number[len(prefix):]
+		}
+	}
+
+	if region == nil{
+		// Without a region there's no telling which country a national number belongs to,
+		// so only the leading zeroes are dropped
+		trimmed := strings.TrimLeft(number, "0")
+		if trimmed != number{
+			res.addStep(StepLeadingZerosRemoved)
+		}
+		return trimmed
+	}
+
+	if region.TrunkPrefix != "" && strings.HasPrefix(number, region.TrunkPrefix){
+		res.addStep(StepTrunkPrefix)
+		res.addStep(StepCallingCodeAdded)
+		return region.CallingCode + number[len(region.TrunkPrefix):]
+	}
+	// A number already starting with the calling code is taken as international when
+	// national numbers of the region are dialed with a trunk prefix, or when adding
+	// the calling code would make it too long
+	if strings.HasPrefix(number, region.CallingCode) &&
+		(region.TrunkPrefix != "" || !validLength(region.CallingCode + number)){
+		return number
+	}
+	res.addStep(StepCallingCodeAdded)
+	return region.CallingCode + number
+}
+
+func (res *Result) addStep(step string){
+
+	for _, s := range res.Steps{
+		if s == step{
+			return
+		}
+	}
+	res.Steps = append(res.Steps, step)
+}
+
+func validLength(number string) bool{
+	return len(number) >= 7 && len(number) <= 15
+}
@@ -0,0 +1,207 @@
+package normalize
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/robesmi/MSISDNApp/model/errs"
+)
+
+var mk = Region{CallingCode: "389", TrunkPrefix: "0", InternationalPrefixes: []string{"00"}}
+var us = Region{CallingCode: "1", TrunkPrefix: "1", InternationalPrefixes: []string{"011"}}
+var pl = Region{CallingCode: "48", InternationalPrefixes: []string{"00"}}
+
+func TestNormalize(t *testing.T) {
+
+	tt := []struct{
+		Name string
+		Input string
+		Region *Region
+		ExpectedNumber string
+		ExpectedSteps []string
+	}{
+		{
+			Name:			"Plain international number",
+			Input:			"38977123456",
+			ExpectedNumber:	"38977123456",
+		},
+		{
+			Name:			"Plus prefix with formatting",
+			Input:			"+389 (77) 123-456",
+			ExpectedNumber:	"38977123456",
+			ExpectedSteps:	[]string{StepFormattingRemoved, StepPlusPrefix},
+		},
+		{
+			Name:			"Double zero prefix",
+			Input:			"0038977123456",
+			ExpectedNumber:	"38977123456",
+			ExpectedSteps:	[]string{StepInternationalPrefix},
+		},
+		{
+			Name:			"North American international prefix",
+			Input:			"011 389 77 123 456",
+			ExpectedNumber:	"38977123456",
+			ExpectedSteps:	[]string{StepFormattingRemoved, StepInternationalPrefix},
+		},
+		{
+			Name:			"National number with region",
+			Input:			"070 123 456",
+			Region:			&mk,
+			ExpectedNumber:	"38970123456",
+			ExpectedSteps:	[]string{StepFormattingRemoved, StepTrunkPrefix, StepCallingCodeAdded},
+		},
+		{
+			Name:			"International number with region",
+			Input:			"00 48 510 123 456",
+			Region:			&mk,
+			ExpectedNumber:	"48510123456",
+			ExpectedSteps:	[]string{StepFormattingRemoved, StepInternationalPrefix},
+		},
+		{
+			Name:			"Region without a trunk prefix",
+			Input:			"510123456",
+			Region:			&pl,
+			ExpectedNumber:	"48510123456",
+			ExpectedSteps:	[]string{StepCallingCodeAdded},
+		},
+		{
+			Name:			"Number with calling code and region",
+			Input:			"38970123456",
+			Region:			&mk,
+			ExpectedNumber:	"38970123456",
+		},
+		{
+			Name:			"Vanity number",
+			Input:			"1-800-FLOWERS",
+			Region:			&us,
+			ExpectedNumber:	"18003569377",
+			ExpectedSteps:	[]string{StepFormattingRemoved, StepVanityLetters, StepTrunkPrefix, StepCallingCodeAdded},
+		},
+		{
+			Name:			"Leading zeroes without region",
+			Input:			"077123456",
+			ExpectedNumber:	"77123456",
+			ExpectedSteps:	[]string{StepLeadingZerosRemoved},
+		},
+	}
+
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Act
+			res, err := Normalize(test.Input, test.Region)
+
+			//Assert
+			if err != nil{
+				t.Fatalf("Error in TestNormalize:\n expected %s\n got %s", "nil", err)
+			}
+			if res.Number != test.ExpectedNumber{
+				t.Errorf("Error in TestNormalize:\n expected %s\n got %s", test.ExpectedNumber, res.Number)
+			}
+			if !reflect.DeepEqual(res.Steps, test.ExpectedSteps){
+				t.Errorf("Error in TestNormalize:\n expected steps %v\n got %v", test.ExpectedSteps, res.Steps)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
+
+func TestNormalizeInvalid(t *testing.T) {
+
+	tt := []struct{
+		Name string
+		Input string
+	}{
+		{
+			Name:	"Empty input",
+			Input:	"   ",
+		},
+		{
+			Name:	"Text",
+			Input:	"lorem ipsum",
+		},
+		{
+			Name:	"Too short",
+			Input:	"+389 77",
+		},
+		{
+			Name:	"Too long",
+			Input:	"237128937019023213123121232",
+		},
+	}
+
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Act
+			_, err := Normalize(test.Input, nil)
+
+			//Assert
+			if _, ok := err.(*errs.InvalidNumberError); !ok{
+				t.Errorf("Error in TestNormalizeInvalid:\n expected %s\n got %v", "InvalidNumberError", err)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
+
+func TestNormalizePartial(t *testing.T) {
+
+	tt := []struct{
+		Name string
+		Input string
+		Region *Region
+		ExpectedNumber string
+		ExpectedError bool
+	}{
+		{
+			Name:			"Start of an international number",
+			Input:			"+389 7",
+			ExpectedNumber:	"3897",
+		},
+		{
+			Name:			"Start of a national number",
+			Input:			"07",
+			Region:			&mk,
+			ExpectedNumber:	"3897",
+		},
+		{
+			Name:			"Complete number",
+			Input:			"38977123456",
+			ExpectedNumber:	"38977123456",
+		},
+		{
+			Name:			"International prefix only",
+			Input:			"00",
+			ExpectedError:	true,
+		},
+		{
+			Name:			"Too long",
+			Input:			"2371289370190232",
+			ExpectedError:	true,
+		},
+	}
+
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Act
+			res, err := NormalizePartial(test.Input, test.Region)
+
+			//Assert
+			if test.ExpectedError{
+				if _, ok := err.(*errs.InvalidNumberError); !ok{
+					t.Errorf("Error in TestNormalizePartial:\n expected %s\n got %v", "InvalidNumberError", err)
+				}
+				return
+			}
+			if err != nil{
+				t.Fatalf("Error in TestNormalizePartial:\n expected %s\n got %v", test.ExpectedNumber, err)
+			}
+			if res.Number != test.ExpectedNumber{
+				t.Errorf("Error in TestNormalizePartial:\n expected %s\n got %s", test.ExpectedNumber, res.Number)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
@@ -0,0 +1,110 @@
+package numplan
+
+import (
+	"fmt"
+	"regexp/syntax"
+
+	"github.com/robesmi/MSISDNApp/model/errs"
+)
+
+// maxPatternLength is the longest pattern accepted for a rule
+const maxPatternLength = 200
+
+// ValidatePattern checks that a rule pattern is written in the dialect shared by Go's
+// RE2 and the ICU engine behind MySQL's RLIKE, so that both evaluate it the same way,
+// returning an InvalidPatternError if it isn't. The dialect only matches digits:
+//   - digits, \d and classes of digits like [0-46-9]
+//   - concatenation, alternation with | and groups, capturing or not
+//   - the ? * + {n} {n,} and {n,m} repetitions, with counts up to 15
+//   - ^ and $ anchoring the whole number
+//
+// Lookarounds, backreferences, named groups, line anchors, word boundaries, any
+// character matching and repetitions nested in other repetitions are rejected, the
+// last since backtracking engines like ICU can take exponential time on them.
+// Ranges a rule shouldn't match go in its exclusion pattern instead of a lookahead
+func ValidatePattern(expr string) (error){
+
+	if expr == ""{
+		return errs.NewInvalidPatternError("The pattern can't be empty")
+	}
+	if len(expr) > maxPatternLength{
+		return errs.NewInvalidPatternError(fmt.Sprintf("The pattern can't be longer than %d characters", maxPatternLength))
+	}
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil{
+		return errs.NewInvalidPatternError("Invalid pattern " + expr + ": " + err.Error())
+	}
+	if err := checkDialect(re, false); err != nil{
+		return errs.NewInvalidPatternError("Unsupported pattern " + expr + ": " + err.Error())
+	}
+	return nil
+}
+
+// CheckCallingCode verifies that every number the pattern of a country matches starts
+// with the country's calling code. Countries sharing a calling code are told apart by the
+// digits after it, so +1 246 is Barbados with the calling code 1 rather than a code of its own
+func CheckCallingCode(expr string, callingCode string) (error){
+
+	pattern, err := CompilePattern(expr)
+	if err != nil{
+		return errs.NewInvalidPatternError("Invalid pattern " + expr + ": " + err.Error())
+	}
+	prefixes := pattern.Prefixes(len(callingCode), indexWidth)
+	if len(prefixes) != 1 || prefixes[0] != callingCode{
+		return errs.NewInvalidPatternError(fmt.Sprintf("The pattern %s must only match numbers starting with the country code %s", expr, callingCode))
+	}
+	return nil
+}
+
+// checkDialect walks the parsed pattern, where repeated is set inside a repetition
+// that can match more than once
+func checkDialect(re *syntax.Regexp, repeated bool) (error){
+
+	switch re.Op {
+	case syntax.OpEmptyMatch, syntax.OpBeginText, syntax.OpEndText:
+	case syntax.OpLiteral:
+		for _, r := range re.Rune{
+			if r < '0' || r > '9'{
+				return fmt.Errorf("%q is not a digit", r)
+			}
+		}
+	case syntax.OpCharClass:
+		for i := 0; i < len(re.Rune); i += 2 {
+			if re.Rune[i] < '0' || re.Rune[i+1] > '9'{
+				return fmt.Errorf("%s matches more than digits", re)
+			}
+		}
+	case syntax.OpCapture:
+		if re.Name != ""{
+			return fmt.Errorf("named group %s", re.Name)
+		}
+	case syntax.OpConcat, syntax.OpAlternate:
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		if re.Op == syntax.OpRepeat && (re.Min > maxNumberLength || re.Max > maxNumberLength){
+			return fmt.Errorf("%s repeats more than %d times", re, maxNumberLength)
+		}
+		if repeated{
+			return fmt.Errorf("%s is a repetition inside another repetition", re)
+		}
+		for _, sub := range re.Sub{
+			if err := checkDialect(sub, repeated || re.Op != syntax.OpQuest); err != nil{
+				return err
+			}
+		}
+		return nil
+	case syntax.OpBeginLine, syntax.OpEndLine:
+		return fmt.Errorf("line anchors aren't supported, use ^ and $")
+	case syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		return fmt.Errorf("word boundaries aren't supported")
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return fmt.Errorf(". matches more than digits, use [0-9]")
+	default:
+		return fmt.Errorf("%s is not supported", re)
+	}
+	for _, sub := range re.Sub{
+		if err := checkDialect(sub, repeated); err != nil{
+			return err
+		}
+	}
+	return nil
+}
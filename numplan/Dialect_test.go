@@ -0,0 +1,119 @@
+package numplan
+
+import (
+	"testing"
+
+	"github.com/robesmi/MSISDNApp/model/errs"
+)
+
+func TestValidatePattern(t *testing.T) {
+
+	tt := []struct{
+		Name string
+		Pattern string
+		ExpectsValid bool
+	}{
+		{
+			Name:			"Anchored range",
+			Pattern:		"^389[0-9]{8}$",
+			ExpectsValid:	true,
+		},
+		{
+			Name:			"Alternation and digit class",
+			Pattern:		"^(23\\d|24\\d|25[0-4])[0-9]{7}$",
+			ExpectsValid:	true,
+		},
+		{
+			Name:			"Variable length",
+			Pattern:		"^43[0-9]{6,13}$",
+			ExpectsValid:	true,
+		},
+		{
+			Name:			"Optional digit",
+			Pattern:		"^1(2?3)$",
+			ExpectsValid:	true,
+		},
+		{
+			Name:		"Lookahead",
+			Pattern:	"(?!^61(2|4|7|9)[0-9]{5}$)^611[0-9]{6}$",
+		},
+		{
+			Name:		"Nested repetition",
+			Pattern:	"^([0-9]{2})*$",
+		},
+		{
+			Name:		"Non digit class",
+			Pattern:	"^[^5][0-9]{7}$",
+		},
+		{
+			Name:		"Letters",
+			Pattern:	"^abc$",
+		},
+		{
+			Name:		"Word boundary",
+			Pattern:	"\\b389[0-9]{8}",
+		},
+		{
+			Name:		"Repeat count above a number's length",
+			Pattern:	"^[0-9]{20}$",
+		},
+		{
+			Name:		"Named group",
+			Pattern:	"^(?P<ndc>77)[0-9]{6}$",
+		},
+		{
+			Name:		"Empty",
+			Pattern:	"",
+		},
+	}
+
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Act
+			err := ValidatePattern(test.Pattern)
+
+			//Assert
+			if test.ExpectsValid && err != nil{
+				t.Errorf("Error in TestValidatePattern:\n expected %s\n got %s", "nil", err)
+			}
+			if _, ok := err.(*errs.InvalidPatternError); !test.ExpectsValid && !ok{
+				t.Errorf("Error in TestValidatePattern:\n expected %s\n got %v", "InvalidPatternError", err)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
+
+func TestCheckCallingCode(t *testing.T) {
+
+	tt := []struct{
+		Name string
+		Pattern string
+		CallingCode string
+		ExpectsValid bool
+	}{
+		{Name: "Own calling code", Pattern: "^389[0-9]{8}$", CallingCode: "389", ExpectsValid: true},
+		{Name: "Shared calling code with area code", Pattern: "^1246[0-9]{7}$", CallingCode: "1", ExpectsValid: true},
+		{Name: "Area code as calling code", Pattern: "^1[2-9][0-9]{9}$", CallingCode: "1246"},
+		{Name: "Other calling code", Pattern: "^48[0-9]{9}$", CallingCode: "389"},
+		{Name: "Unanchored", Pattern: "389[0-9]{8}", CallingCode: "389"},
+	}
+
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Act
+			err := CheckCallingCode(test.Pattern, test.CallingCode)
+
+			//Assert
+			if test.ExpectsValid && err != nil{
+				t.Errorf("Error in TestCheckCallingCode:\n expected %s\n got %s", "nil", err)
+			}
+			if _, ok := err.(*errs.InvalidPatternError); !test.ExpectsValid && !ok{
+				t.Errorf("Error in TestCheckCallingCode:\n expected %s\n got %v", "InvalidPatternError", err)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
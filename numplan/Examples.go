@@ -0,0 +1,311 @@
+package numplan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robesmi/MSISDNApp/model"
+)
+
+// track is a pattern a generated number is checked against. Country patterns see the
+// whole number and range patterns only the significant number after the calling code
+type track struct {
+	pattern *Pattern
+	national bool
+}
+
+// search returns the shortest number starting with the calling code, of at most
+// maxNumberLength digits, that every accepted track matches and no rejected track does
+func search(callingCode string, accept []track, reject []track) (string, bool){
+
+	type node struct {
+		number string
+		accepted []ruleState
+		rejected []ruleState
+	}
+
+	accepted, rejected := afterCallingCode(callingCode, accept), afterCallingCode(callingCode, reject)
+	for _, s := range accepted{
+		if s.dead(){
+			return "", false
+		}
+	}
+
+	seen := make(map[string]bool)
+	frontier := []node{{callingCode, accepted, rejected}}
+	for length := len(callingCode); len(frontier) > 0; length++ {
+		var next []node
+		for _, n := range frontier{
+			if length > len(callingCode) && allAccept(n.accepted) && !anyAccepts(n.rejected){
+				return n.number, true
+			}
+			if length == maxNumberLength{
+				continue
+			}
+			for d := '0'; d <= '9'; d++ {
+				child := node{n.number + string(d), make([]ruleState, len(accept)), make([]ruleState, len(reject))}
+				dead := false
+				for i, t := range accept{
+					child.accepted[i] = t.pattern.ruleStep(n.accepted[i], d)
+					dead = dead || child.accepted[i].dead()
+				}
+				if dead{
+					continue
+				}
+				for i, t := range reject{
+					child.rejected[i] = t.pattern.ruleStep(n.rejected[i], d)
+				}
+				// Reaching states already seen can't lead to a shorter number
+				key := stateKey(child.accepted, child.rejected)
+				if seen[key]{
+					continue
+				}
+				seen[key] = true
+				next = append(next, child)
+			}
+		}
+		frontier = next
+	}
+	return "", false
+}
+
+// afterCallingCode returns the states of the tracks once the calling code has been read
+func afterCallingCode(callingCode string, tracks []track) []ruleState{
+
+	states := make([]ruleState, len(tracks))
+	for i, t := range tracks{
+		states[i] = t.pattern.ruleStart()
+		if t.national{
+			continue
+		}
+		for _, d := range callingCode{
+			states[i] = t.pattern.ruleStep(states[i], d)
+		}
+	}
+	return states
+}
+
+func allAccept(states []ruleState) bool{
+
+	for _, s := range states{
+		if !s.accepts(){
+			return false
+		}
+	}
+	return true
+}
+
+func anyAccepts(states []ruleState) bool{
+
+	for _, s := range states{
+		if s.accepts(){
+			return true
+		}
+	}
+	return false
+}
+
+func stateKey(accepted []ruleState, rejected []ruleState) string{
+
+	var key strings.Builder
+	for _, s := range accepted{
+		key.WriteString(s.key())
+		key.WriteByte('|')
+	}
+	key.WriteByte('#')
+	for _, s := range rejected{
+		// A rejected rule that can no longer match doesn't constrain the number
+		if !s.dead(){
+			key.WriteString(s.key())
+		}
+		key.WriteByte('|')
+	}
+	return key.String()
+}
+
+// CheckRules generates an example number for every rule in effect at the time that
+// should resolve to it, and reports the rules that can't be reached: patterns the index
+// can't compile, rules matching no number, rules whose every number resolves to a higher
+// ranked rule, and ranges that match numbers their country's pattern doesn't
+func CheckRules(countries []model.Country, operators []model.MobileOperator, at time.Time) []model.RuleCheck{
+
+	plan := NewPlan(countries, operators)
+	unsupported := make(map[string]bool, len(plan.unsupported))
+	for _, expr := range plan.unsupported{
+		unsupported[expr] = true
+	}
+	var checks []model.RuleCheck
+
+	// Rules are checked in the order they rank in, so higher ranked ones come first
+	var effective []countryRule
+	for _, c := range countries{
+		if c.EffectiveAt(at) && unsupported[c.CountryNumberFormat]{
+			checks = append(checks, unsupportedCheck(model.RuleKindCountry, c.CountryIdentifier, c.CountryNumberFormat, c.Priority, ""))
+		}
+	}
+	for _, rule := range plan.countries{
+		if rule.country.EffectiveAt(at){
+			effective = append(effective, rule)
+		}
+	}
+	for i, rule := range effective{
+		checks = append(checks, plan.checkCountry(rule, effective[:i], at))
+	}
+
+	for _, o := range operators{
+		if o.EffectiveAt(at) && unsupported[o.PrefixFormat]{
+			checks = append(checks, unsupportedCheck(model.RuleKindOperator, o.CountryIdentifier, o.PrefixFormat, o.Priority, o.MNO))
+		}
+	}
+	for _, c := range sortedIdentifiers(plan.operators){
+		var countryRules []int
+		for i, rule := range effective{
+			if rule.country.CountryIdentifier == c{
+				countryRules = append(countryRules, i)
+			}
+		}
+		var ranges []operatorRule
+		for _, rule := range plan.operators[c].rules{
+			if rule.operator.EffectiveAt(at){
+				ranges = append(ranges, rule)
+			}
+		}
+		for i, rule := range ranges{
+			checks = append(checks, plan.checkOperator(rule, ranges[:i], effective, countryRules, at))
+		}
+	}
+	return checks
+}
+
+func unsupportedCheck(kind string, ci string, expr string, priority int, mno string) model.RuleCheck{
+	return model.RuleCheck{
+		Kind: kind,
+		CountryIdentifier: ci,
+		Pattern: expr,
+		Priority: priority,
+		MNO: mno,
+		Problems: []string{model.RuleProblemUnsupported},
+		Detail: "The pattern can't be compiled by the lookup index",
+	}
+}
+
+func (p *Plan) checkCountry(rule countryRule, higher []countryRule, at time.Time) model.RuleCheck{
+
+	check := model.RuleCheck{
+		Kind: model.RuleKindCountry,
+		CountryIdentifier: rule.country.CountryIdentifier,
+		Pattern: rule.country.CountryNumberFormat,
+		Priority: rule.country.Priority,
+	}
+	own := []track{{pattern: rule.pattern}}
+	example, ok := search("", own, nil)
+	if !ok{
+		check.Problems = append(check.Problems, model.RuleProblemUnreachable)
+		check.Detail = fmt.Sprintf("The pattern matches no number of up to %d digits", maxNumberLength)
+		return check
+	}
+	check.Example = example
+
+	var others []track
+	for _, h := range higher{
+		others = append(others, track{pattern: h.pattern})
+	}
+	if resolving, ok := search("", own, others); ok{
+		check.Example = resolving
+		return check
+	}
+	check.Problems = append(check.Problems, model.RuleProblemShadowed)
+	if winner, found := p.LookupCountry(example, at); found{
+		check.Winner = winner.CountryNumberFormat
+	}
+	check.Detail = "Every number the rule matches resolves to a higher ranked country"
+	return check
+}
+
+// checkOperator checks a range against the higher ranked ranges of its country and the
+// country rules in effect, of which countryRules are the ones of the range's country
+func (p *Plan) checkOperator(rule operatorRule, higher []operatorRule, countries []countryRule, countryRules []int, at time.Time) model.RuleCheck{
+
+	ci := rule.operator.CountryIdentifier
+	check := model.RuleCheck{
+		Kind: model.RuleKindOperator,
+		CountryIdentifier: ci,
+		Pattern: rule.operator.PrefixFormat,
+		Priority: rule.operator.Priority,
+		MNO: rule.operator.MNO,
+	}
+	own := track{pattern: rule.pattern, national: true}
+	if len(countryRules) == 0{
+		check.Problems = append(check.Problems, model.RuleProblemUnreachable)
+		check.Detail = "The country of the range has no rule in effect"
+		return check
+	}
+
+	var details []string
+	var countryTracks []track
+	for _, i := range countryRules{
+		countryTracks = append(countryTracks, track{pattern: countries[i].pattern})
+	}
+	code := countries[countryRules[0]].country.CountryCode
+	if outside, ok := search(code, []track{own}, countryTracks); ok{
+		check.Problems = append(check.Problems, model.RuleProblemOutsideCountry)
+		details = append(details, fmt.Sprintf("%s matches the range but not the pattern of %s", outside, ci))
+	}
+
+	var reachable, resolving string
+	for _, i := range countryRules{
+		country := countries[i]
+		code := country.country.CountryCode
+		accept := []track{{pattern: country.pattern}, own}
+		var higherCountries []track
+		for _, h := range countries[:i]{
+			higherCountries = append(higherCountries, track{pattern: h.pattern})
+		}
+		example, ok := search(code, accept, higherCountries)
+		if !ok{
+			continue
+		}
+		if reachable == ""{
+			reachable = example
+		}
+		others := higherCountries
+		for _, h := range higher{
+			others = append(others, track{pattern: h.pattern, national: true})
+		}
+		if example, ok := search(code, accept, others); ok{
+			resolving = example
+			break
+		}
+	}
+
+	switch {
+	case resolving != "":
+		check.Example = resolving
+	case reachable != "":
+		check.Example = reachable
+		check.Problems = append(check.Problems, model.RuleProblemShadowed)
+		if country, found := p.LookupCountry(reachable, at); found{
+			if winner, found := p.LookupOperator(ci, reachable[len(country.CountryCode):], at); found{
+				check.Winner = winner.PrefixFormat
+			}
+		}
+		details = append(details, "Every number of the range resolves to a higher ranked range")
+	default:
+		check.Problems = append(check.Problems, model.RuleProblemUnreachable)
+		details = append(details, "No number of the range resolves to its country")
+	}
+	check.Detail = strings.Join(details, ". ")
+	return check
+}
+
+func sortedIdentifiers(operators map[string]*operatorTable) []string{
+
+	identifiers := make([]string, 0, len(operators))
+	for ci := range operators{
+		identifiers = append(identifiers, ci)
+	}
+	sort.Strings(identifiers)
+	return identifiers
+}
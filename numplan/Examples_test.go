@@ -0,0 +1,78 @@
+package numplan
+
+import (
+	"testing"
+
+	"github.com/robesmi/MSISDNApp/model"
+)
+
+func TestCheckRules(t *testing.T) {
+
+	//Arrange
+	countries := []model.Country{
+		{CountryNumberFormat: "^389[0-9]{8}$", CountryCode: "389", CountryIdentifier: "mk"},
+		{CountryNumberFormat: "^3897[0-9]{7}$", CountryCode: "389", CountryIdentifier: "xk", Priority: -1},
+		{CountryNumberFormat: "^48[0-9]{20}$", CountryCode: "48", CountryIdentifier: "pl"},
+	}
+	operators := []model.MobileOperator{
+		{CountryIdentifier: "mk", PrefixFormat: "^7[0-9]{7}$", MNO: "Telekom"},
+		{CountryIdentifier: "mk", PrefixFormat: "^77[0-9]{6}$", MNO: "A1"},
+		{CountryIdentifier: "mk", PrefixFormat: "^77[0-9]{6}$", MNO: "Lycamobile", Priority: -1},
+		{CountryIdentifier: "mk", PrefixFormat: "^75[0-9]{6,7}$", MNO: "Lyca", Priority: 1},
+		{CountryIdentifier: "mk", PrefixFormat: "^76[0-9]{7}$", MNO: "Vip", Priority: 1},
+	}
+	expected := map[string][]string{
+		"mk ^389[0-9]{8}$": nil,
+		"xk ^3897[0-9]{7}$": {model.RuleProblemShadowed},
+		"pl ^48[0-9]{20}$": {model.RuleProblemUnreachable},
+		"Telekom ^7[0-9]{7}$": nil,
+		"A1 ^77[0-9]{6}$": nil,
+		"Lycamobile ^77[0-9]{6}$": {model.RuleProblemShadowed},
+		"Lyca ^75[0-9]{6,7}$": {model.RuleProblemOutsideCountry},
+		"Vip ^76[0-9]{7}$": {model.RuleProblemOutsideCountry, model.RuleProblemUnreachable},
+	}
+
+	//Act
+	checks := CheckRules(countries, operators, now)
+
+	//Assert
+	if len(checks) != len(expected){
+		t.Fatalf("Error in TestCheckRules:\n expected %d checks\n got %d", len(expected), len(checks))
+	}
+	for _, check := range checks{
+		name := check.CountryIdentifier + " " + check.Pattern
+		if check.Kind == model.RuleKindOperator{
+			name = check.MNO + " " + check.Pattern
+		}
+		problems, ok := expected[name]
+		if !ok || len(problems) != len(check.Problems){
+			t.Errorf("Error in TestCheckRules:\n expected %s to have problems %v\n got %v", name, problems, check.Problems)
+			continue
+		}
+		for i := range problems{
+			if problems[i] != check.Problems[i]{
+				t.Errorf("Error in TestCheckRules:\n expected %s to have problems %v\n got %v", name, problems, check.Problems)
+			}
+		}
+		if len(problems) == 0 && check.Example == ""{
+			t.Errorf("Error in TestCheckRules:\n expected an example number for %s\n got none", name)
+		}
+	}
+}
+
+func TestCheckRulesExamplesResolve(t *testing.T) {
+
+	//Arrange
+	plan := NewPlan(sharedCodeCountries, nil)
+
+	//Act
+	checks := CheckRules(sharedCodeCountries, nil, now)
+
+	//Assert
+	for _, check := range checks{
+		country, found := plan.LookupCountry(check.Example, now)
+		if len(check.Problems) != 0 || !found || country.CountryIdentifier != check.CountryIdentifier{
+			t.Errorf("Error in TestCheckRulesExamplesResolve:\n expected %s to resolve to %s\n got %v, %v", check.Example, check.CountryIdentifier, country, check.Problems)
+		}
+	}
+}
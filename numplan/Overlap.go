@@ -0,0 +1,267 @@
+package numplan
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/robesmi/MSISDNApp/model"
+)
+
+// maxNumberLength is the most digits a number can have, which bounds the search for
+// a number matched by two patterns
+const maxNumberLength = 15
+
+// Overlap returns the shortest number matched by both patterns, if there is one
+// with at most maxNumberLength digits
+func Overlap(a *Pattern, b *Pattern) (string, bool){
+
+	type node struct {
+		number string
+		a, b ruleState
+	}
+
+	seen := make(map[string]bool)
+	frontier := []node{{"", a.ruleStart(), b.ruleStart()}}
+	for depth := 0; len(frontier) > 0; depth++ {
+		var next []node
+		for _, n := range frontier{
+			if depth > 0 && n.a.accepts() && n.b.accepts(){
+				return n.number, true
+			}
+			if depth == maxNumberLength{
+				continue
+			}
+			for d := '0'; d <= '9'; d++ {
+				na, nb := a.ruleStep(n.a, d), b.ruleStep(n.b, d)
+				if na.dead() || nb.dead(){
+					continue
+				}
+				// Reaching a pair of states already seen can't lead to a shorter number
+				key := na.key() + "|" + nb.key()
+				if seen[key]{
+					continue
+				}
+				seen[key] = true
+				next = append(next, node{n.number + string(d), na, nb})
+			}
+		}
+		frontier = next
+	}
+	return "", false
+}
+
+// ruleState follows a pattern together with the ranges it excludes
+type ruleState struct {
+	main state
+	excluded state
+}
+
+func (p *Pattern) ruleStart() ruleState{
+
+	s := ruleState{main: p.start()}
+	if p.excluded != nil{
+		s.excluded = p.excluded.start()
+	}
+	return s
+}
+
+func (p *Pattern) ruleStep(s ruleState, r rune) ruleState{
+
+	next := ruleState{main: p.step(s.main, r)}
+	if p.excluded != nil{
+		next.excluded = p.excluded.step(s.excluded, r)
+	}
+	return next
+}
+
+// accepts reports whether the rule matches when the input ends in this state
+func (s ruleState) accepts() bool{
+	return s.main.accepts() && !s.excluded.accepts()
+}
+
+// dead reports whether no continuation of the input can match the rule
+func (s ruleState) dead() bool{
+	return s.main.dead() || s.excluded.matched
+}
+
+func (s ruleState) key() string{
+	return string(s.appendKey(nil))
+}
+
+// appendKey appends the key of the state to the buffer, so callers building many
+// keys can reuse one
+func (s ruleState) appendKey(key []byte) []byte{
+
+	key = s.main.appendKey(key)
+	key = append(key, '/')
+	return s.excluded.appendKey(key)
+}
+
+// accepts reports whether the pattern matches when the input ends in this state
+func (s state) accepts() bool{
+	return s.matched || s.final
+}
+
+func (s state) key() string{
+	return string(s.appendKey(nil))
+}
+
+func (s state) appendKey(key []byte) []byte{
+
+	pcs := append([]uint32(nil), s.pcs...)
+	sort.Slice(pcs, func(i, j int) bool { return pcs[i] < pcs[j] })
+	key = strconv.AppendBool(key, s.matched)
+	key = strconv.AppendBool(key, s.final)
+	for _, pc := range pcs{
+		key = append(key, ',')
+		key = strconv.AppendUint(key, uint64(pc), 10)
+	}
+	return key
+}
+
+// CountryOverlaps returns the existing countries whose rules match a number the rule of
+// the new country matches during a period both are in effect, or an error if the new
+// rule can't be compiled. Existing rules that can't be compiled are skipped
+func CountryOverlaps(country model.Country, existing []model.Country) ([]model.RuleOverlap, error){
+
+	pattern, err := CompileRule(country.CountryNumberFormat, country.ExcludedFormat)
+	if err != nil{
+		return nil, err
+	}
+	var overlaps []model.RuleOverlap
+	for _, other := range existing{
+		if !model.PeriodsIntersect(country.EffectiveFrom, country.EffectiveTo, other.EffectiveFrom, other.EffectiveTo){
+			continue
+		}
+		otherPattern, err := CompileRule(other.CountryNumberFormat, other.ExcludedFormat)
+		if err != nil{
+			continue
+		}
+		if example, ok := Overlap(pattern, otherPattern); ok{
+			overlaps = append(overlaps, model.RuleOverlap{
+				Kind: model.RuleKindCountry,
+				First: country.CountryNumberFormat,
+				FirstPriority: country.Priority,
+				Second: other.CountryNumberFormat,
+				SecondPriority: other.Priority,
+				Example: example,
+			})
+		}
+	}
+	return overlaps, nil
+}
+
+// OperatorOverlaps returns the existing operators of the same country whose rules match
+// a number the rule of the new operator matches during a period both are in effect, or
+// an error if the new rule can't be compiled. Existing rules that can't be compiled are
+// skipped
+func OperatorOverlaps(operator model.MobileOperator, existing []model.MobileOperator) ([]model.RuleOverlap, error){
+
+	pattern, err := CompileRule(operator.PrefixFormat, operator.ExcludedFormat)
+	if err != nil{
+		return nil, err
+	}
+	var overlaps []model.RuleOverlap
+	for _, other := range existing{
+		if other.CountryIdentifier != operator.CountryIdentifier ||
+			!model.PeriodsIntersect(operator.EffectiveFrom, operator.EffectiveTo, other.EffectiveFrom, other.EffectiveTo){
+			continue
+		}
+		otherPattern, err := CompileRule(other.PrefixFormat, other.ExcludedFormat)
+		if err != nil{
+			continue
+		}
+		if example, ok := Overlap(pattern, otherPattern); ok{
+			overlaps = append(overlaps, model.RuleOverlap{
+				Kind: model.RuleKindOperator,
+				CountryIdentifier: operator.CountryIdentifier,
+				First: operator.PrefixFormat,
+				FirstPriority: operator.Priority,
+				Second: other.PrefixFormat,
+				SecondPriority: other.Priority,
+				Example: example,
+			})
+		}
+	}
+	return overlaps, nil
+}
+
+// FindOverlaps returns every pair of country rules, and of operator rules of the same
+// country, that match a common number while both are in effect, along with the rule
+// that number resolves to once they both are
+func FindOverlaps(countries []model.Country, operators []model.MobileOperator) []model.RuleOverlap{
+
+	plan := NewPlan(countries, operators)
+	var overlaps []model.RuleOverlap
+
+	for i := range plan.countries{
+		for j := i + 1; j < len(plan.countries); j++ {
+			first, second := plan.countries[i], plan.countries[j]
+			if !model.PeriodsIntersect(first.country.EffectiveFrom, first.country.EffectiveTo, second.country.EffectiveFrom, second.country.EffectiveTo){
+				continue
+			}
+			example, ok := Overlap(first.pattern, second.pattern)
+			if !ok{
+				continue
+			}
+			overlap := model.RuleOverlap{
+				Kind: model.RuleKindCountry,
+				First: first.country.CountryNumberFormat,
+				FirstPriority: first.country.Priority,
+				Second: second.country.CountryNumberFormat,
+				SecondPriority: second.country.Priority,
+				Example: example,
+			}
+			at := laterStart(first.country.EffectiveFrom, second.country.EffectiveFrom)
+			if winner, found := plan.LookupCountry(example, at); found{
+				overlap.Winner = winner.CountryNumberFormat
+			}
+			overlaps = append(overlaps, overlap)
+		}
+	}
+
+	for _, ci := range sortedIdentifiers(plan.operators){
+		rules := plan.operators[ci].rules
+		for i := range rules{
+			for j := i + 1; j < len(rules); j++ {
+				first, second := rules[i], rules[j]
+				if !model.PeriodsIntersect(first.operator.EffectiveFrom, first.operator.EffectiveTo, second.operator.EffectiveFrom, second.operator.EffectiveTo){
+					continue
+				}
+				example, ok := Overlap(first.pattern, second.pattern)
+				if !ok{
+					continue
+				}
+				overlap := model.RuleOverlap{
+					Kind: model.RuleKindOperator,
+					CountryIdentifier: ci,
+					First: first.operator.PrefixFormat,
+					FirstPriority: first.operator.Priority,
+					Second: second.operator.PrefixFormat,
+					SecondPriority: second.operator.Priority,
+					Example: example,
+				}
+				at := laterStart(first.operator.EffectiveFrom, second.operator.EffectiveFrom)
+				if winner, found := plan.LookupOperator(ci, example, at); found{
+					overlap.Winner = winner.PrefixFormat
+				}
+				overlaps = append(overlaps, overlap)
+			}
+		}
+	}
+	return overlaps
+}
+
+// laterStart returns the moment from which two rules starting at the given times are
+// both in effect, where nil means they always were
+func laterStart(a *time.Time, b *time.Time) time.Time{
+
+	var start time.Time
+	for _, t := range []*time.Time{a, b}{
+		if t != nil && t.After(start){
+			start = *t
+		}
+	}
+	return start
+}
@@ -0,0 +1,145 @@
+package numplan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robesmi/MSISDNApp/model"
+)
+
+func TestOverlap(t *testing.T) {
+
+	tt := []struct{
+		Name string
+		First string
+		Second string
+		ExpectedExample string
+		ExpectsOverlap bool
+	}{
+		{
+			Name:				"Nested ranges",
+			First:				"^7[0-9]{7}$",
+			Second:				"^77[0-9]{6}$",
+			ExpectedExample:	"77000000",
+			ExpectsOverlap:		true,
+		},
+		{
+			Name:				"Different prefixes",
+			First:				"^77[0-9]{6}$",
+			Second:				"^71[0-9]{6}$",
+		},
+		{
+			Name:				"Different lengths",
+			First:				"^5366[0-9]{6}$",
+			Second:				"^53[0-7][0-9]{6}$",
+		},
+		{
+			Name:				"Alternatives",
+			First:				"^(23[0-9]|24[0-9]|25[0-4])[0-9]{7}$",
+			Second:				"^25[3-9][0-9]{7}$",
+			ExpectedExample:	"2530000000",
+			ExpectsOverlap:		true,
+		},
+		{
+			Name:				"Unanchored pattern",
+			First:				"77",
+			Second:				"^389[0-9]{8}$",
+			ExpectedExample:	"38900000077",
+			ExpectsOverlap:		true,
+		},
+	}
+
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Arrange
+			first, err := CompilePattern(test.First)
+			if err != nil{
+				t.Fatal(err)
+			}
+			second, err := CompilePattern(test.Second)
+			if err != nil{
+				t.Fatal(err)
+			}
+
+			//Act
+			example, overlaps := Overlap(first, second)
+
+			//Assert
+			if overlaps != test.ExpectsOverlap{
+				t.Fatalf("Error in TestOverlap:\n expected overlap %t\n got %t", test.ExpectsOverlap, overlaps)
+			}
+			if example != test.ExpectedExample{
+				t.Errorf("Error in TestOverlap:\n expected %s\n got %s", test.ExpectedExample, example)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
+
+func TestFindOverlaps(t *testing.T) {
+
+	//Arrange
+	countries := append([]model.Country{
+		{CountryNumberFormat: "^3897[0-9]{7}$", CountryIdentifier: "xk", Priority: 1},
+	}, testCountries...)
+
+	//Act
+	overlaps := FindOverlaps(countries, testOperators)
+
+	//Assert
+	if len(overlaps) != 1{
+		t.Fatalf("Error in TestFindOverlaps:\n expected %d overlap\n got %v", 1, overlaps)
+	}
+	if overlaps[0].Kind != model.RuleKindCountry || overlaps[0].Winner != "^3897[0-9]{7}$"{
+		t.Errorf("Error in TestFindOverlaps:\n expected %s to win\n got %v", "^3897[0-9]{7}$", overlaps[0])
+	}
+}
+
+func TestOverlapExcluded(t *testing.T) {
+
+	//Arrange
+	broad, err := CompileRule("^7[0-9]{7}$", "^77[0-9]{6}$")
+	if err != nil{
+		t.Fatal(err)
+	}
+	narrow, err := CompilePattern("^77[0-9]{6}$")
+	if err != nil{
+		t.Fatal(err)
+	}
+
+	//Act
+	example, overlaps := Overlap(broad, narrow)
+
+	//Assert
+	if overlaps{
+		t.Errorf("Error in TestOverlapExcluded:\n expected no overlap\n got %s", example)
+	}
+}
+
+func TestOperatorOverlapsDisjointPeriods(t *testing.T) {
+
+	//Arrange
+	reallocation := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	existing := []model.MobileOperator{
+		{CountryIdentifier: "mk", PrefixFormat: "^77[0-9]{6}$", MNO: "Telekom", EffectiveTo: &reallocation},
+	}
+	scheduled := model.MobileOperator{CountryIdentifier: "mk", PrefixFormat: "^77[0-9]{6}$", MNO: "A1", EffectiveFrom: &reallocation}
+	early := scheduled
+	early.EffectiveFrom = nil
+
+	//Act
+	overlaps, err := OperatorOverlaps(scheduled, existing)
+	earlyOverlaps, earlyErr := OperatorOverlaps(early, existing)
+
+	//Assert
+	if err != nil || earlyErr != nil{
+		t.Fatalf("Error in TestOperatorOverlapsDisjointPeriods:\n expected %s\n got %v, %v", "nil", err, earlyErr)
+	}
+	if len(overlaps) != 0{
+		t.Errorf("Error in TestOperatorOverlapsDisjointPeriods:\n expected no overlaps\n got %v", overlaps)
+	}
+	if len(earlyOverlaps) != 1{
+		t.Errorf("Error in TestOperatorOverlapsDisjointPeriods:\n expected %d overlap\n got %v", 1, earlyOverlaps)
+	}
+}
@@ -0,0 +1,238 @@
+package numplan
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robesmi/MSISDNApp/model"
+)
+
+// rival is a better ranked rule a number being typed can still match, which would take
+// the number from the rule being completed
+type rival struct {
+	id int
+	pattern *Pattern
+	s ruleState
+}
+
+// Partial returns the countries and ranges in effect at the time that a number starting
+// with the digits can still resolve to, along with how many more digits each of them takes.
+// Ranges are only listed once the digits hold the calling code of their country. Rules
+// whose patterns couldn't be compiled aren't considered
+func (p *Plan) Partial(digits string, at time.Time) model.PartialMatch{
+
+	var match model.PartialMatch
+	budget := maxNumberLength - len(digits)
+
+	var rivals []rival
+	for _, id := range sortedCandidates(p.countryIndex.Completions(digits)){
+		rule := p.countries[id]
+		if !rule.country.EffectiveAt(at){
+			continue
+		}
+		s, live := rule.pattern.read(digits)
+		if !live{
+			continue
+		}
+		min, max, ok := p.remaining("", id, rule.pattern, s, rivals, budget)
+		rivals = append(rivals, rival{id, rule.pattern, s})
+		if !ok{
+			continue
+		}
+		match.Countries = addPartialCountry(match.Countries, model.PartialCountry{Country: rule.country, MinRemaining: min, MaxRemaining: max})
+	}
+
+	for i, candidate := range match.Countries{
+		if i == 0 || candidate.MinRemaining < match.MinRemaining{
+			match.MinRemaining = candidate.MinRemaining
+		}
+		if candidate.MaxRemaining > match.MaxRemaining{
+			match.MaxRemaining = candidate.MaxRemaining
+		}
+		code := candidate.Country.CountryCode
+		if strings.HasPrefix(digits, code){
+			match.Operators = append(match.Operators, p.partialOperators(candidate.Country.CountryIdentifier, digits[len(code):], budget, at)...)
+		}
+	}
+
+	if country, ok := p.LookupCountry(digits, at); ok && strings.HasPrefix(digits, country.CountryCode){
+		_, match.Complete = p.LookupOperator(country.CountryIdentifier, digits[len(country.CountryCode):], at)
+	}
+	return match
+}
+
+// partialOperators returns the ranges of the country in effect at the time that a
+// significant number starting with the digits can still resolve to
+func (p *Plan) partialOperators(ci string, digits string, budget int, at time.Time) []model.PartialOperator{
+
+	table, ok := p.operators[ci]
+	if !ok{
+		return nil
+	}
+	var operators []model.PartialOperator
+	var rivals []rival
+	for _, id := range sortedCandidates(table.index.Completions(digits)){
+		rule := table.rules[id]
+		if !rule.operator.EffectiveAt(at){
+			continue
+		}
+		s, live := rule.pattern.read(digits)
+		if !live{
+			continue
+		}
+		min, max, ok := p.remaining(ci, id, rule.pattern, s, rivals, budget)
+		rivals = append(rivals, rival{id, rule.pattern, s})
+		if !ok{
+			continue
+		}
+		operators = addPartialOperator(operators, model.PartialOperator{Operator: rule.operator, MinRemaining: min, MaxRemaining: max})
+	}
+	return operators
+}
+
+// read steps the rule through the digits, reporting whether a number starting with
+// them can still match it
+func (p *Pattern) read(digits string) (ruleState, bool){
+
+	s := p.ruleStart()
+	for _, d := range digits{
+		if s = p.ruleStep(s, d); s.dead(){
+			return s, false
+		}
+	}
+	return s, true
+}
+
+// completion is how many more digits complete a number to a rule
+type completion struct {
+	min, max int
+	found bool
+}
+
+// remaining returns the completion of the rule, a country rule when ci is empty, from the
+// state. Completions are kept for as long as the plan, since numbers are typed one digit
+// at a time and the rules reach few distinct states
+func (p *Plan) remaining(ci string, id int, pattern *Pattern, s ruleState, rivals []rival, budget int) (int, int, bool){
+
+	key := append([]byte(ci), '|')
+	key = strconv.AppendInt(key, int64(id), 10)
+	key = append(key, '|')
+	key = strconv.AppendInt(key, int64(budget), 10)
+	key = append(key, '|')
+	key = s.appendKey(key)
+	for _, r := range rivals{
+		key = append(key, '|')
+		key = strconv.AppendInt(key, int64(r.id), 10)
+		key = append(key, ':')
+		key = r.s.appendKey(key)
+	}
+	if cached, ok := p.completions.Load(string(key)); ok{
+		c := cached.(completion)
+		return c.min, c.max, c.found
+	}
+	min, max, found := remaining(pattern, s, rivals, budget)
+	p.completions.Store(string(key), completion{min, max, found})
+	return min, max, found
+}
+
+// remaining returns the fewest and most digits, up to budget, that complete the state
+// into a number the pattern matches and none of the better ranked rivals do
+func remaining(pattern *Pattern, s ruleState, rivals []rival, budget int) (int, int, bool){
+
+	type node struct {
+		s ruleState
+		rivals []rival
+	}
+
+	min, max, found := 0, 0, false
+	layer := []node{{s, rivals}}
+	for length := 0; len(layer) > 0 && length <= budget; length++ {
+		var next []node
+		var key []byte
+		seen := make(map[string]bool)
+		for _, n := range layer{
+			if n.s.accepts() && !rivalAccepts(n.rivals){
+				if !found{
+					min, found = length, true
+				}
+				max = length
+			}
+			if length == budget{
+				continue
+			}
+			for d := '0'; d <= '9'; d++ {
+				child := node{s: pattern.ruleStep(n.s, d)}
+				if child.s.dead(){
+					continue
+				}
+				key = child.s.appendKey(key[:0])
+				for _, r := range n.rivals{
+					// Rivals that can no longer match drop out, which keeps the states few
+					rs := r.pattern.ruleStep(r.s, d)
+					if rs.dead(){
+						continue
+					}
+					child.rivals = append(child.rivals, rival{r.id, r.pattern, rs})
+					key = append(key, '|')
+					key = strconv.AppendInt(key, int64(r.id), 10)
+					key = append(key, ':')
+					key = rs.appendKey(key)
+				}
+				// Numbers of the same length reaching the same states have the same completions
+				if seen[string(key)]{
+					continue
+				}
+				seen[string(key)] = true
+				next = append(next, child)
+			}
+		}
+		layer = next
+	}
+	return min, max, found
+}
+
+func rivalAccepts(rivals []rival) bool{
+
+	for _, r := range rivals{
+		if r.s.accepts(){
+			return true
+		}
+	}
+	return false
+}
+
+// addPartialCountry adds the candidate to the list, merging it with an earlier rule of the same country
+func addPartialCountry(countries []model.PartialCountry, candidate model.PartialCountry) []model.PartialCountry{
+
+	for i := range countries{
+		if countries[i].Country.CountryIdentifier == candidate.Country.CountryIdentifier{
+			widen(&countries[i].MinRemaining, &countries[i].MaxRemaining, candidate.MinRemaining, candidate.MaxRemaining)
+			return countries
+		}
+	}
+	return append(countries, candidate)
+}
+
+// addPartialOperator adds the candidate to the list, merging it with an earlier range of the
+// same operator and number type
+func addPartialOperator(operators []model.PartialOperator, candidate model.PartialOperator) []model.PartialOperator{
+
+	for i := range operators{
+		if operators[i].Operator.MNO == candidate.Operator.MNO && operators[i].Operator.NumberType == candidate.Operator.NumberType{
+			widen(&operators[i].MinRemaining, &operators[i].MaxRemaining, candidate.MinRemaining, candidate.MaxRemaining)
+			return operators
+		}
+	}
+	return append(operators, candidate)
+}
+
+func widen(min *int, max *int, otherMin int, otherMax int){
+
+	if otherMin < *min{
+		*min = otherMin
+	}
+	if otherMax > *max{
+		*max = otherMax
+	}
+}
@@ -0,0 +1,67 @@
+package numplan
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlanPartial(t *testing.T) {
+
+	tt := []struct{
+		Name string
+		Input string
+		Shared bool
+		ExpectedCountries string
+		ExpectedOperators string
+		ExpectedMin int
+		ExpectedMax int
+		ExpectedComplete bool
+	}{
+		{Name: "Calling code being typed", Input: "3", ExpectedCountries: "mk", ExpectedMin: 10, ExpectedMax: 10},
+		{Name: "Calling code typed", Input: "389", ExpectedCountries: "mk", ExpectedOperators: "Telekom,A1", ExpectedMin: 8, ExpectedMax: 8},
+		{Name: "Range prefix typed", Input: "38977", ExpectedCountries: "mk", ExpectedOperators: "A1", ExpectedMin: 6, ExpectedMax: 6},
+		{Name: "Complete number", Input: "38977123456", ExpectedCountries: "mk", ExpectedOperators: "A1", ExpectedComplete: true},
+		{Name: "Too long", Input: "389771234567"},
+		{Name: "No country", Input: "5"},
+		{Name: "Shared calling code", Input: "1", Shared: true, ExpectedCountries: "ca,bb,us", ExpectedMin: 10, ExpectedMax: 10},
+		{Name: "Area code taken by a better ranked country", Input: "1246", Shared: true, ExpectedCountries: "bb", ExpectedMin: 7, ExpectedMax: 7},
+		{Name: "Area code shared by a better ranked country", Input: "44752", Shared: true, ExpectedCountries: "im,gb", ExpectedMin: 7, ExpectedMax: 7},
+	}
+
+	plan, shared := NewPlan(testCountries, testOperators), NewPlan(sharedCodeCountries, nil)
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Arrange
+			p := plan
+			if test.Shared{
+				p = shared
+			}
+
+			//Act
+			match := p.Partial(test.Input, now)
+
+			//Assert
+			var countries, operators []string
+			for _, c := range match.Countries{
+				countries = append(countries, c.Country.CountryIdentifier)
+			}
+			for _, o := range match.Operators{
+				operators = append(operators, o.Operator.MNO)
+			}
+			if strings.Join(countries, ",") != test.ExpectedCountries{
+				t.Errorf("Error in TestPlanPartial:\n expected countries %s\n got %v", test.ExpectedCountries, countries)
+			}
+			if strings.Join(operators, ",") != test.ExpectedOperators{
+				t.Errorf("Error in TestPlanPartial:\n expected operators %s\n got %v", test.ExpectedOperators, operators)
+			}
+			if match.MinRemaining != test.ExpectedMin || match.MaxRemaining != test.ExpectedMax{
+				t.Errorf("Error in TestPlanPartial:\n expected %d-%d remaining digits\n got %d-%d", test.ExpectedMin, test.ExpectedMax, match.MinRemaining, match.MaxRemaining)
+			}
+			if match.Complete != test.ExpectedComplete{
+				t.Errorf("Error in TestPlanPartial:\n expected complete %v\n got %v", test.ExpectedComplete, match.Complete)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
@@ -0,0 +1,200 @@
+package numplan
+
+import (
+	"regexp"
+	"regexp/syntax"
+)
+
+// Pattern is a compiled numbering plan rule. It matches numbers the same way the
+// RLIKE operator the rules were written for does, and it can also be stepped through
+// one digit at a time, which is what lets the index work out which prefixes a rule
+// is able to start with
+type Pattern struct {
+	expr string
+	re *regexp.Regexp
+	prog *syntax.Prog
+	// excluded matches the numbers within the pattern that the rule leaves out
+	excluded *Pattern
+}
+
+// state is the set of program instructions waiting on the next digit
+type state struct {
+	pcs []uint32
+	// matched is set once the pattern matched part of the input, after which
+	// any continuation matches as well
+	matched bool
+	// final is set when the pattern matches if the input ends here
+	final bool
+}
+
+func (s state) dead() bool {
+	return !s.matched && !s.final && len(s.pcs) == 0
+}
+
+// CompilePattern compiles a numbering plan regex, returning an error if the
+// expression is not supported by the RE2 syntax
+func CompilePattern(expr string) (*Pattern, error){
+
+	re, err := regexp.Compile(expr)
+	if err != nil{
+		return nil, err
+	}
+	parsed, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil{
+		return nil, err
+	}
+	prog, err := syntax.Compile(parsed.Simplify())
+	if err != nil{
+		return nil, err
+	}
+	return &Pattern{expr: expr, re: re, prog: prog}, nil
+}
+
+// CompileRule compiles a rule pattern along with the pattern of the ranges it excludes,
+// which can be empty
+func CompileRule(expr string, excluded string) (*Pattern, error){
+
+	p, err := CompilePattern(expr)
+	if err != nil{
+		return nil, err
+	}
+	if excluded != ""{
+		if p.excluded, err = CompilePattern(excluded); err != nil{
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func (p *Pattern) String() string{
+	return p.expr
+}
+
+// MatchString reports whether the number matches the pattern and isn't excluded
+func (p *Pattern) MatchString(number string) bool{
+	return p.re.MatchString(number) && (p.excluded == nil || !p.excluded.MatchString(number))
+}
+
+// Prefixes returns the digit prefixes that every number matched by the pattern
+// starts with, refined up to maxDepth digits and at most maxCount prefixes.
+// Excluded ranges are ignored, so the prefixes may cover some numbers the rule doesn't match.
+// A pattern that can start with any digit returns the empty prefix
+func (p *Pattern) Prefixes(maxDepth int, maxCount int) []string{
+
+	type node struct {
+		prefix string
+		s state
+	}
+
+	var out []string
+	frontier := []node{{"", p.start()}}
+	for depth := 0; len(frontier) > 0; depth++ {
+		var expanding []node
+		var next []node
+		for _, n := range frontier{
+			if n.s.matched || n.s.final || depth == maxDepth{
+				out = append(out, n.prefix)
+				continue
+			}
+			var live []node
+			for d := '0'; d <= '9'; d++ {
+				if ns := p.step(n.s, d); !ns.dead(){
+					live = append(live, node{n.prefix + string(d), ns})
+				}
+			}
+			if len(live) == 10{
+				out = append(out, n.prefix)
+				continue
+			}
+			expanding = append(expanding, n)
+			next = append(next, live...)
+		}
+		// Stop refining once the rule would be spread over too many prefixes
+		if len(out) + len(next) > maxCount{
+			for _, n := range expanding{
+				out = append(out, n.prefix)
+			}
+			break
+		}
+		frontier = next
+	}
+	return out
+}
+
+func (p *Pattern) start() state{
+	return p.closure([]uint32{uint32(p.prog.Start)}, true)
+}
+
+// step advances the state by one digit. The start of the program is added again
+// at every position, since RLIKE looks for the pattern anywhere in the input
+func (p *Pattern) step(s state, r rune) state{
+
+	if s.matched{
+		return s
+	}
+	next := []uint32{uint32(p.prog.Start)}
+	for _, pc := range s.pcs{
+		inst := &p.prog.Inst[pc]
+		switch inst.Op {
+		case syntax.InstRuneAny, syntax.InstRuneAnyNotNL:
+			next = append(next, inst.Out)
+		default:
+			if inst.MatchRune(r){
+				next = append(next, inst.Out)
+			}
+		}
+	}
+	return p.closure(next, false)
+}
+
+// closure follows the empty transitions from the given instructions. Word boundaries
+// are treated as always satisfied, which can only make the pattern look more permissive
+func (p *Pattern) closure(from []uint32, begin bool) state{
+
+	var s state
+	seen := make([]uint8, len(p.prog.Inst))
+	var visit func(pc uint32, atEnd bool)
+	visit = func(pc uint32, atEnd bool){
+		mark := uint8(1)
+		if atEnd{
+			mark = 2
+		}
+		if seen[pc]&mark != 0{
+			return
+		}
+		seen[pc] |= mark
+
+		inst := &p.prog.Inst[pc]
+		switch inst.Op {
+		case syntax.InstAlt, syntax.InstAltMatch:
+			visit(inst.Out, atEnd)
+			visit(inst.Arg, atEnd)
+		case syntax.InstCapture, syntax.InstNop:
+			visit(inst.Out, atEnd)
+		case syntax.InstEmptyWidth:
+			cond := syntax.EmptyOp(inst.Arg)
+			if cond&(syntax.EmptyBeginText|syntax.EmptyBeginLine) != 0 && !begin{
+				return
+			}
+			if cond&(syntax.EmptyEndText|syntax.EmptyEndLine) != 0{
+				atEnd = true
+			}
+			visit(inst.Out, atEnd)
+		case syntax.InstMatch:
+			if atEnd{
+				s.final = true
+			}else{
+				s.matched = true
+			}
+		case syntax.InstFail:
+		default:
+			if !atEnd{
+				s.pcs = append(s.pcs, pc)
+			}
+		}
+	}
+	for _, pc := range from{
+		visit(pc, false)
+	}
+	return s
+}
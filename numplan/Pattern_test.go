@@ -0,0 +1,112 @@
+package numplan
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPatternPrefixes(t *testing.T) {
+
+	tt := []struct{
+		Name string
+		Pattern string
+		Expected []string
+	}{
+		{
+			Name:		"Literal country code",
+			Pattern:	"^389[0-9]{8}$",
+			Expected:	[]string{"389"},
+		},
+		{
+			Name:		"Alternation",
+			Pattern:	"^(50|56)[0-9]{7}$",
+			Expected:	[]string{"50", "56"},
+		},
+		{
+			Name:		"Character class",
+			Pattern:	"^922[0-2][0-9]{5}$",
+			Expected:	[]string{"9220", "9221", "9222"},
+		},
+		{
+			Name:		"Unanchored pattern",
+			Pattern:	"[0-9]{1}",
+			Expected:	[]string{""},
+		},
+		{
+			Name:		"Pattern matching no digits",
+			Pattern:	"^[a-z]+$",
+			Expected:	nil,
+		},
+	}
+
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Arrange
+			pattern, err := CompilePattern(test.Pattern)
+			if err != nil{
+				t.Fatalf("Error compiling %s: %s", test.Pattern, err)
+			}
+
+			//Act
+			prefixes := pattern.Prefixes(indexDepth, indexWidth)
+
+			//Assert
+			if !reflect.DeepEqual(prefixes, test.Expected){
+				t.Errorf("Error in TestPatternPrefixes:\n expected %v\n got %v", test.Expected, prefixes)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
+
+func TestPatternPrefixesWidthLimit(t *testing.T) {
+
+	//Arrange
+	pattern, _ := CompilePattern("^[1-9]{6}$")
+
+	//Act
+	prefixes := pattern.Prefixes(indexDepth, indexWidth)
+
+	//Assert
+	if len(prefixes) > indexWidth{
+		t.Errorf("Error in TestPatternPrefixesWidthLimit:\n expected at most %d prefixes\n got %d", indexWidth, len(prefixes))
+	}
+	for _, prefix := range prefixes{
+		if len(prefix) != 1{
+			t.Errorf("Error in TestPatternPrefixesWidthLimit:\n expected single digit prefixes\n got %s", prefix)
+		}
+	}
+}
+
+func TestCompilePatternLookahead(t *testing.T) {
+
+	//Act
+	_, err := CompilePattern("(?!^5329[0-9]{5}$)^53[0-7][0-9]{6}$")
+
+	//Assert
+	if err == nil{
+		t.Error("Error in TestCompilePatternLookahead:\n expected an error\n got nil")
+	}
+}
+
+func TestCompileRuleExcluded(t *testing.T) {
+
+	//Arrange
+	rule, err := CompileRule("^53[0-7][0-9]{6}$", "^5329[0-9]{5}$|^5366[0-9]{5}$")
+	if err != nil{
+		t.Fatal(err)
+	}
+
+	//Act
+	inRange := rule.MatchString("533123456")
+	excluded := rule.MatchString("532912345")
+
+	//Assert
+	if !inRange{
+		t.Error("Error in TestCompileRuleExcluded:\n expected a number in the range to match")
+	}
+	if excluded{
+		t.Error("Error in TestCompileRuleExcluded:\n expected an excluded number to not match")
+	}
+}
@@ -0,0 +1,271 @@
+package numplan
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/robesmi/MSISDNApp/model"
+)
+
+const (
+	// indexDepth is how many leading digits of a rule are used to place it in the trie
+	indexDepth = 6
+	// indexWidth is the most prefixes a single rule is spread over in the trie
+	indexWidth = 64
+	// maxCallingCodeLength is the most digits a calling code can have
+	maxCallingCodeLength = 6
+)
+
+// Plan is an immutable snapshot of the numbering plan with every rule compiled
+// and indexed by its leading digits, so lookups only evaluate the few rules
+// that can possibly match a number
+type Plan struct {
+	countries []countryRule
+	countryIndex Trie
+	byIdentifier map[string][]model.Country
+	byCallingCode map[string][]model.Country
+	operators map[string]*operatorTable
+	countriesComplete bool
+	unsupported []string
+	// completions caches the digits left to complete numbers being typed, see Partial
+	completions sync.Map
+}
+
+type countryRule struct {
+	country model.Country
+	pattern *Pattern
+	prefixes []string
+}
+
+type operatorRule struct {
+	operator model.MobileOperator
+	pattern *Pattern
+	prefixes []string
+}
+
+type operatorTable struct {
+	rules []operatorRule
+	index Trie
+	complete bool
+}
+
+// NewPlan compiles and indexes the given countries and operators, including the rules
+// that are no longer or not yet in effect, which lookups skip. When several rules match
+// a number the one with the highest priority wins, then the most specific one, meaning
+// the one with the longest fixed prefix, and then the pattern that sorts first. Rules
+// whose patterns can't be compiled are left out of the plan and reported by Unsupported
+func NewPlan(countries []model.Country, operators []model.MobileOperator) *Plan{
+
+	plan := Plan{
+		operators: make(map[string]*operatorTable),
+		byIdentifier: make(map[string][]model.Country),
+		byCallingCode: make(map[string][]model.Country),
+		countriesComplete: true,
+	}
+
+	for _, c := range countries{
+		plan.byIdentifier[c.CountryIdentifier] = append(plan.byIdentifier[c.CountryIdentifier], c)
+		plan.byCallingCode[c.CountryCode] = append(plan.byCallingCode[c.CountryCode], c)
+		pattern, err := CompileRule(c.CountryNumberFormat, c.ExcludedFormat)
+		if err != nil{
+			plan.countriesComplete = false
+			plan.unsupported = append(plan.unsupported, c.CountryNumberFormat)
+			continue
+		}
+		plan.countries = append(plan.countries, countryRule{c, pattern, pattern.Prefixes(indexDepth, indexWidth)})
+	}
+	sort.SliceStable(plan.countries, func(i, j int) bool {
+		a, b := plan.countries[i], plan.countries[j]
+		return ranksBefore(a.country.Priority, a.prefixes, a.country.CountryNumberFormat,
+			b.country.Priority, b.prefixes, b.country.CountryNumberFormat)
+	})
+	for id, rule := range plan.countries{
+		for _, prefix := range rule.prefixes{
+			plan.countryIndex.Insert(prefix, id)
+		}
+	}
+
+	for _, o := range operators{
+		table, ok := plan.operators[o.CountryIdentifier]
+		if !ok{
+			table = &operatorTable{complete: true}
+			plan.operators[o.CountryIdentifier] = table
+		}
+		pattern, err := CompileRule(o.PrefixFormat, o.ExcludedFormat)
+		if err != nil{
+			table.complete = false
+			plan.unsupported = append(plan.unsupported, o.PrefixFormat)
+			continue
+		}
+		table.rules = append(table.rules, operatorRule{o, pattern, pattern.Prefixes(indexDepth, indexWidth)})
+	}
+	for _, table := range plan.operators{
+		rules := table.rules
+		sort.SliceStable(rules, func(i, j int) bool {
+			a, b := rules[i], rules[j]
+			return ranksBefore(a.operator.Priority, a.prefixes, a.operator.PrefixFormat,
+				b.operator.Priority, b.prefixes, b.operator.PrefixFormat)
+		})
+		for id, rule := range rules{
+			for _, prefix := range rule.prefixes{
+				table.index.Insert(prefix, id)
+			}
+		}
+	}
+
+	return &plan
+}
+
+// LookupCountry returns the best ranked country in effect at the time whose pattern
+// matches the full number
+func (p *Plan) LookupCountry(number string, at time.Time) (*model.Country, bool){
+
+	for _, id := range sortedCandidates(p.countryIndex.Candidates(number)){
+		rule := p.countries[id]
+		if rule.country.EffectiveAt(at) && rule.pattern.MatchString(number){
+			country := rule.country
+			return &country, true
+		}
+	}
+	return nil, false
+}
+
+// CountryByIdentifier returns the first country, in load order, with the identifier
+// in effect at the time, including countries whose pattern could not be compiled
+func (p *Plan) CountryByIdentifier(ci string, at time.Time) (*model.Country, bool){
+	return firstEffective(p.byIdentifier[ci], at)
+}
+
+// CountryByCallingCode returns the country in effect at the time with the longest calling
+// code the number starts with, whether or not the rest of the number fits the country's pattern.
+// When several countries share the calling code, like the ones of NANP, the best ranked one
+// whose pattern starts like the number wins, so the area code picks the country
+func (p *Plan) CountryByCallingCode(number string, at time.Time) (*model.Country, bool){
+
+	length := len(number)
+	if length > maxCallingCodeLength{
+		length = maxCallingCodeLength
+	}
+	for ; length > 0; length--{
+		code := number[:length]
+		sharing := p.byCallingCode[code]
+		if len(sharing) == 0{
+			continue
+		}
+		if len(sharing) > 1{
+			for _, id := range sortedCandidates(p.countryIndex.Candidates(number)){
+				rule := p.countries[id]
+				if rule.country.CountryCode == code && rule.country.EffectiveAt(at){
+					country := rule.country
+					return &country, true
+				}
+			}
+		}
+		if country, ok := firstEffective(sharing, at); ok{
+			return country, true
+		}
+	}
+	return nil, false
+}
+
+// LookupOperator returns the best ranked operator of the country in effect at the
+// time whose pattern matches the significant number
+func (p *Plan) LookupOperator(ci string, significantNumber string, at time.Time) (*model.MobileOperator, bool){
+
+	table, ok := p.operators[ci]
+	if !ok{
+		return nil, false
+	}
+	for _, id := range sortedCandidates(table.index.Candidates(significantNumber)){
+		rule := table.rules[id]
+		if rule.operator.EffectiveAt(at) && rule.pattern.MatchString(significantNumber){
+			operator := rule.operator
+			return &operator, true
+		}
+	}
+	return nil, false
+}
+
+// CountriesComplete reports whether every country rule made it into the plan,
+// meaning a failed country lookup is final
+func (p *Plan) CountriesComplete() bool{
+	return p.countriesComplete
+}
+
+// OperatorsComplete reports whether every operator rule of the country made it
+// into the plan, meaning a failed operator lookup is final
+func (p *Plan) OperatorsComplete(ci string) bool{
+
+	table, ok := p.operators[ci]
+	if !ok{
+		return true
+	}
+	return table.complete
+}
+
+// Unsupported returns the patterns that could not be compiled
+func (p *Plan) Unsupported() []string{
+	return p.unsupported
+}
+
+func firstEffective(countries []model.Country, at time.Time) (*model.Country, bool){
+
+	for _, country := range countries{
+		if country.EffectiveAt(at){
+			return &country, true
+		}
+	}
+	return nil, false
+}
+
+// RanksBefore reports whether the first of two rules matching a number wins over the second,
+// ranking them like a Plan does. Patterns that can't be compiled count as the least specific
+func RanksBefore(priorityA int, exprA string, priorityB int, exprB string) bool{
+	return ranksBefore(priorityA, rulePrefixes(exprA), exprA, priorityB, rulePrefixes(exprB), exprB)
+}
+
+func rulePrefixes(expr string) []string{
+
+	pattern, err := CompilePattern(expr)
+	if err != nil{
+		return nil
+	}
+	return pattern.Prefixes(indexDepth, indexWidth)
+}
+
+// ranksBefore reports whether the first rule wins over the second when both match a number
+func ranksBefore(priorityA int, prefixesA []string, exprA string, priorityB int, prefixesB []string, exprB string) bool{
+
+	if priorityA != priorityB{
+		return priorityA > priorityB
+	}
+	if specificityA, specificityB := specificity(prefixesA), specificity(prefixesB); specificityA != specificityB{
+		return specificityA > specificityB
+	}
+	return exprA < exprB
+}
+
+// specificity is the length of the shortest prefix a rule can start with
+func specificity(prefixes []string) int{
+
+	shortest := 0
+	for i, prefix := range prefixes{
+		if i == 0 || len(prefix) < shortest{
+			shortest = len(prefix)
+		}
+	}
+	return shortest
+}
+
+func sortedCandidates(candidates []int) []int{
+
+	sort.Ints(candidates)
+	unique := candidates[:0]
+	for _, id := range candidates{
+		if len(unique) == 0 || id != unique[len(unique)-1]{
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,299 @@
+package numplan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robesmi/MSISDNApp/model"
+)
+
+// now is the time the tests look the numbering plan up at
+var now = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+var testCountries = []model.Country{
+	{
+		CountryNumberFormat: "^389[0-9]{8}$",
+		CountryCode: "389",
+		CountryIdentifier: "mk",
+		CountryCodeLength: 3,
+	},
+	{
+		CountryNumberFormat: "^48[0-9]{9}$",
+		CountryCode: "48",
+		CountryIdentifier: "pl",
+		CountryCodeLength: 2,
+	},
+}
+
+var testOperators = []model.MobileOperator{
+	{
+		CountryIdentifier: "mk",
+		PrefixFormat: "^77[0-9]{6}$",
+		MNO: "A1",
+		PrefixLength: 2,
+	},
+	{
+		CountryIdentifier: "mk",
+		PrefixFormat: "^71[0-9]{6}$",
+		MNO: "Telekom",
+		PrefixLength: 2,
+	},
+	{
+		CountryIdentifier: "pl",
+		PrefixFormat: "(?!^5329[0-9]{5}$)^53[0-7][0-9]{6}$",
+		MNO: "Orange Polska S.A",
+		PrefixLength: 2,
+	},
+	{
+		CountryIdentifier: "pl",
+		PrefixFormat: "^510[0-9]{6}$",
+		MNO: "Orange",
+		PrefixLength: 2,
+	},
+}
+
+func TestPlanLookupCountry(t *testing.T) {
+
+	tt := []struct{
+		Name string
+		Input string
+		ExpectedCI string
+		ExpectsFound bool
+	}{
+		{
+			Name:			"Macedonian number",
+			Input:			"38977123456",
+			ExpectedCI:		"mk",
+			ExpectsFound:	true,
+		},
+		{
+			Name:			"Polish number",
+			Input:			"48510123456",
+			ExpectedCI:		"pl",
+			ExpectsFound:	true,
+		},
+		{
+			Name:			"Wrong length",
+			Input:			"3897712345",
+			ExpectsFound:	false,
+		},
+		{
+			Name:			"Unknown country",
+			Input:			"6934567890",
+			ExpectsFound:	false,
+		},
+	}
+
+	plan := NewPlan(testCountries, testOperators)
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Act
+			country, found := plan.LookupCountry(test.Input, now)
+
+			//Assert
+			if found != test.ExpectsFound{
+				t.Fatalf("Error in TestPlanLookupCountry:\n expected found %t\n got %t", test.ExpectsFound, found)
+			}
+			if found && country.CountryIdentifier != test.ExpectedCI{
+				t.Errorf("Error in TestPlanLookupCountry:\n expected %s\n got %s", test.ExpectedCI, country.CountryIdentifier)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
+
+func TestPlanLookupOperator(t *testing.T) {
+
+	//Arrange
+	plan := NewPlan(testCountries, testOperators)
+
+	//Act
+	operator, found := plan.LookupOperator("mk", "71123456", now)
+
+	//Assert
+	if !found{
+		t.Fatal("Error in TestPlanLookupOperator:\n expected an operator\n got none")
+	}
+	if operator.MNO != "Telekom"{
+		t.Errorf("Error in TestPlanLookupOperator:\n expected %s\n got %s", "Telekom", operator.MNO)
+	}
+}
+
+func TestPlanUnsupportedPatterns(t *testing.T) {
+
+	//Act
+	plan := NewPlan(testCountries, testOperators)
+
+	//Assert
+	if len(plan.Unsupported()) != 1{
+		t.Errorf("Error in TestPlanUnsupportedPatterns:\n expected %d unsupported pattern\n got %d", 1, len(plan.Unsupported()))
+	}
+	if plan.OperatorsComplete("pl"){
+		t.Error("Error in TestPlanUnsupportedPatterns:\n expected pl operators to be incomplete")
+	}
+	if !plan.OperatorsComplete("mk") || !plan.CountriesComplete(){
+		t.Error("Error in TestPlanUnsupportedPatterns:\n expected mk operators and countries to be complete")
+	}
+	if _, found := plan.LookupOperator("pl", "510123456", now); !found{
+		t.Error("Error in TestPlanUnsupportedPatterns:\n expected supported pl operators to still resolve")
+	}
+}
+
+func TestPlanCountryByCallingCode(t *testing.T) {
+
+	//Arrange
+	plan := NewPlan(testCountries, testOperators)
+
+	//Act
+	country, found := plan.CountryByCallingCode("3897712345", now)
+	_, missing := plan.CountryByCallingCode("6934567890", now)
+
+	//Assert
+	if !found || country.CountryIdentifier != "mk"{
+		t.Errorf("Error in TestPlanCountryByCallingCode:\n expected %s\n got %v", "mk", country)
+	}
+	if missing{
+		t.Error("Error in TestPlanCountryByCallingCode:\n expected no country for an unknown calling code")
+	}
+}
+
+func TestPlanLookupOperatorRanking(t *testing.T) {
+
+	operators := []model.MobileOperator{
+		{CountryIdentifier: "mk", PrefixFormat: "^7[0-9]{7}$", MNO: "Telekom"},
+		{CountryIdentifier: "mk", PrefixFormat: "^77[0-9]{6}$", MNO: "A1"},
+		{CountryIdentifier: "mk", PrefixFormat: "^775[0-9]{5}$", MNO: "Lycamobile", Priority: -1},
+	}
+	tt := []struct{
+		Name string
+		Input string
+		ExpectedMNO string
+	}{
+		{
+			Name:			"Only the broad rule matches",
+			Input:			"71123456",
+			ExpectedMNO:	"Telekom",
+		},
+		{
+			Name:			"More specific rule wins",
+			Input:			"77123456",
+			ExpectedMNO:	"A1",
+		},
+		{
+			Name:			"Higher priority wins over a more specific rule",
+			Input:			"77512345",
+			ExpectedMNO:	"A1",
+		},
+	}
+
+	plan := NewPlan(nil, operators)
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Act
+			operator, found := plan.LookupOperator("mk", test.Input, now)
+
+			//Assert
+			if !found{
+				t.Fatal("Error in TestPlanLookupOperatorRanking:\n expected an operator\n got none")
+			}
+			if operator.MNO != test.ExpectedMNO{
+				t.Errorf("Error in TestPlanLookupOperatorRanking:\n expected %s\n got %s", test.ExpectedMNO, operator.MNO)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
+
+func TestPlanLookupOperatorEffective(t *testing.T) {
+
+	reallocation := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	operators := []model.MobileOperator{
+		{CountryIdentifier: "mk", PrefixFormat: "^77[0-9]{6}$", MNO: "Telekom", EffectiveTo: &reallocation},
+		{CountryIdentifier: "mk", PrefixFormat: "^77[0-9]{6}$", MNO: "A1", EffectiveFrom: &reallocation},
+	}
+	tt := []struct{
+		Name string
+		At time.Time
+		ExpectedMNO string
+	}{
+		{
+			Name:			"Before the reallocation",
+			At:				reallocation.Add(-time.Second),
+			ExpectedMNO:	"Telekom",
+		},
+		{
+			Name:			"When the reallocation takes effect",
+			At:				reallocation,
+			ExpectedMNO:	"A1",
+		},
+	}
+
+	plan := NewPlan(nil, operators)
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Act
+			operator, found := plan.LookupOperator("mk", "77123456", test.At)
+
+			//Assert
+			if !found{
+				t.Fatal("Error in TestPlanLookupOperatorEffective:\n expected an operator\n got none")
+			}
+			if operator.MNO != test.ExpectedMNO{
+				t.Errorf("Error in TestPlanLookupOperatorEffective:\n expected %s\n got %s", test.ExpectedMNO, operator.MNO)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
+
+// sharedCodeCountries share the calling codes 1, 7 and 44 and are told apart by area code
+var sharedCodeCountries = []model.Country{
+	{CountryNumberFormat: "^1[2-9][0-9]{2}[2-9][0-9]{6}$", CountryCode: "1", CountryIdentifier: "us", CountryCodeLength: 1},
+	{CountryNumberFormat: "^1(204|416|514|604)[2-9][0-9]{6}$", CountryCode: "1", CountryIdentifier: "ca", CountryCodeLength: 1, Priority: 1},
+	{CountryNumberFormat: "^1246[0-9]{7}$", CountryCode: "1", CountryIdentifier: "bb", CountryCodeLength: 1, Priority: 1},
+	{CountryNumberFormat: "^7[3489][0-9]{9}$", CountryCode: "7", CountryIdentifier: "ru", CountryCodeLength: 1},
+	{CountryNumberFormat: "^7[67][0-9]{9}$", CountryCode: "7", CountryIdentifier: "kz", CountryCodeLength: 1},
+	{CountryNumberFormat: "^44[0-9]{10}$", CountryCode: "44", CountryIdentifier: "gb", CountryCodeLength: 2},
+	{CountryNumberFormat: "^447(509|700|797|829|937)[0-9]{6}$", CountryCode: "44", CountryIdentifier: "je", CountryCodeLength: 2, Priority: 1},
+	{CountryNumberFormat: "^447(524|624|924)[0-9]{6}$", CountryCode: "44", CountryIdentifier: "im", CountryCodeLength: 2, Priority: 1},
+}
+
+func TestPlanSharedCallingCodes(t *testing.T) {
+
+	tt := []struct{
+		Name string
+		Input string
+		ExpectedCI string
+	}{
+		{Name: "United States", Input: "12125550123", ExpectedCI: "us"},
+		{Name: "Canada", Input: "14165550123", ExpectedCI: "ca"},
+		{Name: "Barbados", Input: "12462301234", ExpectedCI: "bb"},
+		{Name: "Russia", Input: "79161234567", ExpectedCI: "ru"},
+		{Name: "Kazakhstan", Input: "77011234567", ExpectedCI: "kz"},
+		{Name: "United Kingdom", Input: "447400123456", ExpectedCI: "gb"},
+		{Name: "Jersey", Input: "447797123456", ExpectedCI: "je"},
+		{Name: "Isle of Man", Input: "447624123456", ExpectedCI: "im"},
+	}
+
+	plan := NewPlan(sharedCodeCountries, nil)
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Act
+			country, found := plan.LookupCountry(test.Input, now)
+			byCode, foundByCode := plan.CountryByCallingCode(test.Input[:len(test.Input) - 2], now)
+
+			//Assert
+			if !found || country.CountryIdentifier != test.ExpectedCI{
+				t.Errorf("Error in TestPlanSharedCallingCodes:\n expected %s\n got %v", test.ExpectedCI, country)
+			}
+			if !foundByCode || byCode.CountryIdentifier != test.ExpectedCI{
+				t.Errorf("Error in TestPlanSharedCallingCodes:\n expected %s for the number cut short\n got %v", test.ExpectedCI, byCode)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
@@ -0,0 +1,72 @@
+package numplan
+
+// Trie is a digit trie mapping number prefixes to the rules stored under them
+type Trie struct {
+	root trieNode
+}
+
+type trieNode struct {
+	children [10]*trieNode
+	rules []int
+}
+
+// Insert stores a rule index under the given digit prefix
+func (t *Trie) Insert(prefix string, rule int){
+
+	node := &t.root
+	for _, r := range prefix{
+		d := r - '0'
+		if node.children[d] == nil{
+			node.children[d] = &trieNode{}
+		}
+		node = node.children[d]
+	}
+	node.rules = append(node.rules, rule)
+}
+
+// Candidates returns every rule stored under a prefix of the number
+func (t *Trie) Candidates(number string) []int{
+
+	node := &t.root
+	candidates := append([]int(nil), node.rules...)
+	for _, r := range number{
+		if r < '0' || r > '9'{
+			break
+		}
+		node = node.children[r - '0']
+		if node == nil{
+			break
+		}
+		candidates = append(candidates, node.rules...)
+	}
+	return candidates
+}
+
+// Completions returns every rule stored under a prefix of the digits or under a prefix
+// the digits are the start of, meaning the rules a number starting with them can match
+func (t *Trie) Completions(digits string) []int{
+
+	node := &t.root
+	candidates := append([]int(nil), node.rules...)
+	for _, r := range digits{
+		if r < '0' || r > '9'{
+			return candidates
+		}
+		node = node.children[r - '0']
+		if node == nil{
+			return candidates
+		}
+		candidates = append(candidates, node.rules...)
+	}
+	var below func(n *trieNode)
+	below = func(n *trieNode){
+		for _, child := range n.children{
+			if child != nil{
+				candidates = append(candidates, child.rules...)
+				below(child)
+			}
+		}
+	}
+	below(node)
+	return candidates
+}
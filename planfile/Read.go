@@ -0,0 +1,361 @@
+// Package planfile reads and writes numbering plan files holding the countries and
+// operator ranges of the plan, so that they can be imported and exported in bulk
+package planfile
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/errs"
+	"gopkg.in/yaml.v2"
+)
+
+// SchemaVersion is the version of the file layout written by this package. Files
+// with a newer version are rejected, files without one are read as this version.
+// Version 2 names the network operator of a range by its brand name in operator,
+// which version 1 referenced by the database id in operator_id. Both are still read
+const SchemaVersion = 2
+
+// Supported file formats
+const (
+	FormatCSV = "csv"
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// Columns of CSV files, which are matched by name so that they can come in any order
+// and the ones that don't apply to a kind of rule can be left out or empty
+var Columns = []string{
+	"kind", "country_identifier", "pattern", "excluded_pattern", "priority", "effective_from", "effective_to",
+	"country_code", "country_code_length", "trunk_prefix", "international_prefix", "nsn_min_length", "nsn_max_length", "number_grouping",
+	"operator", "prefix_length", "number_type",
+}
+
+// Record is a rule read from a file. Kind is model.RuleKindCountry or model.RuleKindOperator,
+// and Err is set when the row couldn't be read, in which case the rule only holds
+// the fields that could
+type Record struct {
+	// Row is the line of a CSV file, or the position of the rule in its list in a JSON or YAML file
+	Row int
+	Kind string
+	Country model.Country
+	Operator model.MobileOperator
+	Err error
+}
+
+// planFile is the layout of JSON and YAML files
+type planFile struct {
+	SchemaVersion int				`json:"schema_version" yaml:"schema_version"`
+	Countries []countryEntry		`json:"countries" yaml:"countries"`
+	Operators []operatorEntry		`json:"operators" yaml:"operators"`
+}
+
+type countryEntry struct {
+	CountryIdentifier string		`json:"country_identifier" yaml:"country_identifier"`
+	Pattern string					`json:"pattern" yaml:"pattern"`
+	ExcludedPattern string			`json:"excluded_pattern,omitempty" yaml:"excluded_pattern,omitempty"`
+	Priority int					`json:"priority" yaml:"priority"`
+	EffectiveFrom *time.Time		`json:"effective_from,omitempty" yaml:"effective_from,omitempty"`
+	EffectiveTo *time.Time			`json:"effective_to,omitempty" yaml:"effective_to,omitempty"`
+	CountryCode string				`json:"country_code" yaml:"country_code"`
+	CountryCodeLength int			`json:"country_code_length" yaml:"country_code_length"`
+	TrunkPrefix string				`json:"trunk_prefix" yaml:"trunk_prefix"`
+	InternationalPrefix string		`json:"international_prefix" yaml:"international_prefix"`
+	NSNMinLength int				`json:"nsn_min_length" yaml:"nsn_min_length"`
+	NSNMaxLength int				`json:"nsn_max_length" yaml:"nsn_max_length"`
+	NumberGrouping string			`json:"number_grouping" yaml:"number_grouping"`
+}
+
+type operatorEntry struct {
+	CountryIdentifier string		`json:"country_identifier" yaml:"country_identifier"`
+	Pattern string					`json:"pattern" yaml:"pattern"`
+	ExcludedPattern string			`json:"excluded_pattern,omitempty" yaml:"excluded_pattern,omitempty"`
+	Priority int					`json:"priority" yaml:"priority"`
+	EffectiveFrom *time.Time		`json:"effective_from,omitempty" yaml:"effective_from,omitempty"`
+	EffectiveTo *time.Time			`json:"effective_to,omitempty" yaml:"effective_to,omitempty"`
+	Operator string					`json:"operator,omitempty" yaml:"operator,omitempty"`
+	OperatorID int					`json:"operator_id,omitempty" yaml:"operator_id,omitempty"`
+	PrefixLength int				`json:"prefix_length" yaml:"prefix_length"`
+	NumberType string				`json:"number_type" yaml:"number_type"`
+}
+
+// Read reads the rules of a numbering plan file in the format. Errors in single rows
+// are reported in their records, while a file that can't be read at all returns an
+// InvalidFileError
+func Read(file io.Reader, format string) ([]Record, error){
+
+	switch strings.ToLower(format){
+	case FormatCSV:
+		return readCSV(file)
+	case FormatJSON:
+		var plan planFile
+		decoder := json.NewDecoder(file)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&plan); err != nil{
+			return nil, errs.NewInvalidFileError(err.Error())
+		}
+		return planRecords(plan)
+	case FormatYAML, "yml":
+		var plan planFile
+		decoder := yaml.NewDecoder(file)
+		decoder.SetStrict(true)
+		if err := decoder.Decode(&plan); err != nil{
+			return nil, errs.NewInvalidFileError(err.Error())
+		}
+		return planRecords(plan)
+	}
+	return nil, errs.NewInvalidFileError("Unsupported numbering plan format " + format)
+}
+
+// FormatOf returns the format of a file by its extension
+func FormatOf(filename string) string{
+
+	dot := strings.LastIndex(filename, ".")
+	if dot < 0{
+		return ""
+	}
+	return strings.ToLower(filename[dot+1:])
+}
+
+// planRecords returns the rules of a JSON or YAML file
+func planRecords(plan planFile) ([]Record, error){
+
+	if err := checkVersion(plan.SchemaVersion); err != nil{
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(plan.Countries) + len(plan.Operators))
+	for i, c := range plan.Countries{
+		records = append(records, Record{Row: i+1, Kind: model.RuleKindCountry, Country: model.Country{
+			CountryNumberFormat: c.Pattern,
+			ExcludedFormat: c.ExcludedPattern,
+			CountryCode: c.CountryCode,
+			CountryIdentifier: strings.ToLower(c.CountryIdentifier),
+			CountryCodeLength: c.CountryCodeLength,
+			TrunkPrefix: c.TrunkPrefix,
+			InternationalPrefix: c.InternationalPrefix,
+			NSNMinLength: c.NSNMinLength,
+			NSNMaxLength: c.NSNMaxLength,
+			NumberGrouping: c.NumberGrouping,
+			Priority: c.Priority,
+			EffectiveFrom: truncate(c.EffectiveFrom),
+			EffectiveTo: truncate(c.EffectiveTo),
+		}})
+	}
+	for i, o := range plan.Operators{
+		records = append(records, Record{Row: i+1, Kind: model.RuleKindOperator, Operator: model.MobileOperator{
+			CountryIdentifier: strings.ToLower(o.CountryIdentifier),
+			PrefixFormat: o.Pattern,
+			ExcludedFormat: o.ExcludedPattern,
+			MNO: o.Operator,
+			OperatorID: o.OperatorID,
+			PrefixLength: o.PrefixLength,
+			NumberType: o.NumberType,
+			Priority: o.Priority,
+			EffectiveFrom: truncate(o.EffectiveFrom),
+			EffectiveTo: truncate(o.EffectiveTo),
+		}})
+	}
+	return records, nil
+}
+
+func readCSV(file io.Reader) ([]Record, error){
+
+	buffered := bufio.NewReader(file)
+	line := 0
+	// The schema version is an optional comment line in front of the header
+	if first, err := buffered.Peek(1); err == nil && first[0] == '#'{
+		comment, err := buffered.ReadString('\n')
+		if err != nil && err != io.EOF{
+			return nil, errs.NewInvalidFileError(err.Error())
+		}
+		line++
+		version, err := parseVersionComment(comment)
+		if err != nil{
+			return nil, err
+		}
+		if err := checkVersion(version); err != nil{
+			return nil, err
+		}
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err == io.EOF{
+		return nil, errs.NewInvalidFileError("The file is empty")
+	}
+	if err != nil{
+		return nil, errs.NewInvalidFileError(err.Error())
+	}
+	line++
+	columns := make(map[string]int, len(header))
+	for i, name := range header{
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"kind", "pattern"}{
+		if _, ok := columns[required]; !ok{
+			return nil, errs.NewInvalidFileError("The header is missing the " + required + " column")
+		}
+	}
+
+	var records []Record
+	for {
+		row, err := reader.Read()
+		if err == io.EOF{
+			break
+		}
+		if err != nil{
+			return nil, errs.NewInvalidFileError(err.Error())
+		}
+		line++
+		fields := csvRow{row, columns}
+		if fields.empty(){
+			continue
+		}
+		records = append(records, fields.record(line))
+	}
+	return records, nil
+}
+
+// csvRow reads the fields of a CSV row by column name
+type csvRow struct {
+	values []string
+	columns map[string]int
+}
+
+func (r csvRow) get(name string) string{
+
+	i, ok := r.columns[name]
+	if !ok || i >= len(r.values){
+		return ""
+	}
+	return strings.TrimSpace(r.values[i])
+}
+
+func (r csvRow) empty() bool{
+
+	for _, value := range r.values{
+		if strings.TrimSpace(value) != ""{
+			return false
+		}
+	}
+	return true
+}
+
+func (r csvRow) record(line int) Record{
+
+	record := Record{Row: line, Kind: strings.ToLower(r.get("kind"))}
+	var fieldErr error
+	integer := func(name string) int{
+		value := r.get(name)
+		if value == "" || fieldErr != nil{
+			return 0
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil{
+			fieldErr = fmt.Errorf("%s must be a number, got %q", name, value)
+		}
+		return parsed
+	}
+	timestamp := func(name string) *time.Time{
+		value := r.get(name)
+		if value == "" || fieldErr != nil{
+			return nil
+		}
+		parsed, err := parseTime(value)
+		if err != nil{
+			fieldErr = fmt.Errorf("%s must be a date like 2006-01-02 or 2006-01-02T15:04:05Z, got %q", name, value)
+		}
+		return parsed
+	}
+
+	switch record.Kind{
+	case model.RuleKindCountry:
+		record.Country = model.Country{
+			CountryNumberFormat: r.get("pattern"),
+			ExcludedFormat: r.get("excluded_pattern"),
+			CountryCode: r.get("country_code"),
+			CountryIdentifier: strings.ToLower(r.get("country_identifier")),
+			CountryCodeLength: integer("country_code_length"),
+			TrunkPrefix: r.get("trunk_prefix"),
+			InternationalPrefix: r.get("international_prefix"),
+			NSNMinLength: integer("nsn_min_length"),
+			NSNMaxLength: integer("nsn_max_length"),
+			NumberGrouping: r.get("number_grouping"),
+			Priority: integer("priority"),
+			EffectiveFrom: timestamp("effective_from"),
+			EffectiveTo: timestamp("effective_to"),
+		}
+	case model.RuleKindOperator:
+		record.Operator = model.MobileOperator{
+			CountryIdentifier: strings.ToLower(r.get("country_identifier")),
+			PrefixFormat: r.get("pattern"),
+			ExcludedFormat: r.get("excluded_pattern"),
+			MNO: r.get("operator"),
+			OperatorID: integer("operator_id"),
+			PrefixLength: integer("prefix_length"),
+			NumberType: r.get("number_type"),
+			Priority: integer("priority"),
+			EffectiveFrom: timestamp("effective_from"),
+			EffectiveTo: timestamp("effective_to"),
+		}
+	default:
+		fieldErr = fmt.Errorf("kind must be %s or %s, got %q", model.RuleKindCountry, model.RuleKindOperator, record.Kind)
+	}
+	record.Err = fieldErr
+	return record
+}
+
+// parseVersionComment reads a comment line like "# schema_version: 1"
+func parseVersionComment(comment string) (int, error){
+
+	name, value, ok := strings.Cut(strings.TrimSpace(strings.TrimPrefix(comment, "#")), ":")
+	if !ok || strings.TrimSpace(name) != "schema_version"{
+		return 0, errs.NewInvalidFileError("Expected a schema_version comment in front of the header")
+	}
+	version, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil{
+		return 0, errs.NewInvalidFileError("Invalid schema version " + strings.TrimSpace(value))
+	}
+	return version, nil
+}
+
+func checkVersion(version int) (error){
+
+	if version > SchemaVersion{
+		return errs.NewInvalidFileError(fmt.Sprintf("Unsupported schema version %d, the newest supported one is %d", version, SchemaVersion))
+	}
+	return nil
+}
+
+// parseTime parses a UTC date or an RFC 3339 timestamp
+func parseTime(value string) (*time.Time, error){
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil{
+		if parsed, err = time.Parse("2006-01-02", value); err != nil{
+			return nil, err
+		}
+	}
+	return truncate(&parsed), nil
+}
+
+// truncate drops the fractions of a second the database doesn't keep, so that
+// imported times compare equal to the saved ones
+func truncate(t *time.Time) *time.Time{
+
+	if t == nil{
+		return nil
+	}
+	truncated := t.UTC().Truncate(time.Second)
+	return &truncated
+}
@@ -0,0 +1,113 @@
+package planfile
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/errs"
+)
+
+func TestReadCSV(t *testing.T) {
+
+	//Arrange
+	file := "# schema_version: 1\n" +
+		"kind,pattern,country_identifier,country_code,country_code_length,operator_id,prefix_length,effective_from\n" +
+		"country,^389[0-9]{8}$,MK,389,3,,,\n" +
+		"operator,^7[0-9]{7}$,mk,,,1,2,2024-03-01\n" +
+		",,,,,,,\n" +
+		"operator,^8[0-9]{7}$,mk,,,one,2,\n"
+
+	//Act
+	records, err := Read(strings.NewReader(file), FormatCSV)
+
+	//Assert
+	if err != nil{
+		t.Fatalf("Error in TestReadCSV:\n expected %s\n got %s", "nil", err)
+	}
+	if len(records) != 3{
+		t.Fatalf("Error in TestReadCSV:\n expected %d records\n got %d", 3, len(records))
+	}
+	if records[0].Kind != model.RuleKindCountry || records[0].Country.CountryIdentifier != "mk" || records[0].Country.CountryCodeLength != 3{
+		t.Errorf("Error in TestReadCSV:\n expected the mk country\n got %v", records[0])
+	}
+	from := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	operator := records[1].Operator
+	if records[1].Row != 4 || operator.OperatorID != 1 || operator.EffectiveFrom == nil || !operator.EffectiveFrom.Equal(from){
+		t.Errorf("Error in TestReadCSV:\n expected the range of operator 1 from %s on row 4\n got %v", from, records[1])
+	}
+	if records[2].Err == nil || records[2].Row != 6{
+		t.Errorf("Error in TestReadCSV:\n expected an error on row 6\n got %v", records[2])
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+
+	//Arrange
+	file := `{"schema_version": 1,
+		"countries": [{"pattern": "^389[0-9]{8}$", "country_identifier": "mk", "country_code": "389", "country_code_length": 3, "effective_to": "2030-01-01T00:00:00.5Z"}],
+		"operators": [{"pattern": "^7[0-9]{7}$", "country_identifier": "mk", "operator_id": 1, "prefix_length": 2, "number_type": "mobile"}]}`
+
+	//Act
+	records, err := Read(strings.NewReader(file), FormatJSON)
+
+	//Assert
+	if err != nil{
+		t.Fatalf("Error in TestReadJSON:\n expected %s\n got %s", "nil", err)
+	}
+	if len(records) != 2 || records[0].Kind != model.RuleKindCountry || records[1].Kind != model.RuleKindOperator{
+		t.Fatalf("Error in TestReadJSON:\n expected a country and an operator\n got %v", records)
+	}
+	to := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if records[0].Country.EffectiveTo == nil || !records[0].Country.EffectiveTo.Equal(to){
+		t.Errorf("Error in TestReadJSON:\n expected %s\n got %v", to, records[0].Country.EffectiveTo)
+	}
+	if records[1].Operator.PrefixFormat != "^7[0-9]{7}$" || records[1].Row != 1{
+		t.Errorf("Error in TestReadJSON:\n expected %s\n got %v", "^7[0-9]{7}$", records[1])
+	}
+}
+
+func TestReadInvalidFile(t *testing.T) {
+
+	tt := []struct{
+		Name string
+		File string
+		Format string
+	}{
+		{
+			Name:		"Newer schema version",
+			File:		"# schema_version: 3\nkind,pattern\n",
+			Format:		FormatCSV,
+		},
+		{
+			Name:		"Missing pattern column",
+			File:		"kind,country_identifier\ncountry,mk\n",
+			Format:		FormatCSV,
+		},
+		{
+			Name:		"Unknown JSON field",
+			File:		`{"schema_version": 1, "ranges": []}`,
+			Format:		FormatJSON,
+		},
+		{
+			Name:		"Unsupported format",
+			File:		"",
+			Format:		"xlsx",
+		},
+	}
+
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Act
+			_, err := Read(strings.NewReader(test.File), test.Format)
+
+			//Assert
+			if _, ok := err.(*errs.InvalidFileError); !ok{
+				t.Errorf("Error in TestReadInvalidFile:\n expected %s\n got %v", "InvalidFileError", err)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
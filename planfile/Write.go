@@ -0,0 +1,177 @@
+package planfile
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/errs"
+	"gopkg.in/yaml.v2"
+)
+
+// ContentType returns the media type of files in the format
+func ContentType(format string) string{
+
+	switch strings.ToLower(format){
+	case FormatCSV:
+		return "text/csv"
+	case FormatJSON:
+		return "application/json"
+	case FormatYAML, "yml":
+		return "application/yaml"
+	}
+	return "application/octet-stream"
+}
+
+// Write writes the countries and operator ranges as a numbering plan file in the format,
+// headed by the schema version. Rules are sorted by country, pattern and the time they
+// take effect, so that exports of the same plan are identical and can be diffed. Ranges
+// name their network operator by brand name, which stays the same across databases
+func Write(file io.Writer, format string, countries []model.Country, operators []model.MobileOperator) (error){
+
+	plan := planFile{SchemaVersion: SchemaVersion}
+	for _, c := range countries{
+		plan.Countries = append(plan.Countries, countryEntry{
+			CountryIdentifier: c.CountryIdentifier,
+			Pattern: c.CountryNumberFormat,
+			ExcludedPattern: c.ExcludedFormat,
+			Priority: c.Priority,
+			EffectiveFrom: truncate(c.EffectiveFrom),
+			EffectiveTo: truncate(c.EffectiveTo),
+			CountryCode: c.CountryCode,
+			CountryCodeLength: c.CountryCodeLength,
+			TrunkPrefix: c.TrunkPrefix,
+			InternationalPrefix: c.InternationalPrefix,
+			NSNMinLength: c.NSNMinLength,
+			NSNMaxLength: c.NSNMaxLength,
+			NumberGrouping: c.NumberGrouping,
+		})
+	}
+	for _, o := range operators{
+		plan.Operators = append(plan.Operators, operatorEntry{
+			CountryIdentifier: o.CountryIdentifier,
+			Pattern: o.PrefixFormat,
+			ExcludedPattern: o.ExcludedFormat,
+			Priority: o.Priority,
+			EffectiveFrom: truncate(o.EffectiveFrom),
+			EffectiveTo: truncate(o.EffectiveTo),
+			Operator: o.MNO,
+			PrefixLength: o.PrefixLength,
+			NumberType: o.NumberType,
+		})
+	}
+	sort.SliceStable(plan.Countries, func(i, j int) bool {
+		a, b := plan.Countries[i], plan.Countries[j]
+		return sortsBefore(a.CountryIdentifier, a.Pattern, a.EffectiveFrom, b.CountryIdentifier, b.Pattern, b.EffectiveFrom)
+	})
+	sort.SliceStable(plan.Operators, func(i, j int) bool {
+		a, b := plan.Operators[i], plan.Operators[j]
+		return sortsBefore(a.CountryIdentifier, a.Pattern, a.EffectiveFrom, b.CountryIdentifier, b.Pattern, b.EffectiveFrom)
+	})
+
+	switch strings.ToLower(format){
+	case FormatCSV:
+		return writeCSV(file, plan)
+	case FormatJSON:
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(plan)
+	case FormatYAML, "yml":
+		return yaml.NewEncoder(file).Encode(plan)
+	}
+	return errs.NewInvalidFileError("Unsupported numbering plan format " + format)
+}
+
+// sortsBefore orders rules by country and pattern, and rules with the same pattern by
+// when they take effect, with the ones in effect since always first
+func sortsBefore(ciA string, patternA string, fromA *time.Time, ciB string, patternB string, fromB *time.Time) bool{
+
+	if ciA != ciB{
+		return ciA < ciB
+	}
+	if patternA != patternB{
+		return patternA < patternB
+	}
+	if fromA == nil || fromB == nil{
+		return fromA == nil && fromB != nil
+	}
+	return fromA.Before(*fromB)
+}
+
+func writeCSV(file io.Writer, plan planFile) (error){
+
+	if _, err := fmt.Fprintf(file, "# schema_version: %d\n", plan.SchemaVersion); err != nil{
+		return err
+	}
+	writer := csv.NewWriter(file)
+	if err := writer.Write(Columns); err != nil{
+		return err
+	}
+	for _, c := range plan.Countries{
+		row := csvValues{
+			"kind": model.RuleKindCountry,
+			"country_identifier": c.CountryIdentifier,
+			"pattern": c.Pattern,
+			"excluded_pattern": c.ExcludedPattern,
+			"priority": strconv.Itoa(c.Priority),
+			"effective_from": formatTime(c.EffectiveFrom),
+			"effective_to": formatTime(c.EffectiveTo),
+			"country_code": c.CountryCode,
+			"country_code_length": strconv.Itoa(c.CountryCodeLength),
+			"trunk_prefix": c.TrunkPrefix,
+			"international_prefix": c.InternationalPrefix,
+			"nsn_min_length": strconv.Itoa(c.NSNMinLength),
+			"nsn_max_length": strconv.Itoa(c.NSNMaxLength),
+			"number_grouping": c.NumberGrouping,
+		}
+		if err := writer.Write(row.ordered()); err != nil{
+			return err
+		}
+	}
+	for _, o := range plan.Operators{
+		row := csvValues{
+			"kind": model.RuleKindOperator,
+			"country_identifier": o.CountryIdentifier,
+			"pattern": o.Pattern,
+			"excluded_pattern": o.ExcludedPattern,
+			"priority": strconv.Itoa(o.Priority),
+			"effective_from": formatTime(o.EffectiveFrom),
+			"effective_to": formatTime(o.EffectiveTo),
+			"operator": o.Operator,
+			"prefix_length": strconv.Itoa(o.PrefixLength),
+			"number_type": o.NumberType,
+		}
+		if err := writer.Write(row.ordered()); err != nil{
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvValues holds the fields of a CSV row by column name
+type csvValues map[string]string
+
+// ordered returns the fields in the order of Columns, leaving the missing ones empty
+func (v csvValues) ordered() []string{
+
+	row := make([]string, len(Columns))
+	for i, column := range Columns{
+		row[i] = v[column]
+	}
+	return row
+}
+
+func formatTime(t *time.Time) string{
+
+	if t == nil{
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
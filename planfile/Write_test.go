@@ -0,0 +1,94 @@
+package planfile
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/errs"
+)
+
+func TestWriteRoundTrip(t *testing.T) {
+
+	//Arrange
+	from := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2030, time.January, 1, 12, 30, 0, 0, time.UTC)
+	countries := []model.Country{
+		{CountryNumberFormat: "^48[0-9]{9}$", CountryCode: "48", CountryIdentifier: "pl", CountryCodeLength: 2, InternationalPrefix: "00"},
+		{CountryNumberFormat: "^389[0-9]{8}$", ExcludedFormat: "^3890", CountryCode: "389", CountryIdentifier: "mk", CountryCodeLength: 3, TrunkPrefix: "0", InternationalPrefix: "00", NSNMinLength: 8, NSNMaxLength: 8, NumberGrouping: "2-3-3", EffectiveTo: &to},
+	}
+	operators := []model.MobileOperator{
+		{CountryIdentifier: "mk", PrefixFormat: "^7[0-9]{7}$", MNO: "Telekom", PrefixLength: 2, NumberType: model.NumberTypeMobile, EffectiveFrom: &from},
+		{CountryIdentifier: "mk", PrefixFormat: "^7[0-9]{7}$", MNO: "A1", PrefixLength: 2, NumberType: model.NumberTypeMobile, Priority: 1, EffectiveTo: &from},
+	}
+
+	for _, format := range []string{FormatCSV, FormatJSON, FormatYAML}{
+		fn := func(t *testing.T){
+
+			//Act
+			var file bytes.Buffer
+			err := Write(&file, format, countries, operators)
+			if err != nil{
+				t.Fatalf("Error in TestWriteRoundTrip:\n expected %s\n got %s", "nil", err)
+			}
+			records, err := Read(strings.NewReader(file.String()), format)
+
+			//Assert
+			if err != nil{
+				t.Fatalf("Error in TestWriteRoundTrip:\n expected %s\n got %s", "nil", err)
+			}
+			if len(records) != 4{
+				t.Fatalf("Error in TestWriteRoundTrip:\n expected %d records\n got %d", 4, len(records))
+			}
+			if !reflect.DeepEqual(records[0].Country, countries[1]) || !reflect.DeepEqual(records[1].Country, countries[0]){
+				t.Errorf("Error in TestWriteRoundTrip:\n expected the mk and pl countries\n got %v and %v", records[0].Country, records[1].Country)
+			}
+			// The rule in effect since always sorts before the one that replaces it
+			if !reflect.DeepEqual(records[2].Operator, operators[1]) || !reflect.DeepEqual(records[3].Operator, operators[0]){
+				t.Errorf("Error in TestWriteRoundTrip:\n expected the A1 range then the Telekom one\n got %v and %v", records[2].Operator, records[3].Operator)
+			}
+		}
+		t.Run(format, fn)
+	}
+}
+
+func TestWriteSchemaVersion(t *testing.T) {
+
+	tt := []struct{
+		Format string
+		Header string
+	}{
+		{FormatCSV, "# schema_version: 2\n"},
+		{FormatJSON, "{\n  \"schema_version\": 2,"},
+		{FormatYAML, "schema_version: 2\n"},
+	}
+
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Act
+			var file bytes.Buffer
+			err := Write(&file, test.Format, nil, nil)
+
+			//Assert
+			if err != nil || !strings.HasPrefix(file.String(), test.Header){
+				t.Errorf("Error in TestWriteSchemaVersion:\n expected %q\n got %q", test.Header, file.String())
+			}
+		}
+		t.Run(test.Format, fn)
+	}
+}
+
+func TestWriteUnsupportedFormat(t *testing.T) {
+
+	//Act
+	err := Write(&bytes.Buffer{}, "xlsx", nil, nil)
+
+	//Assert
+	if _, ok := err.(*errs.InvalidFileError); !ok{
+		t.Errorf("Error in TestWriteUnsupportedFormat:\n expected %s\n got %v", "InvalidFileError", err)
+	}
+}
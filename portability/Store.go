@@ -0,0 +1,268 @@
+// Package portability keeps the numbers ported away from the operator holding their range
+package portability
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/robesmi/MSISDNApp/model/errs"
+)
+
+const (
+	// removed marks a number in the pending changes that is no longer ported
+	removed = 0
+	// maxOperators is how many distinct operators the store can reference
+	maxOperators = 1<<16 - 1
+	// minCompaction is the fewest pending changes merged into the sorted entries
+	minCompaction = 1 << 16
+)
+
+// Store maps MSISDNs to the operator they were ported to. To stay small with tens of
+// millions of entries the numbers are kept as sorted integers next to an operator
+// index, and lookups are a binary search. Incremental changes go to a map of pending
+// changes that is merged into the sorted entries once it grows large enough
+type Store struct {
+	mu sync.RWMutex
+	keys []uint64
+	operators []uint16
+	pending map[uint64]uint16
+	names operatorNames
+}
+
+// Change sets the operator a number was ported to, or removes the number from the
+// store when the MNO is empty
+type Change struct {
+	MSISDN string
+	MNO string
+}
+
+func NewStore() *Store{
+	return &Store{
+		pending: make(map[uint64]uint16),
+		names: newOperatorNames(),
+	}
+}
+
+// Lookup returns the operator the MSISDN was ported to, if it was
+func (s *Store) Lookup(msisdn string) (string, bool){
+
+	key, err := parseMSISDN(msisdn)
+	if err != nil{
+		return "", false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if op, ok := s.pending[key]; ok{
+		if op == removed{
+			return "", false
+		}
+		return s.names.list[op], true
+	}
+	if i, ok := s.search(key); ok{
+		return s.names.list[s.operators[i]], true
+	}
+	return "", false
+}
+
+// Len returns how many numbers are ported
+func (s *Store) Len() int{
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	count := len(s.keys)
+	for key, op := range s.pending{
+		_, inBase := s.search(key)
+		switch {
+		case op == removed && inBase:
+			count--
+		case op != removed && !inBase:
+			count++
+		}
+	}
+	return count
+}
+
+// Apply adds, updates and removes the ported numbers in the changes. Either all
+// changes are applied or, when one of them is invalid, none are
+func (s *Store) Apply(changes []Change) (error){
+
+	keys := make([]uint64, len(changes))
+	for i, change := range changes{
+		key, err := parseMSISDN(change.MSISDN)
+		if err != nil{
+			return err
+		}
+		keys[i] = key
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ops := make([]uint16, len(changes))
+	for i, change := range changes{
+		op, err := s.names.id(change.MNO)
+		if err != nil{
+			return err
+		}
+		ops[i] = op
+	}
+	for i, key := range keys{
+		s.pending[key] = ops[i]
+	}
+	if len(s.pending) >= minCompaction && len(s.pending) >= len(s.keys)/8{
+		s.compact()
+	}
+	return nil
+}
+
+// Replace swaps the contents of the store with the numbers added to the builder
+func (s *Store) Replace(b *Builder){
+
+	keys, operators := b.build()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+	s.operators = operators
+	s.names = b.names
+	s.pending = make(map[uint64]uint16)
+}
+
+// search returns the position of the key in the sorted entries and whether it's there
+func (s *Store) search(key uint64) (int, bool){
+
+	i := sort.Search(len(s.keys), func(i int) bool { return s.keys[i] >= key })
+	return i, i < len(s.keys) && s.keys[i] == key
+}
+
+// compact merges the pending changes into the sorted entries
+func (s *Store) compact(){
+
+	changed := make([]uint64, 0, len(s.pending))
+	for key := range s.pending{
+		changed = append(changed, key)
+	}
+	sort.Slice(changed, func(i, j int) bool { return changed[i] < changed[j] })
+
+	keys := make([]uint64, 0, len(s.keys) + len(changed))
+	operators := make([]uint16, 0, len(s.keys) + len(changed))
+	i, j := 0, 0
+	for i < len(s.keys) || j < len(changed){
+		switch {
+		case j == len(changed) || (i < len(s.keys) && s.keys[i] < changed[j]):
+			keys = append(keys, s.keys[i])
+			operators = append(operators, s.operators[i])
+			i++
+		default:
+			if i < len(s.keys) && s.keys[i] == changed[j]{
+				i++
+			}
+			if op := s.pending[changed[j]]; op != removed{
+				keys = append(keys, changed[j])
+				operators = append(operators, op)
+			}
+			j++
+		}
+	}
+	s.keys = keys
+	s.operators = operators
+	s.pending = make(map[uint64]uint16)
+}
+
+// Builder collects ported numbers for a full replacement of a store
+type Builder struct {
+	keys []uint64
+	operators []uint16
+	names operatorNames
+}
+
+func NewBuilder() *Builder{
+	return &Builder{names: newOperatorNames()}
+}
+
+// Add records the operator the MSISDN was ported to. When a number is added more
+// than once the last operator wins
+func (b *Builder) Add(msisdn string, mno string) (error){
+
+	key, err := parseMSISDN(msisdn)
+	if err != nil{
+		return err
+	}
+	if mno == ""{
+		return errs.NewInvalidNumberError("Missing the operator of ported number " + msisdn)
+	}
+	op, err := b.names.id(mno)
+	if err != nil{
+		return err
+	}
+	b.keys = append(b.keys, key)
+	b.operators = append(b.operators, op)
+	return nil
+}
+
+func (b *Builder) build() ([]uint64, []uint16){
+
+	sort.Stable(byKey{b.keys, b.operators})
+	keys := b.keys[:0]
+	operators := b.operators[:0]
+	for i, key := range b.keys{
+		if len(keys) != 0 && keys[len(keys)-1] == key{
+			operators[len(operators)-1] = b.operators[i]
+			continue
+		}
+		keys = append(keys, key)
+		operators = append(operators, b.operators[i])
+	}
+	return keys, operators
+}
+
+type byKey struct {
+	keys []uint64
+	operators []uint16
+}
+
+func (b byKey) Len() int { return len(b.keys) }
+func (b byKey) Less(i, j int) bool { return b.keys[i] < b.keys[j] }
+func (b byKey) Swap(i, j int){
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+	b.operators[i], b.operators[j] = b.operators[j], b.operators[i]
+}
+
+// operatorNames interns operator names so each entry only needs a small index
+type operatorNames struct {
+	list []string
+	ids map[string]uint16
+}
+
+func newOperatorNames() operatorNames{
+	return operatorNames{
+		list: []string{""},
+		ids: map[string]uint16{"": removed},
+	}
+}
+
+func (n *operatorNames) id(name string) (uint16, error){
+
+	if id, ok := n.ids[name]; ok{
+		return id, nil
+	}
+	if len(n.list) > maxOperators{
+		return 0, errs.NewUnexpectedError("Too many operators in the portability store")
+	}
+	id := uint16(len(n.list))
+	n.list = append(n.list, name)
+	n.ids[name] = id
+	return id, nil
+}
+
+func parseMSISDN(msisdn string) (uint64, error){
+
+	if len(msisdn) < 7 || len(msisdn) > 15 || msisdn[0] == '0'{
+		return 0, errs.NewInvalidNumberError("Invalid ported number " + msisdn)
+	}
+	key, err := strconv.ParseUint(msisdn, 10, 64)
+	if err != nil{
+		return 0, errs.NewInvalidNumberError("Invalid ported number " + msisdn)
+	}
+	return key, nil
+}
@@ -0,0 +1,119 @@
+package portability
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/robesmi/MSISDNApp/model/errs"
+)
+
+func TestStoreReplace(t *testing.T) {
+
+	//Arrange
+	store := NewStore()
+	builder := NewBuilder()
+	builder.Add("38977123456", "Telekom")
+	builder.Add("38971123456", "A1")
+	builder.Add("38977123456", "Lycamobile")
+
+	//Act
+	store.Replace(builder)
+	mno, ported := store.Lookup("38977123456")
+	_, missing := store.Lookup("38977999999")
+
+	//Assert
+	if !ported || mno != "Lycamobile"{
+		t.Errorf("Error in TestStoreReplace:\n expected %s\n got %s, %t", "Lycamobile", mno, ported)
+	}
+	if missing{
+		t.Error("Error in TestStoreReplace:\n expected a number that wasn't added to not be ported")
+	}
+	if store.Len() != 2{
+		t.Errorf("Error in TestStoreReplace:\n expected %d numbers\n got %d", 2, store.Len())
+	}
+}
+
+func TestStoreApply(t *testing.T) {
+
+	//Arrange
+	store := NewStore()
+	builder := NewBuilder()
+	builder.Add("38977123456", "Telekom")
+	builder.Add("38971123456", "A1")
+	store.Replace(builder)
+
+	//Act
+	err := store.Apply([]Change{
+		{MSISDN: "38977123456", MNO: ""},
+		{MSISDN: "38971123456", MNO: "Telekom"},
+		{MSISDN: "38970123456", MNO: "A1"},
+	})
+
+	//Assert
+	if err != nil{
+		t.Fatalf("Error in TestStoreApply:\n expected %s\n got %s", "nil", err)
+	}
+	if _, ported := store.Lookup("38977123456"); ported{
+		t.Error("Error in TestStoreApply:\n expected the removed number to not be ported")
+	}
+	if mno, _ := store.Lookup("38971123456"); mno != "Telekom"{
+		t.Errorf("Error in TestStoreApply:\n expected %s\n got %s", "Telekom", mno)
+	}
+	if mno, _ := store.Lookup("38970123456"); mno != "A1"{
+		t.Errorf("Error in TestStoreApply:\n expected %s\n got %s", "A1", mno)
+	}
+	if store.Len() != 2{
+		t.Errorf("Error in TestStoreApply:\n expected %d numbers\n got %d", 2, store.Len())
+	}
+}
+
+func TestStoreApplyInvalidNumber(t *testing.T) {
+
+	//Arrange
+	store := NewStore()
+
+	//Act
+	err := store.Apply([]Change{
+		{MSISDN: "38970123456", MNO: "A1"},
+		{MSISDN: "lorem ipsum", MNO: "A1"},
+	})
+
+	//Assert
+	if _, ok := err.(*errs.InvalidNumberError); !ok{
+		t.Errorf("Error in TestStoreApplyInvalidNumber:\n expected %s\n got %v", "InvalidNumberError", err)
+	}
+	if store.Len() != 0{
+		t.Errorf("Error in TestStoreApplyInvalidNumber:\n expected no changes\n got %d numbers", store.Len())
+	}
+}
+
+func TestStoreCompaction(t *testing.T) {
+
+	//Arrange
+	store := NewStore()
+	changes := make([]Change, minCompaction)
+	for i := range changes{
+		changes[i] = Change{MSISDN: strconv.Itoa(38970000000 + 2*i), MNO: "A1"}
+	}
+
+	//Act
+	err := store.Apply(changes)
+	removeErr := store.Apply([]Change{{MSISDN: "38970000000", MNO: ""}})
+
+	//Assert
+	if err != nil || removeErr != nil{
+		t.Fatalf("Error in TestStoreCompaction:\n expected %s\n got %v, %v", "nil", err, removeErr)
+	}
+	if len(store.keys) != minCompaction{
+		t.Errorf("Error in TestStoreCompaction:\n expected the changes to be merged\n got %d merged", len(store.keys))
+	}
+	if _, ported := store.Lookup("38970000000"); ported{
+		t.Error("Error in TestStoreCompaction:\n expected the removed number to not be ported")
+	}
+	if mno, _ := store.Lookup("38970000002"); mno != "A1"{
+		t.Errorf("Error in TestStoreCompaction:\n expected %s\n got %s", "A1", mno)
+	}
+	if store.Len() != minCompaction-1{
+		t.Errorf("Error in TestStoreCompaction:\n expected %d numbers\n got %d", minCompaction-1, store.Len())
+	}
+}
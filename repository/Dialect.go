@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+)
+
+// Database drivers the repositories support, as set in DB_DRIVER
+const (
+	DriverMySQL = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite = "sqlite3"
+)
+
+// sqliteDriver is SQLite with a REGEXP operator, which SQLite leaves to the application
+// to define, evaluated by Go's regex engine
+const sqliteDriver = "sqlite3_regexp"
+
+//go:embed schema
+var schemas embed.FS
+
+// sqlitePatterns caches the patterns compiled for SQLite's REGEXP
+var sqlitePatterns sync.Map
+
+func init(){
+
+	sql.Register(sqliteDriver, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc("regexp", sqliteRegexp, true)
+		},
+	})
+	sqlx.BindDriver(sqliteDriver, sqlx.QUESTION)
+}
+
+// sqliteRegexp is called by SQLite for "value REGEXP pattern"
+func sqliteRegexp(pattern string, value string) (bool, error){
+
+	compiled, ok := sqlitePatterns.Load(pattern)
+	if !ok{
+		expr, err := regexp.Compile(pattern)
+		if err != nil{
+			return false, err
+		}
+		compiled, _ = sqlitePatterns.LoadOrStore(pattern, expr)
+	}
+	return compiled.(*regexp.Regexp).MatchString(value), nil
+}
+
+// dialect holds the SQL that differs between the supported databases. Queries are
+// otherwise written with ? placeholders and rebound for the database they run on
+type dialect struct {
+	name string
+	// regexp and notRegexp are the operators matching a value against a pattern
+	regexp string
+	notRegexp string
+	// startsWith matches a value against the prefix in the column it's formatted with
+	startsWith string
+	// upsertPorted saves a ported number over the one with the same MSISDN
+	upsertPorted string
+	// maxVariables is the most bind variables a single statement can have
+	maxVariables int
+}
+
+var mysqlDialect = dialect{
+	name: DriverMySQL,
+	regexp: "RLIKE",
+	notRegexp: "NOT RLIKE",
+	startsWith: "? LIKE CONCAT(%s, '%%')",
+	upsertPorted: "ON DUPLICATE KEY UPDATE mno = VALUES(mno)",
+	maxVariables: 65535,
+}
+
+var postgresDialect = dialect{
+	name: DriverPostgres,
+	regexp: "~",
+	notRegexp: "!~",
+	startsWith: "? LIKE %s || '%%'",
+	upsertPorted: "ON CONFLICT (msisdn) DO UPDATE SET mno = excluded.mno",
+	maxVariables: 65535,
+}
+
+var sqliteDialect = dialect{
+	name: DriverSQLite,
+	regexp: "REGEXP",
+	notRegexp: "NOT REGEXP",
+	startsWith: "? LIKE %s || '%%'",
+	upsertPorted: "ON CONFLICT (msisdn) DO UPDATE SET mno = excluded.mno",
+	// SQLite builds before 3.32 allow 999, which newer ones still can be compiled with
+	maxVariables: 999,
+}
+
+// dialectOf returns the dialect of the database the client is connected to, MySQL
+// unless the driver is one of the others
+func dialectOf(db *sqlx.DB) dialect{
+
+	switch db.DriverName(){
+	case DriverPostgres:
+		return postgresDialect
+	case DriverSQLite, sqliteDriver:
+		return sqliteDialect
+	}
+	return mysqlDialect
+}
+
+// sqliteOptions are added to SQLite sources that don't set them, so that foreign keys
+// are enforced like in the other databases and writers wait for each other instead
+// of failing while the database is locked
+var sqliteOptions = []string{"_foreign_keys=on", "_busy_timeout=5000"}
+
+// Open connects to the database with one of the supported drivers, and SQLite
+// databases get the REGEXP operator
+func Open(driver string, source string) (*sqlx.DB, error){
+
+	if driver == DriverSQLite{
+		driver = sqliteDriver
+		for _, option := range sqliteOptions{
+			name := option[:strings.Index(option, "=")]
+			if strings.Contains(source, name + "="){
+				continue
+			}
+			separator := "?"
+			if strings.Contains(source, "?"){
+				separator = "&"
+			}
+			source += separator + option
+		}
+	}
+	return sqlx.Open(driver, source)
+}
+
+// CreateSchema brings the tables of the repositories up to date. Databases without
+// any of them get the schema of their dialect, while ones created by an earlier release
+// are upgraded by the migrations in schema/migrations that they haven't run yet. Each
+// migration is recorded in schema_migrations, which a new schema starts with all of
+func CreateSchema(db *sqlx.DB) (error){
+
+	name := dialectOf(db).name
+	if _, err := db.Exec("CREATE TABLE IF NOT EXISTS schema_migrations (version int NOT NULL PRIMARY KEY)"); err != nil{
+		return err
+	}
+	migrations, err := schemas.ReadDir("schema/migrations/" + name)
+	if err != nil && !errors.Is(err, fs.ErrNotExist){
+		return err
+	}
+	var applied int
+	if err := db.Get(&applied, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations"); err != nil{
+		return err
+	}
+	if applied == 0 && !legacySchema(db){
+		applied = len(migrations)
+		for version := 1; version <= applied; version++{
+			if _, err := db.Exec(db.Rebind("INSERT INTO schema_migrations (version) VALUES (?)"), version); err != nil{
+				return err
+			}
+		}
+	}
+	for _, migration := range migrations{
+		version, err := strconv.Atoi(strings.SplitN(migration.Name(), "_", 2)[0])
+		if err != nil{
+			return fmt.Errorf("migration %s isn't numbered", migration.Name())
+		}
+		if version <= applied{
+			continue
+		}
+		if err := execFile(db, "schema/migrations/" + name + "/" + migration.Name()); err != nil{
+			return fmt.Errorf("migration %s: %w", migration.Name(), err)
+		}
+		if _, err := db.Exec(db.Rebind("INSERT INTO schema_migrations (version) VALUES (?)"), version); err != nil{
+			return err
+		}
+	}
+	return execFile(db, "schema/" + name + ".sql")
+}
+
+// legacySchema reports whether the countries table was created by a release from
+// before the numbering plan had priorities, which schema_migrations didn't exist in
+func legacySchema(db *sqlx.DB) bool{
+
+	if _, err := db.Exec("SELECT country_number_format FROM countries WHERE 1 = 0"); err != nil{
+		return false
+	}
+	_, err := db.Exec("SELECT priority FROM countries WHERE 1 = 0")
+	return err != nil
+}
+
+// execFile runs the statements of an embedded SQL file one at a time
+func execFile(db *sqlx.DB, path string) (error){
+
+	file, err := schemas.ReadFile(path)
+	if err != nil{
+		return err
+	}
+	for _, statement := range strings.Split(string(file), ";"){
+		if strings.TrimSpace(statement) == ""{
+			continue
+		}
+		if _, err := db.Exec(statement); err != nil{
+			return err
+		}
+	}
+	return nil
+}
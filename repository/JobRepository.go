@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/errs"
+)
+
+type JobRepositoryDb struct {
+	db *sqlx.DB
+}
+
+func NewJobRepository(dbClient *sqlx.DB) JobRepositoryDb{
+	return JobRepositoryDb{dbClient}
+}
+
+//go:generate mockgen -destination=../mocks/repository/mockJobRepository.go -package=repository github.com/robesmi/MSISDNApp/repository JobRepository
+type JobRepository interface {
+	// CreateJob saves a new bulk lookup job
+	CreateJob(*model.LookupJob) (error)
+	// GetJobById returns the job with the given id or a JobNotFoundError
+	GetJobById(string) (*model.LookupJob, error)
+	// GetJobsByOwner returns the jobs of a user, newest first
+	GetJobsByOwner(string) (*[]model.LookupJob, error)
+	// GetUnfinishedJobs returns the queued and running jobs, oldest first
+	GetUnfinishedJobs() (*[]model.LookupJob, error)
+	// UpdateJobProgress takes a job id and saves the amount of processed and failed numbers
+	UpdateJobProgress(string, int, int) (error)
+	// UpdateJobStatus takes a job id, a status and an error message and saves them
+	UpdateJobStatus(string, string, string) (error)
+}
+
+func (repo JobRepositoryDb) CreateJob(job *model.LookupJob) (error){
+
+	sqlAdd := "INSERT INTO lookup_jobs (id, owner, file_name, status, total, processed, failed, error, created_at, updated_at) VALUES (?,?,?,?,?,?,?,?,?,?)"
+	_, err := repo.db.Exec(repo.db.Rebind(sqlAdd), job.ID, job.Owner, job.FileName, job.Status, job.Total, job.Processed, job.Failed, job.Error, job.CreatedAt, job.UpdatedAt)
+	if err != nil{
+		return errs.NewUnexpectedError(err.Error())
+	}
+	return nil
+}
+
+func (repo JobRepositoryDb) GetJobById(id string) (*model.LookupJob, error){
+
+	var job model.LookupJob
+	sqlQuery := "SELECT * FROM lookup_jobs WHERE id = ?"
+	err := repo.db.Get(&job, repo.db.Rebind(sqlQuery), id)
+	if err != nil{
+		if err == sql.ErrNoRows{
+			return nil, errs.NewJobNotFoundError()
+		}else{
+			return nil, errs.NewUnexpectedError(err.Error())
+		}
+	}
+	return &job, nil
+}
+
+func (repo JobRepositoryDb) GetJobsByOwner(owner string) (*[]model.LookupJob, error){
+
+	jobs := []model.LookupJob{}
+	sqlQuery := "SELECT * FROM lookup_jobs WHERE owner = ? ORDER BY created_at DESC"
+	err := repo.db.Select(&jobs, repo.db.Rebind(sqlQuery), owner)
+	if err != nil{
+		return nil, errs.NewUnexpectedError(err.Error())
+	}
+	return &jobs, nil
+}
+
+func (repo JobRepositoryDb) GetUnfinishedJobs() (*[]model.LookupJob, error){
+
+	jobs := []model.LookupJob{}
+	sqlQuery := "SELECT * FROM lookup_jobs WHERE status IN (?, ?) ORDER BY created_at"
+	err := repo.db.Select(&jobs, repo.db.Rebind(sqlQuery), model.JobQueued, model.JobRunning)
+	if err != nil{
+		return nil, errs.NewUnexpectedError(err.Error())
+	}
+	return &jobs, nil
+}
+
+func (repo JobRepositoryDb) UpdateJobProgress(id string, processed int, failed int) (error){
+
+	sqlUpdate := "UPDATE lookup_jobs SET processed = ?, failed = ?, updated_at = ? WHERE id = ?"
+	_, err := repo.db.Exec(repo.db.Rebind(sqlUpdate), processed, failed, time.Now().UTC(), id)
+	if err != nil{
+		return errs.NewUnexpectedError(err.Error())
+	}
+	return nil
+}
+
+func (repo JobRepositoryDb) UpdateJobStatus(id string, status string, message string) (error){
+
+	sqlUpdate := "UPDATE lookup_jobs SET status = ?, error = ?, updated_at = ? WHERE id = ?"
+	_, err := repo.db.Exec(repo.db.Rebind(sqlUpdate), status, message, time.Now().UTC(), id)
+	if err != nil{
+		return errs.NewUnexpectedError(err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/errs"
+)
+
+var jobRepo JobRepository
+
+func TestGetJobById(t *testing.T) {
+
+	//Arrange
+	mock := setup(t)
+	now := time.Now()
+	rows := mock.NewRows([]string{"id","owner","file_name","status","total","processed","failed","error","created_at","updated_at"}).
+	AddRow("job1", "owner1", "numbers.csv", model.JobRunning, 10, 5, 1, "", now, now)
+	mock.ExpectQuery("SELECT").WithArgs("job1").WillReturnRows(rows)
+
+	//Act
+	job, err := jobRepo.GetJobById("job1")
+
+	//Assert
+	if err != nil{
+		t.Fatalf("Error in TestGetJobById:\n expected %s\n got %s", "nil", err)
+	}
+	if job.Owner != "owner1" || job.Processed != 5{
+		t.Errorf("Error in TestGetJobById:\n expected %s with %d processed\n got %s with %d", "owner1", 5, job.Owner, job.Processed)
+	}
+}
+
+func TestGetJobByIdNotFound(t *testing.T) {
+
+	//Arrange
+	mock := setup(t)
+	mock.ExpectQuery("SELECT").WithArgs("job1").WillReturnError(sql.ErrNoRows)
+
+	//Act
+	_, err := jobRepo.GetJobById("job1")
+
+	//Assert
+	if _, ok := err.(*errs.JobNotFoundError); !ok{
+		t.Errorf("Error in TestGetJobByIdNotFound:\n expected %s\n got %v", "JobNotFoundError", err)
+	}
+}
+
+func TestUpdateJobProgress(t *testing.T) {
+
+	//Arrange
+	mock := setup(t)
+	mock.ExpectExec("UPDATE lookup_jobs").WithArgs(20, 3, sqlmock.AnyArg(), "job1").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	//Act
+	err := jobRepo.UpdateJobProgress("job1", 20, 3)
+
+	//Assert
+	if err != nil{
+		t.Errorf("Error in TestUpdateJobProgress:\n expected %s\n got %s", "nil", err)
+	}
+	if expErr := mock.ExpectationsWereMet(); expErr != nil{
+		t.Errorf("Error in TestUpdateJobProgress:\n %s", expErr)
+	}
+}
@@ -2,66 +2,143 @@ package repository
 
 import (
 	"database/sql"
+	"fmt"
+	"strings"
+	"time"
 
-	_ "github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 	"github.com/robesmi/MSISDNApp/model"
 	"github.com/robesmi/MSISDNApp/model/dto"
 	"github.com/robesmi/MSISDNApp/model/errs"
+	"github.com/robesmi/MSISDNApp/numplan"
 )
 
 type MSISDNRepositoryDb struct {
 	db *sqlx.DB
+	dialect dialect
 }
 
 func NewMSISDNRepository(dbClient *sqlx.DB) MSISDNRepositoryDb{
-	return MSISDNRepositoryDb{dbClient}
+	return MSISDNRepositoryDb{dbClient, dialectOf(dbClient)}
 }
 
 type MSISDNRepository interface{
-	// LookupCountryCode takes a string full number and returns the respective country identifier it belongs to,
-	// the country code and the country's prefix length, or an error
-	LookupCountryCode(string) (*dto.CountryLookupResponse, error)
-	// LookupMobileOperator takes a country identifier and a significant number and returns an MNO, length of carrier prefix, or an error
-	LookupMobileOperator(string, string) (*dto.MobileOperatorLookupResponse, error)
-	AddNewCountry(string, string, string, int) (error)
-	AddNewMobileOperator(string, string, string, int) (error)
+	// LookupCountryCode takes a string full number and a time and returns the respective country identifier it
+	// belonged to at that time, the country code and the country's prefix length, or an error
+	LookupCountryCode(string, time.Time) (*dto.CountryLookupResponse, error)
+	// LookupMobileOperator takes a country identifier, a significant number and a time and returns the MNO the number
+	// belonged to at that time, length of carrier prefix and type of the number range, or an error
+	LookupMobileOperator(string, string, time.Time) (*dto.MobileOperatorLookupResponse, error)
+	// LookupCallingCode takes a string full number and a time and returns the country in effect at that time whose
+	// calling code it starts with, whether or not the rest of the number fits the country's format, or a CountryNotFoundError
+	LookupCallingCode(string, time.Time) (*model.Country, error)
+	// LookupPartial takes the start of a full number and a time and returns the countries and ranges
+	// in effect at that time the number can still belong to, with how many more digits it can take
+	LookupPartial(string, time.Time) (*model.PartialMatch, error)
+	// GetCountryByIdentifier returns the country in effect with the ISO 3166-1-alpha-2 identifier, or a CountryNotFoundError
+	GetCountryByIdentifier(string) (*model.Country, error)
+	AddNewCountry(*model.Country) (error)
+	AddNewMobileOperator(*model.MobileOperator) (error)
+	// GetAllCountries and GetAllMobileOperators return every rule, including the ones
+	// no longer or not yet in effect
 	GetAllCountries() (*[]model.Country, error)
 	GetAllMobileOperators() (*[]model.MobileOperator, error)
-	RemoveCountry(string) (error)
-	RemoveOperator(string) (error)
+	// RemoveCountry and RemoveOperator take a pattern and a time and end the rules with
+	// the pattern in effect at that time, keeping them for lookups of earlier times. Rules
+	// with the pattern scheduled to take effect later are dropped
+	RemoveCountry(string, time.Time) (error)
+	RemoveOperator(string, time.Time) (error)
+	// GetNetworkOperatorByName returns the network operator with the brand name, or an OperatorNotFoundError
+	GetNetworkOperatorByName(string) (*model.NetworkOperator, error)
+	GetAllNetworkOperators() (*[]model.NetworkOperator, error)
+	AddNewNetworkOperator(*model.NetworkOperator) (error)
+	// RemoveNetworkOperator removes the network operator with the ID, which fails while
+	// ranges or MVNOs still reference it
+	RemoveNetworkOperator(int) (error)
+	// GetCountryDetails returns the details of the country with the ISO 3166-1-alpha-2 identifier, or a CountryNotFoundError
+	GetCountryDetails(string) (*model.CountryDetails, error)
+	GetAllCountryDetails() (*[]model.CountryDetails, error)
+	// SaveCountryDetails adds the details of a country or replaces the ones it has
+	SaveCountryDetails(*model.CountryDetails) (error)
+	// UpdatePlan removes, changes and adds the rules of an imported numbering plan in a single transaction
+	UpdatePlan(*model.PlanUpdate) (error)
 }
 
+// operatorColumns selects a range along with the operator it's assigned to
+const operatorColumns = "m.id, m.country_identifier, m.prefix_format, m.excluded_format, m.operator_id, m.prefix_length, m.number_type, m.priority, m.effective_from, m.effective_to, " +
+	"o.brand_name AS mno, o.network_codes, COALESCE(h.brand_name, '') AS host_network " +
+	"FROM mobile_operators m JOIN network_operators o ON o.id = m.operator_id LEFT JOIN network_operators h ON h.id = o.host_operator_id"
+
+// countryFields and operatorFields are the columns written when saving a rule, in the
+// order of countryValues and operatorValues
+var countryFields = []string{"country_number_format", "excluded_format", "country_code", "country_identifier", "country_code_length", "trunk_prefix",
+	"international_prefix", "nsn_min_length", "nsn_max_length", "number_grouping", "priority", "effective_from", "effective_to"}
+var operatorFields = []string{"country_identifier", "prefix_format", "excluded_format", "operator_id", "prefix_length", "number_type", "priority", "effective_from", "effective_to"}
+
+var insertCountry = "INSERT INTO countries (" + strings.Join(countryFields, ", ") + ") VALUES (" + placeholders(len(countryFields)) + ")"
+var updateCountry = "UPDATE countries SET " + strings.Join(countryFields, " = ?, ") + " = ? WHERE id = ?"
+var insertOperator = "INSERT INTO mobile_operators (" + strings.Join(operatorFields, ", ") + ") VALUES (" + placeholders(len(operatorFields)) + ")"
+var updateOperator = "UPDATE mobile_operators SET " + strings.Join(operatorFields, " = ?, ") + " = ? WHERE id = ?"
+
+// effectiveCountry and effectiveOperator restrict a query to the rules in effect at a time,
+// which is passed twice. effectiveCountry works for any table without an alias
+const effectiveCountry = "(effective_from IS NULL OR effective_from <= ?) AND (effective_to IS NULL OR effective_to > ?)"
+const effectiveOperator = "(m.effective_from IS NULL OR m.effective_from <= ?) AND (m.effective_to IS NULL OR m.effective_to > ?)"
+
+// networkOperatorColumns selects a network operator along with its host's brand name
+const networkOperatorColumns = "o.id, o.brand_name, o.legal_name, o.status, o.network_codes, COALESCE(o.host_operator_id, 0) AS host_operator_id, COALESCE(h.brand_name, '') AS host_network " +
+	"FROM network_operators o LEFT JOIN network_operators h ON h.id = o.host_operator_id"
+
 
 //go:generate mockgen -destination=../mocks/repository/mockMSISDNRepository.go -package=repository github.com/robesmi/MSISDNApp/repository MSISDNRepository
-func (repo MSISDNRepositoryDb) LookupCountryCode(fullnumber string) (*dto.CountryLookupResponse,  error){
-	var response dto.CountryLookupResponse
-	sqlQuery := "SELECT country_code, country_identifier, country_code_length FROM countries WHERE ? RLIKE country_number_format"
-	err := repo.db.Get(&response, sqlQuery, fullnumber)
+func (repo MSISDNRepositoryDb) LookupCountryCode(fullnumber string, at time.Time) (*dto.CountryLookupResponse,  error){
+	var matches []rankedCountry
+	sqlQuery := "SELECT country_code, country_identifier, country_code_length, trunk_prefix, number_grouping, priority, country_number_format FROM countries WHERE ? " + repo.dialect.regexp + " country_number_format AND (excluded_format = '' OR ? " + repo.dialect.notRegexp + " excluded_format) AND " + effectiveCountry
+	err := repo.db.Select(&matches, repo.db.Rebind(sqlQuery), fullnumber, fullnumber, at, at)
 	if err != nil{
-		if err == sql.ErrNoRows{
-			return nil, errs.NewNumberNotFoundError()
-		}else{
-			return nil, errs.NewUnexpectedError(err.Error())
+		return nil, errs.NewUnexpectedError(err.Error())
+	}
+	if len(matches) == 0{
+		return nil, errs.NewNumberNotFoundError()
+	}
+	// The database can't tell how specific a pattern is, so the matches are ranked here
+	// the same way the index ranks them
+	best := matches[0]
+	for _, match := range matches[1:]{
+		if numplan.RanksBefore(match.Priority, match.CountryNumberFormat, best.Priority, best.CountryNumberFormat){
+			best = match
 		}
 	}
-	return &response,nil
+	return &best.CountryLookupResponse,nil
 }
 
-func (repo MSISDNRepositoryDb) LookupMobileOperator(ci string, significantNumber string) (*dto.MobileOperatorLookupResponse, error){
-	var response dto.MobileOperatorLookupResponse
-	sqlQuery := "SELECT mno, prefix_length FROM mobile_operators WHERE ? = country_identifier AND ? RLIKE prefix_format"
-	err := repo.db.Get(&response, sqlQuery, ci, significantNumber)
+func (repo MSISDNRepositoryDb) LookupMobileOperator(ci string, significantNumber string, at time.Time) (*dto.MobileOperatorLookupResponse, error){
+	var matches []model.MobileOperator
+	sqlQuery := "SELECT " + operatorColumns + " WHERE ? = m.country_identifier AND ? " + repo.dialect.regexp + " m.prefix_format AND (m.excluded_format = '' OR ? " + repo.dialect.notRegexp + " m.excluded_format) AND " + effectiveOperator
+	err := repo.db.Select(&matches, repo.db.Rebind(sqlQuery), ci, significantNumber, significantNumber, at, at)
 	if err != nil{
-		if err == sql.ErrNoRows{
-			return nil, errs.NewNoCarriersFoundError()
-		}else{
-			return nil, errs.NewUnexpectedError(err.Error())
+		return nil, errs.NewUnexpectedError(err.Error())
+	}
+	if len(matches) == 0{
+		return nil, errs.NewNoCarriersFoundError()
+	}
+	best := matches[0]
+	for _, match := range matches[1:]{
+		if numplan.RanksBefore(match.Priority, match.PrefixFormat, best.Priority, best.PrefixFormat){
+			best = match
 		}
 	}
+	response := best.ToDto()
 	return &response,nil
 }
 
+// rankedCountry is a country matching a number along with what it's ranked by
+type rankedCountry struct {
+	dto.CountryLookupResponse
+	Priority int					`db:"priority"`
+	CountryNumberFormat string		`db:"country_number_format"`
+}
+
 func (repo MSISDNRepositoryDb) GetAllCountries() (*[]model.Country,error){
 
 	var response []model.Country
@@ -76,7 +153,7 @@ func (repo MSISDNRepositoryDb) GetAllCountries() (*[]model.Country,error){
 func (repo MSISDNRepositoryDb) GetAllMobileOperators() (*[]model.MobileOperator, error){
 
 	var response []model.MobileOperator
-	sqlQuery := "SELECT * FROM mobile_operators"
+	sqlQuery := "SELECT " + operatorColumns
 	err := repo.db.Select(&response,sqlQuery)
 	if err != nil {
 		return nil, err
@@ -84,42 +161,254 @@ func (repo MSISDNRepositoryDb) GetAllMobileOperators() (*[]model.MobileOperator,
 	return &response, nil
 }
 
-func (repo MSISDNRepositoryDb) AddNewCountry(numFormat string, cc string, ci string, cLen int) (error){
+// LookupPartial compiles the whole numbering plan for every call, which is only meant as
+// a fallback for repositories not served through an MSISDNRepositoryIndex
+func (repo MSISDNRepositoryDb) LookupPartial(digits string, at time.Time) (*model.PartialMatch, error){
+
+	countries, err := repo.GetAllCountries()
+	if err != nil{
+		return nil, errs.NewUnexpectedError(err.Error())
+	}
+	operators, err := repo.GetAllMobileOperators()
+	if err != nil{
+		return nil, errs.NewUnexpectedError(err.Error())
+	}
+	match := numplan.NewPlan(*countries, *operators).Partial(digits, at)
+	return &match, nil
+}
+
+func (repo MSISDNRepositoryDb) LookupCallingCode(fullnumber string, at time.Time) (*model.Country, error){
+
+	var response model.Country
+	sqlQuery := "SELECT * FROM countries WHERE " + fmt.Sprintf(repo.dialect.startsWith, "country_code") + " AND " + effectiveCountry + " ORDER BY LENGTH(country_code) DESC LIMIT 1"
+	err := repo.db.Get(&response, repo.db.Rebind(sqlQuery), fullnumber, at, at)
+	if err != nil{
+		if err == sql.ErrNoRows{
+			return nil, errs.NewCountryNotFoundError()
+		}else{
+			return nil, errs.NewUnexpectedError(err.Error())
+		}
+	}
+	return &response, nil
+}
+
+func (repo MSISDNRepositoryDb) GetCountryByIdentifier(ci string) (*model.Country, error){
 
-	sqlAdd := "INSERT INTO countries VALUES (?,?,?,?)"
-	_, err := repo.db.Exec(sqlAdd, numFormat,cc,ci,cLen)
+	var response model.Country
+	now := time.Now().UTC()
+	sqlQuery := "SELECT * FROM countries WHERE country_identifier = ? AND " + effectiveCountry + " LIMIT 1"
+	err := repo.db.Get(&response, repo.db.Rebind(sqlQuery), ci, now, now)
+	if err != nil{
+		if err == sql.ErrNoRows{
+			return nil, errs.NewCountryNotFoundError()
+		}else{
+			return nil, errs.NewUnexpectedError(err.Error())
+		}
+	}
+	return &response, nil
+}
+
+func (repo MSISDNRepositoryDb) AddNewCountry(country *model.Country) (error){
+
+	_, err := repo.db.Exec(repo.db.Rebind(insertCountry), countryValues(country)...)
 	if err != nil{
 		return err
 	}
 	return nil
 }
 
-func (repo MSISDNRepositoryDb) AddNewMobileOperator(ci string, prefix string, mno string, prefixLen int) (error){
+func (repo MSISDNRepositoryDb) AddNewMobileOperator(operator *model.MobileOperator) (error){
 
-	sqlAdd := "INSERT INTO mobile_operators VALUES (?,?,?,?)"
-	_, err := repo.db.Exec(sqlAdd, ci, prefix, mno, prefixLen)
+	_, err := repo.db.Exec(repo.db.Rebind(insertOperator), operatorValues(operator)...)
 	if err != nil{
 		return err
 	}
 	return nil
 }
 
-func (repo MSISDNRepositoryDb) RemoveCountry(prefix string) (error){
+func (repo MSISDNRepositoryDb) RemoveCountry(prefix string, at time.Time) (error){
+	return repo.endRules("countries", "country_number_format", prefix, at)
+}
+
+func (repo MSISDNRepositoryDb) RemoveOperator(prefix string, at time.Time) (error){
+	return repo.endRules("mobile_operators", "prefix_format", prefix, at)
+}
+
+// endRules ends the rules of the table with the pattern that are in effect at the time,
+// and drops the ones that were scheduled to take effect at or after it
+func (repo MSISDNRepositoryDb) endRules(table string, column string, prefix string, at time.Time) (error){
 
-	sqlRemove := "DELETE FROM countries WHERE country_number_format = ?"
-	_, err := repo.db.Exec(sqlRemove, prefix)
+	tx, err := repo.db.Beginx()
+	if err != nil{
+		return err
+	}
+	defer tx.Rollback()
+
+	sqlDrop := "DELETE FROM " + table + " WHERE " + column + " = ? AND effective_from >= ?"
+	if _, err := tx.Exec(tx.Rebind(sqlDrop), prefix, at); err != nil{
+		return err
+	}
+	sqlEnd := "UPDATE " + table + " SET effective_to = ? WHERE " + column + " = ? AND " + effectiveCountry
+	if _, err := tx.Exec(tx.Rebind(sqlEnd), at, prefix, at, at); err != nil{
+		return err
+	}
+	return tx.Commit()
+}
+
+func (repo MSISDNRepositoryDb) GetNetworkOperatorByName(name string) (*model.NetworkOperator, error){
+
+	var response model.NetworkOperator
+	sqlQuery := "SELECT " + networkOperatorColumns + " WHERE o.brand_name = ? LIMIT 1"
+	err := repo.db.Get(&response, repo.db.Rebind(sqlQuery), name)
+	if err != nil{
+		if err == sql.ErrNoRows{
+			return nil, errs.NewOperatorNotFoundError()
+		}else{
+			return nil, errs.NewUnexpectedError(err.Error())
+		}
+	}
+	return &response, nil
+}
+
+func (repo MSISDNRepositoryDb) GetAllNetworkOperators() (*[]model.NetworkOperator, error){
+
+	var response []model.NetworkOperator
+	sqlQuery := "SELECT " + networkOperatorColumns + " ORDER BY o.id"
+	err := repo.db.Select(&response, sqlQuery)
+	if err != nil{
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (repo MSISDNRepositoryDb) AddNewNetworkOperator(operator *model.NetworkOperator) (error){
+
+	sqlAdd := "INSERT INTO network_operators (brand_name, legal_name, status, network_codes, host_operator_id) VALUES (?,?,?,?,NULLIF(?, 0))"
+	_, err := repo.db.Exec(repo.db.Rebind(sqlAdd), operator.BrandName, operator.LegalName, operator.Status, operator.NetworkCodes, operator.HostOperatorID)
 	if err != nil{
 		return err
 	}
 	return nil
 }
 
-func (repo MSISDNRepositoryDb) RemoveOperator(prefix string) (error){
+func (repo MSISDNRepositoryDb) RemoveNetworkOperator(id int) (error){
 
-	sqlRemove := "DELETE FROM mobile_operators WHERE prefix_format = ?"
-	_, err := repo.db.Exec(sqlRemove, prefix)
+	sqlRemove := "DELETE FROM network_operators WHERE id = ?"
+	_, err := repo.db.Exec(repo.db.Rebind(sqlRemove), id)
 	if err != nil{
 		return err
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// countryDetailsColumns are the columns of a country's details, in the order of SaveCountryDetails
+const countryDetailsColumns = "country_identifier, name, native_name, iso_alpha3, iso_numeric, time_zones, currency, region, continent"
+
+func (repo MSISDNRepositoryDb) GetCountryDetails(ci string) (*model.CountryDetails, error){
+
+	var response model.CountryDetails
+	sqlQuery := "SELECT " + countryDetailsColumns + " FROM country_details WHERE country_identifier = ?"
+	err := repo.db.Get(&response, repo.db.Rebind(sqlQuery), ci)
+	if err != nil{
+		if err == sql.ErrNoRows{
+			return nil, errs.NewCountryNotFoundError()
+		}
+		return nil, errs.NewUnexpectedError(err.Error())
+	}
+	return &response, nil
+}
+
+func (repo MSISDNRepositoryDb) GetAllCountryDetails() (*[]model.CountryDetails, error){
+
+	var response []model.CountryDetails
+	sqlQuery := "SELECT " + countryDetailsColumns + " FROM country_details ORDER BY country_identifier"
+	err := repo.db.Select(&response, sqlQuery)
+	if err != nil{
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (repo MSISDNRepositoryDb) SaveCountryDetails(details *model.CountryDetails) (error){
+
+	tx, err := repo.db.Beginx()
+	if err != nil{
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(tx.Rebind("DELETE FROM country_details WHERE country_identifier = ?"), details.CountryIdentifier); err != nil{
+		return err
+	}
+	sqlAdd := "INSERT INTO country_details (" + countryDetailsColumns + ") VALUES (?,?,?,?,?,?,?,?,?)"
+	_, err = tx.Exec(tx.Rebind(sqlAdd), details.CountryIdentifier, details.Name, details.NativeName, details.Alpha3, details.Numeric,
+		details.TimeZones, details.Currency, details.Region, details.Continent)
+	if err != nil{
+		return err
+	}
+	return tx.Commit()
+}
+
+func (repo MSISDNRepositoryDb) UpdatePlan(update *model.PlanUpdate) (error){
+
+	tx, err := repo.db.Beginx()
+	if err != nil{
+		return errs.NewUnexpectedError(err.Error())
+	}
+	defer tx.Rollback()
+
+	// Ranges go first, so that no range is left pointing to a removed country
+	// and added ranges find their countries
+	for _, id := range update.RemovedOperators{
+		if _, err := tx.Exec(tx.Rebind("DELETE FROM mobile_operators WHERE id = ?"), id); err != nil{
+			return errs.NewUnexpectedError(err.Error())
+		}
+	}
+	for _, id := range update.RemovedCountries{
+		if _, err := tx.Exec(tx.Rebind("DELETE FROM countries WHERE id = ?"), id); err != nil{
+			return errs.NewUnexpectedError(err.Error())
+		}
+	}
+	for i := range update.ChangedCountries{
+		country := &update.ChangedCountries[i]
+		if _, err := tx.Exec(tx.Rebind(updateCountry), append(countryValues(country), country.ID)...); err != nil{
+			return errs.NewUnexpectedError(err.Error())
+		}
+	}
+	for i := range update.AddedCountries{
+		if _, err := tx.Exec(tx.Rebind(insertCountry), countryValues(&update.AddedCountries[i])...); err != nil{
+			return errs.NewUnexpectedError(err.Error())
+		}
+	}
+	for i := range update.ChangedOperators{
+		operator := &update.ChangedOperators[i]
+		if _, err := tx.Exec(tx.Rebind(updateOperator), append(operatorValues(operator), operator.ID)...); err != nil{
+			return errs.NewUnexpectedError(err.Error())
+		}
+	}
+	for i := range update.AddedOperators{
+		if _, err := tx.Exec(tx.Rebind(insertOperator), operatorValues(&update.AddedOperators[i])...); err != nil{
+			return errs.NewUnexpectedError(err.Error())
+		}
+	}
+
+	if err := tx.Commit(); err != nil{
+		return errs.NewUnexpectedError(err.Error())
+	}
+	return nil
+}
+
+func countryValues(country *model.Country) []interface{}{
+	return []interface{}{country.CountryNumberFormat, country.ExcludedFormat, country.CountryCode, country.CountryIdentifier, country.CountryCodeLength, country.TrunkPrefix,
+		country.InternationalPrefix, country.NSNMinLength, country.NSNMaxLength, country.NumberGrouping, country.Priority, country.EffectiveFrom, country.EffectiveTo}
+}
+
+func operatorValues(operator *model.MobileOperator) []interface{}{
+	return []interface{}{operator.CountryIdentifier, operator.PrefixFormat, operator.ExcludedFormat, operator.OperatorID, operator.PrefixLength, operator.NumberType,
+		operator.Priority, operator.EffectiveFrom, operator.EffectiveTo}
+}
+
+// placeholders returns a comma separated list of n query placeholders
+func placeholders(n int) string{
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
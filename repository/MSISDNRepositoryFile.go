@@ -0,0 +1,314 @@
+package repository
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/dto"
+	"github.com/robesmi/MSISDNApp/model/errs"
+	"github.com/robesmi/MSISDNApp/numplan"
+)
+
+// SnapshotVersion is the version of the snapshot file layout, raised whenever
+// the layout changes in a way older readers can't handle
+const SnapshotVersion = 1
+
+// PlanSnapshot is the whole numbering plan along with the network operators, as
+// saved in a snapshot file. Files ending in .json hold it as JSON, any other file
+// in the compact gob encoding
+type PlanSnapshot struct {
+	SchemaVersion int
+	CreatedAt time.Time
+	Countries []model.Country
+	Operators []model.MobileOperator
+	NetworkOperators []model.NetworkOperator
+	// CountryDetails is empty in snapshots saved before countries had details
+	CountryDetails []model.CountryDetails
+}
+
+// MSISDNRepositoryFile serves the numbering plan from a snapshot file instead of the
+// database, for lookup nodes that run without MySQL. It's meant to back an
+// MSISDNRepositoryIndex and is read-only, so changes return a ReadOnlyPlanError
+type MSISDNRepositoryFile struct {
+	path string
+	snapshot atomic.Pointer[PlanSnapshot]
+	// modified and size identify the version of the file that was loaded
+	modified time.Time
+	size int64
+	mu sync.Mutex
+}
+
+// NewMSISDNRepositoryFile loads the snapshot file at the path
+func NewMSISDNRepositoryFile(path string) (*MSISDNRepositoryFile, error){
+
+	repo := &MSISDNRepositoryFile{path: path}
+	if _, err := repo.Refresh(); err != nil{
+		return nil, err
+	}
+	return repo, nil
+}
+
+// Refresh loads the snapshot file again if it changed since it was last loaded and
+// reports whether it did. A file that can't be read leaves the current snapshot in place
+func (repo *MSISDNRepositoryFile) Refresh() (bool, error){
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	info, err := os.Stat(repo.path)
+	if err != nil{
+		return false, errs.NewUnexpectedError(err.Error())
+	}
+	if repo.snapshot.Load() != nil && info.ModTime().Equal(repo.modified) && info.Size() == repo.size{
+		return false, nil
+	}
+	snapshot, err := ReadSnapshot(repo.path)
+	if err != nil{
+		return false, err
+	}
+	repo.snapshot.Store(snapshot)
+	repo.modified = info.ModTime()
+	repo.size = info.Size()
+	return true, nil
+}
+
+// Watch checks the snapshot file for changes every interval and calls changed after
+// loading a new version of it, or with the error when the file can't be loaded.
+// Calling the returned function stops watching
+func (repo *MSISDNRepositoryFile) Watch(interval time.Duration, changed func(error)) func(){
+
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func(){
+		for{
+			select{
+			case <-done:
+				return
+			case <-ticker.C:
+				if reloaded, err := repo.Refresh(); reloaded || err != nil{
+					changed(err)
+				}
+			}
+		}
+	}()
+	return func(){
+		ticker.Stop()
+		close(done)
+	}
+}
+
+// ReadSnapshot reads a snapshot file, refusing files written with a newer layout
+func ReadSnapshot(path string) (*PlanSnapshot, error){
+
+	file, err := os.Open(path)
+	if err != nil{
+		return nil, errs.NewUnexpectedError(err.Error())
+	}
+	defer file.Close()
+
+	var snapshot PlanSnapshot
+	if isJSONSnapshot(path){
+		err = json.NewDecoder(file).Decode(&snapshot)
+	}else{
+		err = gob.NewDecoder(file).Decode(&snapshot)
+	}
+	if err != nil{
+		return nil, errs.NewInvalidFileError(fmt.Sprintf("Error reading snapshot %s: %s", path, err))
+	}
+	if snapshot.SchemaVersion > SnapshotVersion{
+		return nil, errs.NewInvalidFileError(fmt.Sprintf("Snapshot %s has schema version %d, the latest supported one is %d", path, snapshot.SchemaVersion, SnapshotVersion))
+	}
+	return &snapshot, nil
+}
+
+// WriteSnapshot saves the numbering plan of the repository in a snapshot file. The file
+// is written next to the path and then renamed over it, so that nodes watching the
+// path never load a partly written snapshot
+func WriteSnapshot(path string, repo MSISDNRepository) (error){
+
+	countries, err := repo.GetAllCountries()
+	if err != nil{
+		return err
+	}
+	operators, err := repo.GetAllMobileOperators()
+	if err != nil{
+		return err
+	}
+	networkOperators, err := repo.GetAllNetworkOperators()
+	if err != nil{
+		return err
+	}
+	countryDetails, err := repo.GetAllCountryDetails()
+	if err != nil{
+		return err
+	}
+	snapshot := PlanSnapshot{
+		SchemaVersion: SnapshotVersion,
+		CreatedAt: time.Now().UTC(),
+		Countries: *countries,
+		Operators: *operators,
+		NetworkOperators: *networkOperators,
+		CountryDetails: *countryDetails,
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path) + ".*.tmp")
+	if err != nil{
+		return err
+	}
+	defer os.Remove(file.Name())
+	if err := encodeSnapshot(file, path, &snapshot); err != nil{
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil{
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+func encodeSnapshot(file io.Writer, path string, snapshot *PlanSnapshot) (error){
+
+	if isJSONSnapshot(path){
+		return json.NewEncoder(file).Encode(snapshot)
+	}
+	return gob.NewEncoder(file).Encode(snapshot)
+}
+
+func isJSONSnapshot(path string) bool{
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
+
+// LookupCountryCode and LookupMobileOperator are only reached for the patterns the index
+// couldn't compile, which can't be evaluated without the database either
+func (repo *MSISDNRepositoryFile) LookupCountryCode(fullnumber string, at time.Time) (*dto.CountryLookupResponse, error){
+	return nil, errs.NewNumberNotFoundError()
+}
+
+func (repo *MSISDNRepositoryFile) LookupMobileOperator(ci string, significantNumber string, at time.Time) (*dto.MobileOperatorLookupResponse, error){
+	return nil, errs.NewNoCarriersFoundError()
+}
+
+func (repo *MSISDNRepositoryFile) LookupCallingCode(fullnumber string, at time.Time) (*model.Country, error){
+
+	var response *model.Country
+	countries := repo.snapshot.Load().Countries
+	for i := range countries{
+		country := &countries[i]
+		if !country.EffectiveAt(at) || !strings.HasPrefix(fullnumber, country.CountryCode){
+			continue
+		}
+		if response == nil || len(country.CountryCode) > len(response.CountryCode){
+			response = country
+		}
+	}
+	if response == nil{
+		return nil, errs.NewCountryNotFoundError()
+	}
+	found := *response
+	return &found, nil
+}
+
+func (repo *MSISDNRepositoryFile) GetCountryByIdentifier(ci string) (*model.Country, error){
+
+	now := time.Now().UTC()
+	for _, country := range repo.snapshot.Load().Countries{
+		if country.CountryIdentifier == ci && country.EffectiveAt(now){
+			return &country, nil
+		}
+	}
+	return nil, errs.NewCountryNotFoundError()
+}
+
+func (repo *MSISDNRepositoryFile) GetAllCountries() (*[]model.Country, error){
+
+	countries := append([]model.Country(nil), repo.snapshot.Load().Countries...)
+	return &countries, nil
+}
+
+// LookupPartial compiles the plan of the snapshot for every call, the MSISDNRepositoryIndex
+// backed by the file answers it from its own plan instead
+func (repo *MSISDNRepositoryFile) LookupPartial(digits string, at time.Time) (*model.PartialMatch, error){
+
+	snapshot := repo.snapshot.Load()
+	match := numplan.NewPlan(snapshot.Countries, snapshot.Operators).Partial(digits, at)
+	return &match, nil
+}
+
+func (repo *MSISDNRepositoryFile) GetAllMobileOperators() (*[]model.MobileOperator, error){
+
+	operators := append([]model.MobileOperator(nil), repo.snapshot.Load().Operators...)
+	return &operators, nil
+}
+
+func (repo *MSISDNRepositoryFile) GetNetworkOperatorByName(name string) (*model.NetworkOperator, error){
+
+	for _, operator := range repo.snapshot.Load().NetworkOperators{
+		if operator.BrandName == name{
+			return &operator, nil
+		}
+	}
+	return nil, errs.NewOperatorNotFoundError()
+}
+
+func (repo *MSISDNRepositoryFile) GetAllNetworkOperators() (*[]model.NetworkOperator, error){
+
+	networkOperators := append([]model.NetworkOperator(nil), repo.snapshot.Load().NetworkOperators...)
+	return &networkOperators, nil
+}
+
+func (repo *MSISDNRepositoryFile) GetCountryDetails(ci string) (*model.CountryDetails, error){
+
+	for _, details := range repo.snapshot.Load().CountryDetails{
+		if details.CountryIdentifier == ci{
+			return &details, nil
+		}
+	}
+	return nil, errs.NewCountryNotFoundError()
+}
+
+func (repo *MSISDNRepositoryFile) GetAllCountryDetails() (*[]model.CountryDetails, error){
+
+	details := append([]model.CountryDetails(nil), repo.snapshot.Load().CountryDetails...)
+	return &details, nil
+}
+
+func (repo *MSISDNRepositoryFile) AddNewCountry(country *model.Country) (error){
+	return errs.NewReadOnlyPlanError()
+}
+
+func (repo *MSISDNRepositoryFile) AddNewMobileOperator(operator *model.MobileOperator) (error){
+	return errs.NewReadOnlyPlanError()
+}
+
+func (repo *MSISDNRepositoryFile) RemoveCountry(prefix string, at time.Time) (error){
+	return errs.NewReadOnlyPlanError()
+}
+
+func (repo *MSISDNRepositoryFile) RemoveOperator(prefix string, at time.Time) (error){
+	return errs.NewReadOnlyPlanError()
+}
+
+func (repo *MSISDNRepositoryFile) AddNewNetworkOperator(operator *model.NetworkOperator) (error){
+	return errs.NewReadOnlyPlanError()
+}
+
+func (repo *MSISDNRepositoryFile) RemoveNetworkOperator(id int) (error){
+	return errs.NewReadOnlyPlanError()
+}
+
+func (repo *MSISDNRepositoryFile) SaveCountryDetails(details *model.CountryDetails) (error){
+	return errs.NewReadOnlyPlanError()
+}
+
+func (repo *MSISDNRepositoryFile) UpdatePlan(update *model.PlanUpdate) (error){
+	return errs.NewReadOnlyPlanError()
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mocks "github.com/robesmi/MSISDNApp/mocks/repository"
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/errs"
+)
+
+// writeTestSnapshot saves a snapshot of the plan at the path through a mocked repository
+func writeTestSnapshot(t *testing.T, path string, countries []model.Country, operators []model.MobileOperator, networkOperators []model.NetworkOperator){
+
+	ctrl := gomock.NewController(t)
+	source := mocks.NewMockMSISDNRepository(ctrl)
+	source.EXPECT().GetAllCountries().Return(&countries, nil)
+	source.EXPECT().GetAllMobileOperators().Return(&operators, nil)
+	source.EXPECT().GetAllNetworkOperators().Return(&networkOperators, nil)
+	source.EXPECT().GetAllCountryDetails().Return(&[]model.CountryDetails{
+		{CountryIdentifier: "mk", Name: "North Macedonia", Alpha3: "MKD", Numeric: "807"},
+	}, nil)
+
+	if err := WriteSnapshot(path, source); err != nil{
+		t.Fatalf("Error writing snapshot: %s", err)
+	}
+}
+
+func TestFileRepositoryLookups(t *testing.T) {
+
+	countries := []model.Country{
+		{ID: 1, CountryNumberFormat: "^389[0-9]{8}$", CountryCode: "389", CountryIdentifier: "mk", CountryCodeLength: 3, TrunkPrefix: "0"},
+	}
+	operators := []model.MobileOperator{
+		{ID: 1, CountryIdentifier: "mk", PrefixFormat: "^7[0-9]{7}$", OperatorID: 1, MNO: "A1", NetworkCodes: "294-01", PrefixLength: 2, NumberType: model.NumberTypeMobile, EffectiveFrom: &now},
+	}
+	networkOperators := []model.NetworkOperator{
+		{ID: 1, BrandName: "A1", Status: model.OperatorStatusActive, NetworkCodes: "294-01"},
+	}
+
+	for _, name := range []string{"plan.json", "plan.snapshot"}{
+		fn := func(t *testing.T){
+
+			//Arrange
+			path := filepath.Join(t.TempDir(), name)
+			writeTestSnapshot(t, path, countries, operators, networkOperators)
+			repo, err := NewMSISDNRepositoryFile(path)
+			if err != nil{
+				t.Fatalf("Error in TestFileRepositoryLookups:\n expected %s\n got %s", "nil", err)
+			}
+			index, err := NewMSISDNRepositoryIndex(repo)
+			if err != nil{
+				t.Fatalf("Error in TestFileRepositoryLookups:\n expected %s\n got %s", "nil", err)
+			}
+
+			//Act
+			country, countryErr := index.LookupCountryCode("38977123456", now)
+			operator, operatorErr := index.LookupMobileOperator("mk", "77123456", now)
+			_, earlierErr := index.LookupMobileOperator("mk", "77123456", now.Add(-time.Hour))
+			networkOperator, networkErr := index.GetNetworkOperatorByName("A1")
+			details, detailsErr := index.GetCountryDetails("mk")
+
+			//Assert
+			if countryErr != nil || country.CountryIdentifier != "mk"{
+				t.Errorf("Error in TestFileRepositoryLookups:\n expected %s\n got %v, %v", "mk", country, countryErr)
+			}
+			if operatorErr != nil || operator.MNO != "A1" || operator.NetworkCodes != "294-01"{
+				t.Errorf("Error in TestFileRepositoryLookups:\n expected %s\n got %v, %v", "A1 294-01", operator, operatorErr)
+			}
+			if _, ok := earlierErr.(*errs.NoCarriersFoundError); !ok{
+				t.Errorf("Error in TestFileRepositoryLookups:\n expected %s\n got %v", "NoCarriersFoundError", earlierErr)
+			}
+			if networkErr != nil || networkOperator.Status != model.OperatorStatusActive{
+				t.Errorf("Error in TestFileRepositoryLookups:\n expected %s\n got %v, %v", "A1 active", networkOperator, networkErr)
+			}
+			if detailsErr != nil || details.Alpha3 != "MKD"{
+				t.Errorf("Error in TestFileRepositoryLookups:\n expected %s\n got %v, %v", "MKD", details, detailsErr)
+			}
+		}
+		t.Run(name, fn)
+	}
+}
+
+func TestFileRepositoryRefresh(t *testing.T) {
+
+	//Arrange
+	path := filepath.Join(t.TempDir(), "plan.snapshot")
+	writeTestSnapshot(t, path, []model.Country{{CountryNumberFormat: "^389[0-9]{8}$", CountryCode: "389", CountryIdentifier: "mk", CountryCodeLength: 3}}, nil, nil)
+	repo, err := NewMSISDNRepositoryFile(path)
+	if err != nil{
+		t.Fatalf("Error in TestFileRepositoryRefresh:\n expected %s\n got %s", "nil", err)
+	}
+	unchanged, unchangedErr := repo.Refresh()
+
+	writeTestSnapshot(t, path, []model.Country{{CountryNumberFormat: "^48[0-9]{9}$", CountryCode: "48", CountryIdentifier: "pl", CountryCodeLength: 2}}, nil, nil)
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil{
+		t.Fatalf("Error changing snapshot time: %s", err)
+	}
+
+	//Act
+	changed, changedErr := repo.Refresh()
+	country, getErr := repo.GetCountryByIdentifier("pl")
+
+	//Assert
+	if unchanged || unchangedErr != nil{
+		t.Errorf("Error in TestFileRepositoryRefresh:\n expected %s\n got %v, %v", "no reload of the same file", unchanged, unchangedErr)
+	}
+	if !changed || changedErr != nil{
+		t.Errorf("Error in TestFileRepositoryRefresh:\n expected %s\n got %v, %v", "a reload of the changed file", changed, changedErr)
+	}
+	if getErr != nil || country.CountryCode != "48"{
+		t.Errorf("Error in TestFileRepositoryRefresh:\n expected %s\n got %v, %v", "pl", country, getErr)
+	}
+}
+
+func TestFileRepositoryKeepsSnapshotWhenFileIsInvalid(t *testing.T) {
+
+	//Arrange
+	path := filepath.Join(t.TempDir(), "plan.json")
+	writeTestSnapshot(t, path, []model.Country{{CountryNumberFormat: "^389[0-9]{8}$", CountryCode: "389", CountryIdentifier: "mk", CountryCodeLength: 3}}, nil, nil)
+	repo, err := NewMSISDNRepositoryFile(path)
+	if err != nil{
+		t.Fatalf("Error in TestFileRepositoryKeepsSnapshotWhenFileIsInvalid:\n expected %s\n got %s", "nil", err)
+	}
+	if err := os.WriteFile(path, []byte(`{"SchemaVersion": 2}`), 0644); err != nil{
+		t.Fatalf("Error writing snapshot: %s", err)
+	}
+
+	//Act
+	_, refreshErr := repo.Refresh()
+	countries, _ := repo.GetAllCountries()
+
+	//Assert
+	if _, ok := refreshErr.(*errs.InvalidFileError); !ok{
+		t.Errorf("Error in TestFileRepositoryKeepsSnapshotWhenFileIsInvalid:\n expected %s\n got %v", "InvalidFileError", refreshErr)
+	}
+	if len(*countries) != 1{
+		t.Errorf("Error in TestFileRepositoryKeepsSnapshotWhenFileIsInvalid:\n expected %s\n got %v", "the loaded snapshot", countries)
+	}
+}
+
+func TestFileRepositoryIsReadOnly(t *testing.T) {
+
+	//Arrange
+	path := filepath.Join(t.TempDir(), "plan.json")
+	writeTestSnapshot(t, path, nil, nil, nil)
+	repo, err := NewMSISDNRepositoryFile(path)
+	if err != nil{
+		t.Fatalf("Error in TestFileRepositoryIsReadOnly:\n expected %s\n got %s", "nil", err)
+	}
+
+	//Act
+	addErr := repo.AddNewCountry(&model.Country{CountryNumberFormat: "^389[0-9]{8}$"})
+
+	//Assert
+	if _, ok := addErr.(*errs.ReadOnlyPlanError); !ok{
+		t.Errorf("Error in TestFileRepositoryIsReadOnly:\n expected %s\n got %v", "ReadOnlyPlanError", addErr)
+	}
+}
@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/dto"
+	"github.com/robesmi/MSISDNApp/model/errs"
+	"github.com/robesmi/MSISDNApp/numplan"
+)
+
+// MSISDNRepositoryIndex answers lookups from an in-memory copy of the numbering plan
+// loaded from a backing repository. Changes are written through to the backing
+// repository, after which the plan is rebuilt and swapped in atomically
+type MSISDNRepositoryIndex struct {
+	backing MSISDNRepository
+	plan atomic.Pointer[numplan.Plan]
+	// networkOperators maps brand names to network operators
+	networkOperators atomic.Pointer[map[string]model.NetworkOperator]
+	// countryDetails maps ISO 3166-1-alpha-2 identifiers to the details of the countries
+	countryDetails atomic.Pointer[map[string]model.CountryDetails]
+	mu sync.Mutex
+	// reloaded are called after each reload of the plan
+	reloaded []func()
+}
+
+// NewMSISDNRepositoryIndex loads the numbering plan from the backing repository
+// and returns a repository serving lookups from it
+func NewMSISDNRepositoryIndex(backing MSISDNRepository) (*MSISDNRepositoryIndex, error){
+
+	repo := &MSISDNRepositoryIndex{backing: backing}
+	if err := repo.Reload(); err != nil{
+		return nil, err
+	}
+	return repo, nil
+}
+
+// Reload rebuilds the plan from the backing repository and replaces the current one
+func (repo *MSISDNRepositoryIndex) Reload() (error){
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	countries, err := repo.backing.GetAllCountries()
+	if err != nil{
+		return errs.NewUnexpectedError(err.Error())
+	}
+	operators, err := repo.backing.GetAllMobileOperators()
+	if err != nil{
+		return errs.NewUnexpectedError(err.Error())
+	}
+	networkOperators, err := repo.backing.GetAllNetworkOperators()
+	if err != nil{
+		return errs.NewUnexpectedError(err.Error())
+	}
+	details, err := repo.backing.GetAllCountryDetails()
+	if err != nil{
+		return errs.NewUnexpectedError(err.Error())
+	}
+	byName := make(map[string]model.NetworkOperator, len(*networkOperators))
+	for _, operator := range *networkOperators{
+		byName[operator.BrandName] = operator
+	}
+	repo.plan.Store(numplan.NewPlan(*countries, *operators))
+	repo.networkOperators.Store(&byName)
+	byIdentifier := make(map[string]model.CountryDetails, len(*details))
+	for _, country := range *details{
+		byIdentifier[country.CountryIdentifier] = country
+	}
+	repo.countryDetails.Store(&byIdentifier)
+	for _, fn := range repo.reloaded{
+		fn()
+	}
+	return nil
+}
+
+// OnReload registers a function called after each reload of the plan, for whatever
+// holds on to results of the previous one
+func (repo *MSISDNRepositoryIndex) OnReload(fn func()){
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.reloaded = append(repo.reloaded, fn)
+}
+
+// Unsupported returns the patterns the current plan could not compile. Lookups
+// that could only be answered by one of them are passed on to the backing repository
+func (repo *MSISDNRepositoryIndex) Unsupported() []string{
+	return repo.plan.Load().Unsupported()
+}
+
+func (repo *MSISDNRepositoryIndex) LookupCountryCode(fullnumber string, at time.Time) (*dto.CountryLookupResponse, error){
+
+	plan := repo.plan.Load()
+	country, ok := plan.LookupCountry(fullnumber, at)
+	if !ok{
+		if !plan.CountriesComplete(){
+			return repo.backing.LookupCountryCode(fullnumber, at)
+		}
+		return nil, errs.NewNumberNotFoundError()
+	}
+	return &dto.CountryLookupResponse{
+		CountryCode: country.CountryCode,
+		CountryIdentifier: country.CountryIdentifier,
+		CountryCodeLength: country.CountryCodeLength,
+		TrunkPrefix: country.TrunkPrefix,
+		NumberGrouping: country.NumberGrouping,
+	}, nil
+}
+
+func (repo *MSISDNRepositoryIndex) LookupMobileOperator(ci string, significantNumber string, at time.Time) (*dto.MobileOperatorLookupResponse, error){
+
+	plan := repo.plan.Load()
+	operator, ok := plan.LookupOperator(ci, significantNumber, at)
+	if !ok{
+		if !plan.OperatorsComplete(ci){
+			return repo.backing.LookupMobileOperator(ci, significantNumber, at)
+		}
+		return nil, errs.NewNoCarriersFoundError()
+	}
+	response := operator.ToDto()
+	return &response, nil
+}
+
+func (repo *MSISDNRepositoryIndex) GetAllCountries() (*[]model.Country, error){
+	return repo.backing.GetAllCountries()
+}
+
+func (repo *MSISDNRepositoryIndex) GetAllMobileOperators() (*[]model.MobileOperator, error){
+	return repo.backing.GetAllMobileOperators()
+}
+
+func (repo *MSISDNRepositoryIndex) LookupCallingCode(fullnumber string, at time.Time) (*model.Country, error){
+
+	country, ok := repo.plan.Load().CountryByCallingCode(fullnumber, at)
+	if !ok{
+		return nil, errs.NewCountryNotFoundError()
+	}
+	return country, nil
+}
+
+func (repo *MSISDNRepositoryIndex) LookupPartial(digits string, at time.Time) (*model.PartialMatch, error){
+
+	match := repo.plan.Load().Partial(digits, at)
+	return &match, nil
+}
+
+func (repo *MSISDNRepositoryIndex) GetCountryByIdentifier(ci string) (*model.Country, error){
+
+	country, ok := repo.plan.Load().CountryByIdentifier(ci, time.Now().UTC())
+	if !ok{
+		return nil, errs.NewCountryNotFoundError()
+	}
+	return country, nil
+}
+
+func (repo *MSISDNRepositoryIndex) AddNewCountry(country *model.Country) (error){
+
+	if err := repo.backing.AddNewCountry(country); err != nil{
+		return err
+	}
+	return repo.Reload()
+}
+
+func (repo *MSISDNRepositoryIndex) AddNewMobileOperator(operator *model.MobileOperator) (error){
+
+	if err := repo.backing.AddNewMobileOperator(operator); err != nil{
+		return err
+	}
+	return repo.Reload()
+}
+
+func (repo *MSISDNRepositoryIndex) RemoveCountry(prefix string, at time.Time) (error){
+
+	if err := repo.backing.RemoveCountry(prefix, at); err != nil{
+		return err
+	}
+	return repo.Reload()
+}
+
+func (repo *MSISDNRepositoryIndex) RemoveOperator(prefix string, at time.Time) (error){
+
+	if err := repo.backing.RemoveOperator(prefix, at); err != nil{
+		return err
+	}
+	return repo.Reload()
+}
+
+func (repo *MSISDNRepositoryIndex) GetNetworkOperatorByName(name string) (*model.NetworkOperator, error){
+
+	operator, ok := (*repo.networkOperators.Load())[name]
+	if !ok{
+		return nil, errs.NewOperatorNotFoundError()
+	}
+	return &operator, nil
+}
+
+func (repo *MSISDNRepositoryIndex) GetAllNetworkOperators() (*[]model.NetworkOperator, error){
+	return repo.backing.GetAllNetworkOperators()
+}
+
+func (repo *MSISDNRepositoryIndex) AddNewNetworkOperator(operator *model.NetworkOperator) (error){
+
+	if err := repo.backing.AddNewNetworkOperator(operator); err != nil{
+		return err
+	}
+	return repo.Reload()
+}
+
+func (repo *MSISDNRepositoryIndex) RemoveNetworkOperator(id int) (error){
+
+	if err := repo.backing.RemoveNetworkOperator(id); err != nil{
+		return err
+	}
+	return repo.Reload()
+}
+
+func (repo *MSISDNRepositoryIndex) GetCountryDetails(ci string) (*model.CountryDetails, error){
+
+	details, ok := (*repo.countryDetails.Load())[ci]
+	if !ok{
+		return nil, errs.NewCountryNotFoundError()
+	}
+	return &details, nil
+}
+
+func (repo *MSISDNRepositoryIndex) GetAllCountryDetails() (*[]model.CountryDetails, error){
+	return repo.backing.GetAllCountryDetails()
+}
+
+func (repo *MSISDNRepositoryIndex) SaveCountryDetails(details *model.CountryDetails) (error){
+
+	if err := repo.backing.SaveCountryDetails(details); err != nil{
+		return err
+	}
+	return repo.Reload()
+}
+
+func (repo *MSISDNRepositoryIndex) UpdatePlan(update *model.PlanUpdate) (error){
+
+	if err := repo.backing.UpdatePlan(update); err != nil{
+		return err
+	}
+	return repo.Reload()
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mocks "github.com/robesmi/MSISDNApp/mocks/repository"
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/dto"
+	"github.com/robesmi/MSISDNApp/model/errs"
+)
+
+// now is the time the tests look the numbering plan up at
+var now = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func setupIndex(t *testing.T, countries []model.Country, operators []model.MobileOperator, networkOperators []model.NetworkOperator) (*mocks.MockMSISDNRepository, *MSISDNRepositoryIndex){
+
+	ctrl := gomock.NewController(t)
+	backing := mocks.NewMockMSISDNRepository(ctrl)
+	backing.EXPECT().GetAllCountries().Return(&countries, nil)
+	backing.EXPECT().GetAllMobileOperators().Return(&operators, nil)
+	backing.EXPECT().GetAllNetworkOperators().Return(&networkOperators, nil)
+	backing.EXPECT().GetAllCountryDetails().Return(&[]model.CountryDetails{}, nil).AnyTimes()
+
+	index, err := NewMSISDNRepositoryIndex(backing)
+	if err != nil{
+		t.Fatalf("Error loading index: %s", err)
+	}
+	return backing, index
+}
+
+func TestIndexLookupCountryCode(t *testing.T) {
+
+	//Arrange
+	countries := []model.Country{
+		{CountryNumberFormat: "^389[0-9]{8}$", CountryCode: "389", CountryIdentifier: "mk", CountryCodeLength: 3},
+	}
+	_, index := setupIndex(t, countries, nil, nil)
+
+	//Act
+	resp, getErr := index.LookupCountryCode("38977123456", now)
+	_, missErr := index.LookupCountryCode("6934567890", now)
+
+	//Assert
+	if getErr != nil{
+		t.Fatalf("Error in TestIndexLookupCountryCode:\n expected %s\n got %s", "nil", getErr)
+	}
+	if resp.CountryIdentifier != "mk"{
+		t.Errorf("Error in TestIndexLookupCountryCode:\n expected %s\n got %s", "mk", resp.CountryIdentifier)
+	}
+	if _, ok := missErr.(*errs.NumberNotFoundError); !ok{
+		t.Errorf("Error in TestIndexLookupCountryCode:\n expected %s\n got %v", "NumberNotFoundError", missErr)
+	}
+}
+
+func TestIndexLookupPartial(t *testing.T) {
+
+	//Arrange
+	countries := []model.Country{
+		{CountryNumberFormat: "^389[0-9]{8}$", CountryCode: "389", CountryIdentifier: "mk", CountryCodeLength: 3},
+	}
+	operators := []model.MobileOperator{
+		{CountryIdentifier: "mk", PrefixFormat: "^77[0-9]{6}$", MNO: "A1", PrefixLength: 2},
+	}
+	_, index := setupIndex(t, countries, operators, nil)
+
+	//Act
+	partial, partialErr := index.LookupPartial("3897", now)
+	complete, completeErr := index.LookupPartial("38977123456", now)
+
+	//Assert
+	if partialErr != nil || completeErr != nil{
+		t.Fatalf("Error in TestIndexLookupPartial:\n expected %s\n got %v, %v", "nil", partialErr, completeErr)
+	}
+	if partial.Complete || partial.MinRemaining != 7 || len(partial.Countries) != 1 || len(partial.Operators) != 1{
+		t.Errorf("Error in TestIndexLookupPartial:\n expected %s\n got %+v", "mk and A1 with 7 digits remaining", partial)
+	}
+	if !complete.Complete || complete.MaxRemaining != 0{
+		t.Errorf("Error in TestIndexLookupPartial:\n expected %s\n got %+v", "a complete number", complete)
+	}
+}
+
+func TestIndexFallsBackForUnsupportedPatterns(t *testing.T) {
+
+	//Arrange
+	operators := []model.MobileOperator{
+		{CountryIdentifier: "pl", PrefixFormat: "(?!^5329[0-9]{5}$)^53[0-7][0-9]{6}$", MNO: "Orange Polska S.A", PrefixLength: 2},
+	}
+	backing, index := setupIndex(t, nil, operators, nil)
+	expResp := dto.MobileOperatorLookupResponse{MNO: "Orange Polska S.A", PrefixLength: 2}
+	backing.EXPECT().LookupMobileOperator("pl", "531123456", now).Return(&expResp, nil)
+
+	//Act
+	resp, err := index.LookupMobileOperator("pl", "531123456", now)
+
+	//Assert
+	if err != nil || resp.MNO != expResp.MNO{
+		t.Errorf("Error in TestIndexFallsBackForUnsupportedPatterns:\n expected %s\n got %v, %v", expResp.MNO, resp, err)
+	}
+}
+
+func TestIndexReloadsAfterChange(t *testing.T) {
+
+	//Arrange
+	backing, index := setupIndex(t, nil, nil, nil)
+	added := []model.MobileOperator{
+		{CountryIdentifier: "mk", PrefixFormat: "^77[0-9]{6}$", MNO: "A1", PrefixLength: 2, NumberType: model.NumberTypeMobile},
+	}
+	gomock.InOrder(
+		backing.EXPECT().AddNewMobileOperator(&added[0]).Return(nil),
+		backing.EXPECT().GetAllCountries().Return(&[]model.Country{}, nil),
+		backing.EXPECT().GetAllMobileOperators().Return(&added, nil),
+		backing.EXPECT().GetAllNetworkOperators().Return(&[]model.NetworkOperator{}, nil),
+	)
+
+	//Act
+	addErr := index.AddNewMobileOperator(&added[0])
+	resp, getErr := index.LookupMobileOperator("mk", "77123456", now)
+
+	//Assert
+	if addErr != nil || getErr != nil{
+		t.Fatalf("Error in TestIndexReloadsAfterChange:\n expected %s\n got %v, %v", "nil", addErr, getErr)
+	}
+	if resp.MNO != "A1" || resp.NumberType != model.NumberTypeMobile{
+		t.Errorf("Error in TestIndexReloadsAfterChange:\n expected %s\n got %s %s", "A1 mobile", resp.MNO, resp.NumberType)
+	}
+}
+
+func TestIndexKeepsPlanWhenWriteFails(t *testing.T) {
+
+	//Arrange
+	backing, index := setupIndex(t, nil, nil, nil)
+	expErr := errors.New("write failed")
+	backing.EXPECT().RemoveCountry("^389[0-9]{8}$", now).Return(expErr)
+
+	//Act
+	err := index.RemoveCountry("^389[0-9]{8}$", now)
+
+	//Assert
+	if !errors.Is(err, expErr){
+		t.Errorf("Error in TestIndexKeepsPlanWhenWriteFails:\n expected %s\n got %v", expErr, err)
+	}
+}
+
+func TestIndexGetCountryByIdentifier(t *testing.T) {
+
+	//Arrange
+	countries := []model.Country{
+		{CountryNumberFormat: "(?!^38999)^389[0-9]{8}$", CountryCode: "389", CountryIdentifier: "mk", CountryCodeLength: 3, TrunkPrefix: "0"},
+	}
+	_, index := setupIndex(t, countries, nil, nil)
+
+	//Act
+	country, getErr := index.GetCountryByIdentifier("mk")
+	_, missErr := index.GetCountryByIdentifier("zz")
+
+	//Assert
+	if getErr != nil || country.TrunkPrefix != "0"{
+		t.Errorf("Error in TestIndexGetCountryByIdentifier:\n expected %s\n got %v, %v", "mk", country, getErr)
+	}
+	if _, ok := missErr.(*errs.CountryNotFoundError); !ok{
+		t.Errorf("Error in TestIndexGetCountryByIdentifier:\n expected %s\n got %v", "CountryNotFoundError", missErr)
+	}
+}
+
+func TestIndexGetNetworkOperatorByName(t *testing.T) {
+
+	//Arrange
+	networkOperators := []model.NetworkOperator{
+		{ID: 1, BrandName: "MEO", NetworkCodes: "268-06"},
+		{ID: 2, BrandName: "CTT", HostOperatorID: 1, HostNetwork: "MEO"},
+	}
+	_, index := setupIndex(t, nil, nil, networkOperators)
+
+	//Act
+	operator, getErr := index.GetNetworkOperatorByName("CTT")
+	_, missErr := index.GetNetworkOperatorByName("Vodafone")
+
+	//Assert
+	if getErr != nil || operator.ID != 2 || operator.HostNetwork != "MEO"{
+		t.Errorf("Error in TestIndexGetNetworkOperatorByName:\n expected %s\n got %v, %v", "CTT hosted on MEO", operator, getErr)
+	}
+	if _, ok := missErr.(*errs.OperatorNotFoundError); !ok{
+		t.Errorf("Error in TestIndexGetNetworkOperatorByName:\n expected %s\n got %v", "OperatorNotFoundError", missErr)
+	}
+}
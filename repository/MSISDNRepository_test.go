@@ -1,9 +1,13 @@
 package repository
 
 import (
+	"database/sql"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
+
 	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/errs"
 )
 
 var lookupRepo MSISDNRepository
@@ -20,10 +24,10 @@ func TestLookupCountryCode(t *testing.T) {
 	}
 	rows := mock.NewRows([]string{"country_code","country_identifier","country_code_length"}).
 	AddRow(exampleCountry.CountryCode, exampleCountry.CountryIdentifier, exampleCountry.CountryCodeLength)
-	mock.ExpectQuery("SELECT").WithArgs("1").WillReturnRows(rows)
+	mock.ExpectQuery("SELECT").WithArgs("1","1",now,now).WillReturnRows(rows)
 
 	//Act
-	resp, getErr := lookupRepo.LookupCountryCode("1")
+	resp, getErr := lookupRepo.LookupCountryCode("1", now)
 
 	//Assert
 
@@ -45,12 +49,12 @@ func TestLookupMobileOperator(t *testing.T) {
 		MNO: "test",
 		PrefixLength: 1,
 	}
-	rows := mock.NewRows([]string{"mno","prefix_length"}).
-	AddRow(exampleOperator.MNO, exampleOperator.PrefixLength)
-	mock.ExpectQuery("SELECT").WithArgs("tt","1").WillReturnRows(rows)
+	rows := mock.NewRows([]string{"mno","prefix_length","operator_id","network_codes"}).
+	AddRow(exampleOperator.MNO, exampleOperator.PrefixLength, 2, "294-03")
+	mock.ExpectQuery("SELECT").WithArgs("tt","1","1",now,now).WillReturnRows(rows)
 
 	//Act
-	resp, getErr := lookupRepo.LookupMobileOperator("tt","1")
+	resp, getErr := lookupRepo.LookupMobileOperator("tt","1", now)
 
 	//Assert
 
@@ -60,5 +64,71 @@ func TestLookupMobileOperator(t *testing.T) {
 	if resp.MNO != exampleOperator.MNO{
 		t.Errorf("Error in TestLookupMobileOperator:\n expected %s\n got %s", exampleOperator.MNO, resp.MNO)
 	}
+	if resp.OperatorID != 2 || resp.NetworkCodes != "294-03"{
+		t.Errorf("Error in TestLookupMobileOperator:\n expected %s\n got %d %s", "2 294-03", resp.OperatorID, resp.NetworkCodes)
+	}
+}
+
+func TestGetNetworkOperatorByNameNotFound(t *testing.T) {
+
+	//Arrange
+	mock := setup(t)
+	mock.ExpectQuery("SELECT").WithArgs("Vodafone").WillReturnError(sql.ErrNoRows)
+
+	//Act
+	_, err := lookupRepo.GetNetworkOperatorByName("Vodafone")
+
+	//Assert
+	if _, ok := err.(*errs.OperatorNotFoundError); !ok{
+		t.Errorf("Error in TestGetNetworkOperatorByNameNotFound:\n expected %s\n got %v", "OperatorNotFoundError", err)
+	}
 }
 
+
+func TestRemoveOperatorEndsRule(t *testing.T) {
+
+	//Arrange
+	mock := setup(t)
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM mobile_operators").WithArgs("^7[0-9]{7}$", now).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE mobile_operators").WithArgs(now, "^7[0-9]{7}$", now, now).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	//Act
+	err := lookupRepo.RemoveOperator("^7[0-9]{7}$", now)
+
+	//Assert
+	if err != nil{
+		t.Errorf("Error in TestRemoveOperatorEndsRule:\n expected %s\n got %s", "nil", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil{
+		t.Errorf("Error in TestRemoveOperatorEndsRule:\n expected the rule to be ended\n got %s", err)
+	}
+}
+
+func TestUpdatePlan(t *testing.T) {
+
+	//Arrange
+	mock := setup(t)
+	update := model.PlanUpdate{
+		RemovedOperators: []int{3},
+		ChangedCountries: []model.Country{{ID: 1, CountryNumberFormat: "^389[0-9]{8}$", CountryIdentifier: "mk"}},
+		AddedOperators: []model.MobileOperator{{CountryIdentifier: "mk", PrefixFormat: "^2[0-9]{7}$", OperatorID: 3}},
+	}
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM mobile_operators").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE countries SET").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO mobile_operators").WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	//Act
+	err := lookupRepo.UpdatePlan(&update)
+
+	//Assert
+	if _, ok := err.(*errs.UnexpectedError); !ok{
+		t.Errorf("Error in TestUpdatePlan:\n expected %s\n got %v", "UnexpectedError", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil{
+		t.Errorf("Error in TestUpdatePlan:\n expected the transaction to be rolled back\n got %s", err)
+	}
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/errs"
+)
+
+type PortingRepositoryDb struct {
+	db *sqlx.DB
+	dialect dialect
+}
+
+func NewPortingRepository(dbClient *sqlx.DB) PortingRepositoryDb{
+	return PortingRepositoryDb{dbClient, dialectOf(dbClient)}
+}
+
+//go:generate mockgen -destination=../mocks/repository/mockPortingRepository.go -package=repository github.com/robesmi/MSISDNApp/repository PortingRepository
+type PortingRepository interface {
+	// GetAllPortedNumbers calls the function with every ported number, stopping at the first error
+	GetAllPortedNumbers(func(model.PortedNumber) error) (error)
+	// ApplyPortedNumbers saves the batches returned by the function in a single transaction until it returns
+	// an empty batch. Numbers without an MNO are removed, and when replace is set every number that isn't
+	// in the batches is removed as well
+	ApplyPortedNumbers(bool, func() ([]model.PortedNumber, error)) (error)
+}
+
+func (repo PortingRepositoryDb) GetAllPortedNumbers(fn func(model.PortedNumber) error) (error){
+
+	rows, err := repo.db.Queryx("SELECT msisdn, mno FROM ported_numbers")
+	if err != nil{
+		return errs.NewUnexpectedError(err.Error())
+	}
+	defer rows.Close()
+	for rows.Next(){
+		var number model.PortedNumber
+		if err := rows.StructScan(&number); err != nil{
+			return errs.NewUnexpectedError(err.Error())
+		}
+		if err := fn(number); err != nil{
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil{
+		return errs.NewUnexpectedError(err.Error())
+	}
+	return nil
+}
+
+func (repo PortingRepositoryDb) ApplyPortedNumbers(replace bool, next func() ([]model.PortedNumber, error)) (error){
+
+	tx, err := repo.db.Beginx()
+	if err != nil{
+		return errs.NewUnexpectedError(err.Error())
+	}
+	defer tx.Rollback()
+
+	if replace{
+		if _, err := tx.Exec("DELETE FROM ported_numbers"); err != nil{
+			return errs.NewUnexpectedError(err.Error())
+		}
+	}
+	for {
+		batch, err := next()
+		if err != nil{
+			return err
+		}
+		if len(batch) == 0{
+			break
+		}
+
+		// Only the last change of a number in the batch counts, as when the rows are applied
+		// in file order like portability.Store does. A multi-row upsert can't change the same
+		// row twice either
+		var order []string
+		last := map[string]string{}
+		for _, number := range batch{
+			if _, ok := last[number.MSISDN]; !ok{
+				order = append(order, number.MSISDN)
+			}
+			last[number.MSISDN] = number.MNO
+		}
+		var saved []interface{}
+		var removed []interface{}
+		for _, msisdn := range order{
+			if last[msisdn] == ""{
+				removed = append(removed, msisdn)
+			}else{
+				saved = append(saved, msisdn, last[msisdn])
+			}
+		}
+		// The batch is split into statements within the dialect's limit of bind variables,
+		// two for each saved number and one for each removed one
+		for start := 0; start < len(saved); start += repo.dialect.maxVariables / 2 * 2{
+			args := saved[start:minInt(start + repo.dialect.maxVariables / 2 * 2, len(saved))]
+			values := strings.TrimSuffix(strings.Repeat("(?,?),", len(args)/2), ",")
+			sqlSave := "INSERT INTO ported_numbers (msisdn, mno) VALUES " + values + " " + repo.dialect.upsertPorted
+			if _, err := tx.Exec(tx.Rebind(sqlSave), args...); err != nil{
+				return errs.NewUnexpectedError(err.Error())
+			}
+		}
+		for start := 0; start < len(removed); start += repo.dialect.maxVariables{
+			args := removed[start:minInt(start + repo.dialect.maxVariables, len(removed))]
+			sqlRemove := "DELETE FROM ported_numbers WHERE msisdn IN (" + placeholders(len(args)) + ")"
+			if _, err := tx.Exec(tx.Rebind(sqlRemove), args...); err != nil{
+				return errs.NewUnexpectedError(err.Error())
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil{
+		return errs.NewUnexpectedError(err.Error())
+	}
+	return nil
+}
+
+func minInt(a int, b int) int{
+
+	if a < b{
+		return a
+	}
+	return b
+}
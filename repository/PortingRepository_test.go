@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/robesmi/MSISDNApp/model"
+)
+
+var portingRepo PortingRepository
+
+func TestGetAllPortedNumbers(t *testing.T) {
+
+	//Arrange
+	mock := setup(t)
+	rows := mock.NewRows([]string{"msisdn","mno"}).
+	AddRow("38977123456", "Telekom").
+	AddRow("38971123456", "A1")
+	mock.ExpectQuery("SELECT").WillReturnRows(rows)
+
+	//Act
+	var numbers []model.PortedNumber
+	err := portingRepo.GetAllPortedNumbers(func(number model.PortedNumber) error {
+		numbers = append(numbers, number)
+		return nil
+	})
+
+	//Assert
+	if err != nil{
+		t.Fatalf("Error in TestGetAllPortedNumbers:\n expected %s\n got %s", "nil", err)
+	}
+	if len(numbers) != 2 || numbers[1].MNO != "A1"{
+		t.Errorf("Error in TestGetAllPortedNumbers:\n expected %d numbers\n got %v", 2, numbers)
+	}
+}
+
+func TestApplyPortedNumbers(t *testing.T) {
+
+	//Arrange
+	mock := setup(t)
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM ported_numbers$").WillReturnResult(sqlmock.NewResult(0, 5))
+	mock.ExpectExec("INSERT INTO ported_numbers").WithArgs("38977123456", "Telekom").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM ported_numbers WHERE msisdn IN").WithArgs("38971123456").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	batches := [][]model.PortedNumber{
+		{{MSISDN: "38977123456", MNO: "Telekom"}, {MSISDN: "38971123456"}},
+	}
+
+	//Act
+	err := portingRepo.ApplyPortedNumbers(true, func() ([]model.PortedNumber, error) {
+		if len(batches) == 0{
+			return nil, nil
+		}
+		batch := batches[0]
+		batches = batches[1:]
+		return batch, nil
+	})
+
+	//Assert
+	if err != nil{
+		t.Errorf("Error in TestApplyPortedNumbers:\n expected %s\n got %s", "nil", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil{
+		t.Errorf("Error in TestApplyPortedNumbers:\n %s", err)
+	}
+}
+
+func TestApplyPortedNumbersRollsBack(t *testing.T) {
+
+	//Arrange
+	mock := setup(t)
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	readErr := errors.New("bad row")
+
+	//Act
+	err := portingRepo.ApplyPortedNumbers(false, func() ([]model.PortedNumber, error) {
+		return nil, readErr
+	})
+
+	//Assert
+	if err != readErr{
+		t.Errorf("Error in TestApplyPortedNumbersRollsBack:\n expected %s\n got %v", readErr, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil{
+		t.Errorf("Error in TestApplyPortedNumbersRollsBack:\n %s", err)
+	}
+}
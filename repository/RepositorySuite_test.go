@@ -0,0 +1,453 @@
+package repository
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/errs"
+)
+
+// The suite runs every repository against the real databases: SQLite in a temporary
+// file, and PostgreSQL and MySQL when TEST_POSTGRES_SOURCE and TEST_MYSQL_SOURCE point
+// to a database the tests may empty
+var suiteDatabases = []struct{
+	Driver string
+	SourceVariable string
+}{
+	{DriverSQLite, ""},
+	{DriverPostgres, "TEST_POSTGRES_SOURCE"},
+	{DriverMySQL, "TEST_MYSQL_SOURCE"},
+}
+
+// suiteTables are dropped before every test, referencing tables first
+var suiteTables = []string{"mobile_operators", "network_operators", "countries", "country_details", "ported_numbers", "users", "lookup_jobs", "schema_migrations"}
+
+// forEachDatabase runs the test against an empty database of every available driver
+func forEachDatabase(t *testing.T, test func(t *testing.T, db *sqlx.DB)){
+
+	for _, database := range suiteDatabases{
+		fn := func(t *testing.T){
+
+			source := filepath.Join(t.TempDir(), "msisdn.db")
+			if database.SourceVariable != ""{
+				var set bool
+				if source, set = os.LookupEnv(database.SourceVariable); !set{
+					t.Skipf("%s is not set", database.SourceVariable)
+				}
+			}
+			db, err := Open(database.Driver, source)
+			if err != nil{
+				t.Fatalf("Error opening %s: %s", database.Driver, err)
+			}
+			defer db.Close()
+			if err := db.Ping(); err != nil{
+				t.Skipf("%s is not available: %s", database.Driver, err)
+			}
+			for _, table := range suiteTables{
+				if _, err := db.Exec("DROP TABLE IF EXISTS " + table); err != nil{
+					t.Fatalf("Error dropping %s: %s", table, err)
+				}
+			}
+			if err := CreateSchema(db); err != nil{
+				t.Fatalf("Error creating schema: %s", err)
+			}
+			test(t, db)
+		}
+		t.Run(database.Driver, fn)
+	}
+}
+
+// addSuitePlan saves the mk country with the A1 and Telekom ranges
+func addSuitePlan(t *testing.T, repo MSISDNRepository){
+
+	for _, operator := range []model.NetworkOperator{
+		{BrandName: "A1", LegalName: "A1 Makedonija", Status: model.OperatorStatusActive, NetworkCodes: "294-03"},
+		{BrandName: "Telekom", LegalName: "Makedonski Telekom", Status: model.OperatorStatusActive, NetworkCodes: "294-01"},
+	}{
+		if err := repo.AddNewNetworkOperator(&operator); err != nil{
+			t.Fatalf("Error adding network operator: %s", err)
+		}
+	}
+	country := model.Country{CountryNumberFormat: "^389[0-9]{8}$", CountryCode: "389", CountryIdentifier: "mk", CountryCodeLength: 3, TrunkPrefix: "0", InternationalPrefix: "00"}
+	if err := repo.AddNewCountry(&country); err != nil{
+		t.Fatalf("Error adding country: %s", err)
+	}
+	for _, operator := range []model.MobileOperator{
+		{CountryIdentifier: "mk", PrefixFormat: "^7[0-9]{7}$", ExcludedFormat: "^71[0-9]{6}$", OperatorID: 1, PrefixLength: 2, NumberType: model.NumberTypeMobile},
+		{CountryIdentifier: "mk", PrefixFormat: "^71[0-9]{6}$", OperatorID: 2, PrefixLength: 2, NumberType: model.NumberTypeMobile},
+	}{
+		if err := repo.AddNewMobileOperator(&operator); err != nil{
+			t.Fatalf("Error adding range: %s", err)
+		}
+	}
+}
+
+func TestSuiteNumberingPlan(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *sqlx.DB){
+
+		//Arrange
+		repo := NewMSISDNRepository(db)
+		addSuitePlan(t, repo)
+
+		//Act
+		country, countryErr := repo.LookupCountryCode("38977123456", now)
+		_, missErr := repo.LookupCountryCode("4877123456", now)
+		operator, operatorErr := repo.LookupMobileOperator("mk", "77123456", now)
+		excluded, excludedErr := repo.LookupMobileOperator("mk", "71123456", now)
+		byCode, byCodeErr := repo.LookupCallingCode("38970", now)
+		networkOperator, networkErr := repo.GetNetworkOperatorByName("Telekom")
+		countries, _ := repo.GetAllCountries()
+		operators, _ := repo.GetAllMobileOperators()
+
+		//Assert
+		if countryErr != nil || country.CountryIdentifier != "mk" || country.TrunkPrefix != "0"{
+			t.Errorf("Error in TestSuiteNumberingPlan:\n expected %s\n got %v, %v", "mk", country, countryErr)
+		}
+		if _, ok := missErr.(*errs.NumberNotFoundError); !ok{
+			t.Errorf("Error in TestSuiteNumberingPlan:\n expected %s\n got %v", "NumberNotFoundError", missErr)
+		}
+		if operatorErr != nil || operator.MNO != "A1" || operator.NetworkCodes != "294-03"{
+			t.Errorf("Error in TestSuiteNumberingPlan:\n expected %s\n got %v, %v", "A1 294-03", operator, operatorErr)
+		}
+		if excludedErr != nil || excluded.MNO != "Telekom"{
+			t.Errorf("Error in TestSuiteNumberingPlan:\n expected %s\n got %v, %v", "Telekom", excluded, excludedErr)
+		}
+		if byCodeErr != nil || byCode.CountryIdentifier != "mk"{
+			t.Errorf("Error in TestSuiteNumberingPlan:\n expected %s\n got %v, %v", "mk", byCode, byCodeErr)
+		}
+		if networkErr != nil || networkOperator.ID != 2{
+			t.Errorf("Error in TestSuiteNumberingPlan:\n expected %s\n got %v, %v", "operator 2", networkOperator, networkErr)
+		}
+		if len(*countries) != 1 || len(*operators) != 2{
+			t.Errorf("Error in TestSuiteNumberingPlan:\n expected %s\n got %d, %d", "1 country and 2 ranges", len(*countries), len(*operators))
+		}
+	})
+}
+
+func TestSuiteCreateSchemaAgain(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *sqlx.DB){
+
+		//Arrange
+		repo := NewMSISDNRepository(db)
+		addSuitePlan(t, repo)
+		migrations, _ := schemas.ReadDir("schema/migrations/" + dialectOf(db).name)
+
+		//Act
+		err := CreateSchema(db)
+		var recorded int
+		db.Get(&recorded, "SELECT COUNT(*) FROM schema_migrations")
+		countries, _ := repo.GetAllCountries()
+
+		//Assert
+		if err != nil || recorded != len(migrations){
+			t.Errorf("Error in TestSuiteCreateSchemaAgain:\n expected %d migrations\n got %d, %v", len(migrations), recorded, err)
+		}
+		if len(*countries) != 1{
+			t.Errorf("Error in TestSuiteCreateSchemaAgain:\n expected %s\n got %d", "1 country", len(*countries))
+		}
+	})
+}
+
+func TestSuiteUpgradeLegacySchema(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *sqlx.DB){
+
+		if dialectOf(db).name != DriverMySQL{
+			t.Skip("only MySQL deployments have tables from before schema_migrations")
+		}
+		//Arrange
+		for _, statement := range []string{
+			"DROP TABLE mobile_operators",
+			"DROP TABLE network_operators",
+			"DROP TABLE countries",
+			"DROP TABLE schema_migrations",
+			"CREATE TABLE countries (country_number_format varchar(20) NOT NULL, country_code varchar(6) NOT NULL, country_identifier varchar(3) NOT NULL, country_code_length int NOT NULL, PRIMARY KEY (country_number_format))",
+			"CREATE TABLE mobile_operators (country_identifier varchar(3) NOT NULL, prefix_format varchar(60) NOT NULL, mno varchar(100) NOT NULL, prefix_length int NOT NULL, PRIMARY KEY (country_identifier, prefix_format))",
+			"INSERT INTO countries VALUES ('^389[0-9]{8}$', '389', 'mk', 3)",
+			"INSERT INTO mobile_operators VALUES ('mk', '^77[0-9]{6}$', 'A1', 2), ('mk', '^71[0-9]{6}$', 'Telekom', 2), ('mk', '^78[0-9]{6}$', 'A1', 2)",
+		}{
+			if _, err := db.Exec(statement); err != nil{
+				t.Fatalf("Error creating legacy tables: %s", err)
+			}
+		}
+		repo := NewMSISDNRepository(db)
+
+		//Act
+		err := CreateSchema(db)
+		country, countryErr := repo.LookupCountryCode("38977123456", now)
+		operator, operatorErr := repo.LookupMobileOperator("mk", "78123456", now)
+		networkOperators, _ := repo.GetAllNetworkOperators()
+		var version int
+		db.Get(&version, "SELECT MAX(version) FROM schema_migrations")
+
+		//Assert
+		if err != nil || version != 1{
+			t.Fatalf("Error in TestSuiteUpgradeLegacySchema:\n expected %s\n got %d, %v", "version 1", version, err)
+		}
+		if countryErr != nil || country.CountryIdentifier != "mk"{
+			t.Errorf("Error in TestSuiteUpgradeLegacySchema:\n expected %s\n got %v, %v", "mk", country, countryErr)
+		}
+		if operatorErr != nil || operator.MNO != "A1"{
+			t.Errorf("Error in TestSuiteUpgradeLegacySchema:\n expected %s\n got %v, %v", "A1", operator, operatorErr)
+		}
+		if len(*networkOperators) != 2{
+			t.Errorf("Error in TestSuiteUpgradeLegacySchema:\n expected %s\n got %v", "A1 and Telekom", networkOperators)
+		}
+	})
+}
+
+func TestSuiteLookupRanksLikeIndex(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *sqlx.DB){
+
+		//Arrange
+		repo := NewMSISDNRepository(db)
+		addSuitePlan(t, repo)
+		// Same priority as the existing rules, more specific, and sorting after them
+		country := model.Country{CountryNumberFormat: "^38[9]7[0-9]{7}$", CountryCode: "389", CountryIdentifier: "xk", CountryCodeLength: 3}
+		operator := model.MobileOperator{CountryIdentifier: "mk", PrefixFormat: "^7[5][0-9]{6}$", OperatorID: 2, PrefixLength: 2, NumberType: model.NumberTypeMobile}
+		countryErr := repo.AddNewCountry(&country)
+		operatorErr := repo.AddNewMobileOperator(&operator)
+		if countryErr != nil || operatorErr != nil{
+			t.Fatalf("Error adding rules: %v, %v", countryErr, operatorErr)
+		}
+		index, indexErr := NewMSISDNRepositoryIndex(repo)
+		if indexErr != nil{
+			t.Fatalf("Error building the index: %s", indexErr)
+		}
+
+		//Act
+		dbCountry, dbCountryErr := repo.LookupCountryCode("38975123456", now)
+		indexCountry, indexCountryErr := index.LookupCountryCode("38975123456", now)
+		dbOperator, dbOperatorErr := repo.LookupMobileOperator("mk", "75123456", now)
+		indexOperator, indexOperatorErr := index.LookupMobileOperator("mk", "75123456", now)
+
+		//Assert
+		if dbCountryErr != nil || indexCountryErr != nil || dbCountry.CountryIdentifier != "xk" || indexCountry.CountryIdentifier != "xk"{
+			t.Errorf("Error in TestSuiteLookupRanksLikeIndex:\n expected %s\n got %v %v, %v %v", "xk from both", dbCountry, dbCountryErr, indexCountry, indexCountryErr)
+		}
+		if dbOperatorErr != nil || indexOperatorErr != nil || dbOperator.MNO != "Telekom" || indexOperator.MNO != "Telekom"{
+			t.Errorf("Error in TestSuiteLookupRanksLikeIndex:\n expected %s\n got %v %v, %v %v", "Telekom from both", dbOperator, dbOperatorErr, indexOperator, indexOperatorErr)
+		}
+	})
+}
+
+func TestSuiteRemoveOperatorEndsRule(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *sqlx.DB){
+
+		//Arrange
+		repo := NewMSISDNRepository(db)
+		addSuitePlan(t, repo)
+
+		//Act
+		removeErr := repo.RemoveOperator("^7[0-9]{7}$", now)
+		before, beforeErr := repo.LookupMobileOperator("mk", "77123456", now.Add(-time.Hour))
+		_, afterErr := repo.LookupMobileOperator("mk", "77123456", now)
+
+		//Assert
+		if removeErr != nil{
+			t.Fatalf("Error in TestSuiteRemoveOperatorEndsRule:\n expected %s\n got %s", "nil", removeErr)
+		}
+		if beforeErr != nil || before.MNO != "A1"{
+			t.Errorf("Error in TestSuiteRemoveOperatorEndsRule:\n expected %s\n got %v, %v", "A1 before the removal", before, beforeErr)
+		}
+		if _, ok := afterErr.(*errs.NoCarriersFoundError); !ok{
+			t.Errorf("Error in TestSuiteRemoveOperatorEndsRule:\n expected %s\n got %v", "NoCarriersFoundError", afterErr)
+		}
+	})
+}
+
+func TestSuiteUpdatePlan(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *sqlx.DB){
+
+		//Arrange
+		repo := NewMSISDNRepository(db)
+		addSuitePlan(t, repo)
+		operators, _ := repo.GetAllMobileOperators()
+		changed := (*operators)[0]
+		changed.OperatorID = 2
+		update := model.PlanUpdate{
+			AddedCountries: []model.Country{{CountryNumberFormat: "^48[0-9]{9}$", CountryCode: "48", CountryIdentifier: "pl", CountryCodeLength: 2, InternationalPrefix: "00", EffectiveFrom: &now}},
+			ChangedOperators: []model.MobileOperator{changed},
+			RemovedOperators: []int{(*operators)[1].ID},
+		}
+
+		//Act
+		err := repo.UpdatePlan(&update)
+		countries, _ := repo.GetAllCountries()
+		operators, _ = repo.GetAllMobileOperators()
+
+		//Assert
+		if err != nil{
+			t.Fatalf("Error in TestSuiteUpdatePlan:\n expected %s\n got %s", "nil", err)
+		}
+		if len(*countries) != 2 || (*countries)[1].EffectiveFrom == nil || !(*countries)[1].EffectiveFrom.Equal(now){
+			t.Errorf("Error in TestSuiteUpdatePlan:\n expected %s\n got %v", "pl added from "+now.String(), countries)
+		}
+		if len(*operators) != 1 || (*operators)[0].MNO != "Telekom"{
+			t.Errorf("Error in TestSuiteUpdatePlan:\n expected %s\n got %v", "a single range moved to Telekom", operators)
+		}
+	})
+}
+
+func TestSuiteRemoveNetworkOperatorInUse(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *sqlx.DB){
+
+		//Arrange
+		repo := NewMSISDNRepository(db)
+		addSuitePlan(t, repo)
+
+		//Act
+		err := repo.RemoveNetworkOperator(1)
+
+		//Assert
+		if err == nil{
+			t.Error("Error in TestSuiteRemoveNetworkOperatorInUse:\n expected an error removing an operator with ranges")
+		}
+	})
+}
+
+func TestSuiteCountryDetails(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *sqlx.DB){
+
+		//Arrange
+		repo := NewMSISDNRepository(db)
+		details := model.CountryDetails{CountryIdentifier: "at", Name: "Austria", NativeName: "Österreich", Alpha3: "AUT", Numeric: "040", TimeZones: "Europe/Vienna", Currency: "EUR"}
+		changed := details
+		changed.Region = "Western Europe"
+
+		//Act
+		saveErr := repo.SaveCountryDetails(&details)
+		replaceErr := repo.SaveCountryDetails(&changed)
+		got, getErr := repo.GetCountryDetails("at")
+		all, allErr := repo.GetAllCountryDetails()
+		_, missErr := repo.GetCountryDetails("mk")
+
+		//Assert
+		if saveErr != nil || replaceErr != nil || getErr != nil || allErr != nil{
+			t.Fatalf("Error in TestSuiteCountryDetails:\n expected %s\n got %v, %v, %v, %v", "nil", saveErr, replaceErr, getErr, allErr)
+		}
+		if *got != changed || len(*all) != 1{
+			t.Errorf("Error in TestSuiteCountryDetails:\n expected %+v\n got %+v, %d saved", changed, *got, len(*all))
+		}
+		if _, ok := missErr.(*errs.CountryNotFoundError); !ok{
+			t.Errorf("Error in TestSuiteCountryDetails:\n expected %s\n got %v", "CountryNotFoundError", missErr)
+		}
+	})
+}
+
+func TestSuiteUsers(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *sqlx.DB){
+
+		//Arrange
+		repo := NewAuthRepository(db)
+		id := "5b1f2a3c-0000-4000-8000-000000000001"
+
+		//Act
+		registerErr := repo.RegisterNativeUser(id, "user", "hash", "user", "token")
+		tokenErr := repo.UpdateRefreshToken(id, "newtoken")
+		editErr := repo.EditUserById(id, "renamed", "", "admin")
+		user, getErr := repo.GetUserByUsername("renamed")
+		removeErr := repo.RemoveUserById(id)
+		_, missErr := repo.GetUserById(id)
+
+		//Assert
+		if registerErr != nil || tokenErr != nil || editErr != nil || removeErr != nil{
+			t.Fatalf("Error in TestSuiteUsers:\n expected %s\n got %v, %v, %v, %v", "nil", registerErr, tokenErr, editErr, removeErr)
+		}
+		if getErr != nil || user.Role != "admin" || user.Password != "hash" || user.RefreshToken != "newtoken"{
+			t.Errorf("Error in TestSuiteUsers:\n expected %s\n got %v, %v", "the edited user", user, getErr)
+		}
+		if _, ok := missErr.(*errs.UserNotFoundError); !ok{
+			t.Errorf("Error in TestSuiteUsers:\n expected %s\n got %v", "UserNotFoundError", missErr)
+		}
+	})
+}
+
+func TestSuitePortedNumbers(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *sqlx.DB){
+
+		//Arrange
+		repo := NewPortingRepository(db)
+		batches := [][]model.PortedNumber{
+			{{MSISDN: "38977000001", MNO: "A1"}, {MSISDN: "38977000002", MNO: "A1"}},
+			{{MSISDN: "38977000001", MNO: "Telekom"}, {MSISDN: "38977000002", MNO: ""}},
+			{{MSISDN: "38977000003", MNO: "A1"}, {MSISDN: "38977000003", MNO: "Telekom"}},
+			{{MSISDN: "38977000003", MNO: ""}, {MSISDN: "38977000003", MNO: "A1"}},
+		}
+		// A batch with more numbers than fit the bind variables of a single SQLite statement,
+		// then the removal of all but the last of them
+		var large, removed []model.PortedNumber
+		for i := 0; i < 1200; i++{
+			msisdn := fmt.Sprintf("3897800%04d", i)
+			large = append(large, model.PortedNumber{MSISDN: msisdn, MNO: "A1"})
+			if i < 1199{
+				removed = append(removed, model.PortedNumber{MSISDN: msisdn})
+			}
+		}
+		batches = append(batches, large, removed)
+
+		//Act
+		var err error
+		for _, batch := range batches{
+			sent := false
+			err = repo.ApplyPortedNumbers(false, func() ([]model.PortedNumber, error){
+				if sent{
+					return nil, nil
+				}
+				sent = true
+				return batch, nil
+			})
+			if err != nil{
+				break
+			}
+		}
+		saved := map[string]string{}
+		getErr := repo.GetAllPortedNumbers(func(number model.PortedNumber) error {
+			saved[number.MSISDN] = number.MNO
+			return nil
+		})
+
+		//Assert
+		if err != nil || getErr != nil{
+			t.Fatalf("Error in TestSuitePortedNumbers:\n expected %s\n got %v, %v", "nil", err, getErr)
+		}
+		if len(saved) != 3 || saved["38977000001"] != "Telekom" || saved["38977000003"] != "A1" || saved["38978001199"] != "A1"{
+			t.Errorf("Error in TestSuitePortedNumbers:\n expected %s\n got %d numbers", "38977000001 ported to Telekom, 38977000003 and 38978001199 to A1", len(saved))
+		}
+	})
+}
+
+func TestSuiteJobs(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *sqlx.DB){
+
+		//Arrange
+		repo := NewJobRepository(db)
+		job := model.LookupJob{ID: "job-1", Owner: "owner-1", FileName: "numbers.csv", Status: model.JobQueued, Total: 10, CreatedAt: now, UpdatedAt: now}
+
+		//Act
+		createErr := repo.CreateJob(&job)
+		progressErr := repo.UpdateJobProgress(job.ID, 5, 1)
+		unfinished, unfinishedErr := repo.GetUnfinishedJobs()
+		statusErr := repo.UpdateJobStatus(job.ID, model.JobCompleted, "")
+		saved, getErr := repo.GetJobById(job.ID)
+		owned, ownedErr := repo.GetJobsByOwner("owner-1")
+
+		//Assert
+		if createErr != nil || progressErr != nil || unfinishedErr != nil || statusErr != nil || getErr != nil || ownedErr != nil{
+			t.Fatalf("Error in TestSuiteJobs:\n expected %s\n got %v, %v, %v, %v, %v, %v", "nil", createErr, progressErr, unfinishedErr, statusErr, getErr, ownedErr)
+		}
+		if len(*unfinished) != 1{
+			t.Errorf("Error in TestSuiteJobs:\n expected %s\n got %v", "1 unfinished job", unfinished)
+		}
+		if saved.Status != model.JobCompleted || saved.Processed != 5 || saved.Failed != 1 || !saved.CreatedAt.Equal(now){
+			t.Errorf("Error in TestSuiteJobs:\n expected %s\n got %v", "a completed job with 5 processed and 1 failed", saved)
+		}
+		if len(*owned) != 1{
+			t.Errorf("Error in TestSuiteJobs:\n expected %s\n got %v", "1 job", owned)
+		}
+	})
+}
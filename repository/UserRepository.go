@@ -55,7 +55,7 @@ func (db UserRepositoryDb) GetAllUsers() (*[]model.User, error){
 func (db UserRepositoryDb) GetUserByUsername(username string) (*model.User, error){
 	var user model.User
 	sqlFind := "SELECT id, username, password, role, refresh_token FROM users WHERE username = ?"
-	err := db.client.Get(&user, sqlFind, username)
+	err := db.client.Get(&user, db.client.Rebind(sqlFind), username)
 	if err != nil{
 		if err == sql.ErrNoRows{
 			return nil, errs.NewUserNotFoundError()
@@ -71,7 +71,7 @@ func (db UserRepositoryDb) GetUserByUsername(username string) (*model.User, erro
 func (db UserRepositoryDb) GetUserById(id string) (*model.User, error){
 	var user model.User
 	sqlFind := "SELECT id, username, password, role, refresh_token FROM users WHERE id = ?"
-	err := db.client.Get(&user, sqlFind, id)
+	err := db.client.Get(&user, db.client.Rebind(sqlFind), id)
 	if err != nil{
 		if err == sql.ErrNoRows{
 			return nil, errs.NewUserNotFoundError()
@@ -86,8 +86,8 @@ func (db UserRepositoryDb) GetUserById(id string) (*model.User, error){
 
 func (db UserRepositoryDb) RegisterNativeUser(uuid string, username string, password string, role string, refresh_token string) (error){
 	
-	sqlNewUser := "INSERT INTO users VALUES (?,?,?,?,?)"
-	_, execError := db.client.Exec(sqlNewUser, uuid, username, password, role, refresh_token)
+	sqlNewUser := "INSERT INTO users (id, username, password, role, refresh_token) VALUES (?,?,?,?,?)"
+	_, execError := db.client.Exec(db.client.Rebind(sqlNewUser), uuid, username, password, role, refresh_token)
 	if execError != nil{
 		return errs.NewUnexpectedError(execError.Error())
 	}
@@ -98,8 +98,8 @@ func (db UserRepositoryDb) RegisterNativeUser(uuid string, username string, pass
 
 func (db UserRepositoryDb) RegisterImportedUser(uuid string, username string, role string, refresh_token string)  error{
 
-	sqlNewUser := "INSERT INTO users VALUES (?,?,?,?,?)"
-	_, execError := db.client.Exec(sqlNewUser, uuid, username,"", role, refresh_token)
+	sqlNewUser := "INSERT INTO users (id, username, password, role, refresh_token) VALUES (?,?,?,?,?)"
+	_, execError := db.client.Exec(db.client.Rebind(sqlNewUser), uuid, username,"", role, refresh_token)
 	if execError != nil{
 		return errs.NewUnexpectedError(execError.Error())
 	}
@@ -109,7 +109,7 @@ func (db UserRepositoryDb) RegisterImportedUser(uuid string, username string, ro
 
 func (db UserRepositoryDb) UpdateRefreshToken(uuid string, refreshToken string) error{
 	sqlRefresh := "UPDATE users SET refresh_token = ? WHERE id = ?"
-	_, refreshErr := db.client.Exec(sqlRefresh, refreshToken, uuid)
+	_, refreshErr := db.client.Exec(db.client.Rebind(sqlRefresh), refreshToken, uuid)
 	if refreshErr != nil{
 		return errs.NewUnexpectedError(refreshErr.Error())
 	}
@@ -120,10 +120,10 @@ func (db UserRepositoryDb) EditUserById(uuid string, username string, password s
 	var err error
 	if password != ""{
 		sqlEdit := "UPDATE users SET username = ?, password = ?, role = ? WHERE id = ?"
-		_, err = db.client.Exec(sqlEdit,username, password, role, uuid)
+		_, err = db.client.Exec(db.client.Rebind(sqlEdit),username, password, role, uuid)
 	}else{
 		sqlEdit := "UPDATE users SET username = ?, role = ? WHERE id = ?"
-		_, err = db.client.Exec(sqlEdit,username, role, uuid)
+		_, err = db.client.Exec(db.client.Rebind(sqlEdit),username, role, uuid)
 	}
 	
 	if err != nil{
@@ -135,7 +135,7 @@ func (db UserRepositoryDb) EditUserById(uuid string, username string, password s
 func (db UserRepositoryDb) RemoveUserById(uuid string) (error) {
 
 	sqlRemove := "DELETE FROM users WHERE id = ?"
-	_, err := db.client.Exec(sqlRemove, uuid)
+	_, err := db.client.Exec(db.client.Rebind(sqlRemove), uuid)
 	if err != nil {
 		return err
 	}
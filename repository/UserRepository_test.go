@@ -21,6 +21,8 @@ func setup(t *testing.T) (sqlmock.Sqlmock){
 	sqlxDb = sqlx.NewDb(db,"sqlmock")
 	userRepo = NewAuthRepository(sqlxDb)
 	lookupRepo = NewMSISDNRepository(sqlxDb)
+	jobRepo = NewJobRepository(sqlxDb)
+	portingRepo = NewPortingRepository(sqlxDb)
 
 
 	return mock
@@ -0,0 +1,54 @@
+-- Upgrades the countries and mobile_operators tables of the first releases, which
+-- matched a single pattern per rule and named the operator of a range in mno, to
+-- rules with priorities, effective dates and network operators
+ALTER TABLE countries
+    DROP PRIMARY KEY,
+    ADD COLUMN id int NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    ADD COLUMN excluded_format varchar(60) NOT NULL DEFAULT '',
+    ADD COLUMN trunk_prefix varchar(4) NOT NULL DEFAULT '',
+    ADD COLUMN international_prefix varchar(20) NOT NULL DEFAULT '00',
+    ADD COLUMN nsn_min_length int NOT NULL DEFAULT 0,
+    ADD COLUMN nsn_max_length int NOT NULL DEFAULT 0,
+    ADD COLUMN number_grouping varchar(100) NOT NULL DEFAULT '',
+    ADD COLUMN priority int NOT NULL DEFAULT 0,
+    ADD COLUMN effective_from datetime NULL,
+    ADD COLUMN effective_to datetime NULL,
+    ADD KEY (country_number_format);
+
+CREATE TABLE IF NOT EXISTS network_operators (
+    id int NOT NULL AUTO_INCREMENT,
+    brand_name varchar(100) NOT NULL,
+    legal_name varchar(200) NOT NULL,
+    status varchar(20) NOT NULL DEFAULT 'active',
+    network_codes varchar(100) NOT NULL DEFAULT '',
+    host_operator_id int DEFAULT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY (brand_name),
+    FOREIGN KEY (host_operator_id) REFERENCES network_operators (id)
+);
+
+-- Every name in mno becomes a network operator, with the name as its legal name
+-- until it's edited on the admin page
+INSERT INTO network_operators (brand_name, legal_name)
+    SELECT DISTINCT mno, mno FROM mobile_operators
+    WHERE mno NOT IN (SELECT brand_name FROM network_operators);
+
+ALTER TABLE mobile_operators
+    DROP PRIMARY KEY,
+    ADD COLUMN id int NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    ADD COLUMN excluded_format varchar(60) NOT NULL DEFAULT '',
+    ADD COLUMN operator_id int NULL,
+    ADD COLUMN number_type varchar(20) NOT NULL DEFAULT 'mobile',
+    ADD COLUMN priority int NOT NULL DEFAULT 0,
+    ADD COLUMN effective_from datetime NULL,
+    ADD COLUMN effective_to datetime NULL,
+    ADD KEY (country_identifier, prefix_format);
+
+UPDATE mobile_operators
+    JOIN network_operators ON network_operators.brand_name = mobile_operators.mno
+    SET mobile_operators.operator_id = network_operators.id;
+
+ALTER TABLE mobile_operators
+    DROP COLUMN mno,
+    MODIFY operator_id int NOT NULL,
+    ADD FOREIGN KEY (operator_id) REFERENCES network_operators (id);
@@ -0,0 +1,80 @@
+-- Tables of the repositories for PostgreSQL
+CREATE TABLE IF NOT EXISTS countries (
+    id serial PRIMARY KEY,
+    country_number_format varchar(20) NOT NULL,
+    excluded_format varchar(60) NOT NULL DEFAULT '',
+    country_code varchar(6) NOT NULL,
+    country_identifier varchar(3) NOT NULL,
+    country_code_length int NOT NULL,
+    trunk_prefix varchar(4) NOT NULL DEFAULT '',
+    international_prefix varchar(20) NOT NULL DEFAULT '00',
+    nsn_min_length int NOT NULL DEFAULT 0,
+    nsn_max_length int NOT NULL DEFAULT 0,
+    number_grouping varchar(100) NOT NULL DEFAULT '',
+    priority int NOT NULL DEFAULT 0,
+    effective_from timestamp NULL,
+    effective_to timestamp NULL
+);
+CREATE INDEX IF NOT EXISTS countries_country_number_format ON countries (country_number_format);
+
+CREATE TABLE IF NOT EXISTS country_details (
+    country_identifier varchar(3) NOT NULL PRIMARY KEY,
+    name varchar(100) NOT NULL,
+    native_name varchar(100) NOT NULL DEFAULT '',
+    iso_alpha3 varchar(3) NOT NULL,
+    iso_numeric varchar(3) NOT NULL,
+    time_zones varchar(200) NOT NULL DEFAULT '',
+    currency varchar(3) NOT NULL DEFAULT '',
+    region varchar(50) NOT NULL DEFAULT '',
+    continent varchar(20) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS network_operators (
+    id serial PRIMARY KEY,
+    brand_name varchar(100) NOT NULL UNIQUE,
+    legal_name varchar(200) NOT NULL,
+    status varchar(20) NOT NULL DEFAULT 'active',
+    network_codes varchar(100) NOT NULL DEFAULT '',
+    host_operator_id int DEFAULT NULL REFERENCES network_operators (id)
+);
+
+CREATE TABLE IF NOT EXISTS mobile_operators (
+    id serial PRIMARY KEY,
+    country_identifier varchar(3) NOT NULL,
+    prefix_format varchar(60) NOT NULL,
+    excluded_format varchar(60) NOT NULL DEFAULT '',
+    operator_id int NOT NULL REFERENCES network_operators (id),
+    prefix_length int NOT NULL,
+    number_type varchar(20) NOT NULL DEFAULT 'mobile',
+    priority int NOT NULL DEFAULT 0,
+    effective_from timestamp NULL,
+    effective_to timestamp NULL
+);
+CREATE INDEX IF NOT EXISTS mobile_operators_prefix_format ON mobile_operators (country_identifier, prefix_format);
+
+CREATE TABLE IF NOT EXISTS ported_numbers (
+    msisdn varchar(15) PRIMARY KEY,
+    mno varchar(100) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    id varchar(36) PRIMARY KEY,
+    username varchar(100) NOT NULL,
+    password varchar(100),
+    role varchar(10) NOT NULL,
+    refresh_token varchar(512)
+);
+
+CREATE TABLE IF NOT EXISTS lookup_jobs (
+    id varchar(36) PRIMARY KEY,
+    owner varchar(36) NOT NULL,
+    file_name varchar(255) NOT NULL,
+    status varchar(10) NOT NULL,
+    total int NOT NULL,
+    processed int NOT NULL,
+    failed int NOT NULL,
+    error varchar(512) NOT NULL,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL
+);
+CREATE INDEX IF NOT EXISTS lookup_jobs_owner ON lookup_jobs (owner);
@@ -0,0 +1,67 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/robesmi/MSISDNApp/model/errs"
+	"github.com/robesmi/MSISDNApp/utils"
+	"github.com/robesmi/MSISDNApp/vault"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authorizeUnary checks the access token of every unary call, see authorize
+func authorizeUnary(vault vault.VaultInterface) grpc.UnaryServerInterceptor{
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error){
+		if err := authorize(ctx, vault); err != nil{
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// authorizeStream checks the access token of every stream when it's opened, see authorize
+func authorizeStream(vault vault.VaultInterface) grpc.StreamServerInterceptor{
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error{
+		if err := authorize(stream.Context(), vault); err != nil{
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
+
+// authorize accepts the same tokens ValidateApiTokenUserSection does: an access token of
+// a user sent as "Bearer <token>" in the authorization metadata
+func authorize(ctx context.Context, vault vault.VaultInterface) error{
+
+	var access_token string
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization"){
+		fields := strings.Fields(value)
+		if len(fields) == 2 && fields[0] == "Bearer"{
+			access_token = fields[1]
+			break
+		}
+	}
+	if access_token == ""{
+		return status.Error(codes.Unauthenticated, "No auth token")
+	}
+
+	claims, err := utils.ValidateAccessToken(vault, access_token)
+	if err != nil{
+		if _, ok := err.(*errs.ExpiredTokenError); ok{
+			return status.Error(codes.Unauthenticated, "Authorization token expired")
+		}
+		if _, ok := err.(*errs.MalformedTokenError); ok{
+			return status.Error(codes.Unauthenticated, "Malformed auth token")
+		}
+		return status.Error(codes.Internal, "Internal error")
+	}
+	if claims["role"] != "user"{
+		return status.Error(codes.PermissionDenied, "Unauthorized")
+	}
+	return nil
+}
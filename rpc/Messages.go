@@ -0,0 +1,51 @@
+package rpc
+
+import (
+	"strings"
+
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/dto"
+	"google.golang.org/protobuf/proto"
+)
+
+// Message returns the message the data of an HTTP response is sent as in Protobuf, so
+// lookups and the numbering plan have the same messages as over gRPC. It reports false
+// for data without a message of its own
+func Message(data interface{}) (proto.Message, bool){
+
+	switch d := data.(type) {
+	case *dto.NumberLookupResponse:
+		return LookupMessage(strings.TrimPrefix(d.Formats.E164, "+"), d), true
+	case dto.BatchLookupResponse:
+		message := &BatchLookupResponse{}
+		for _, item := range d.Results{
+			result := &LookupResult{Number: item.Number, Error: item.Error}
+			if item.Result != nil{
+				result.Result = LookupMessage(strings.TrimPrefix(item.Result.Formats.E164, "+"), item.Result)
+			}
+			message.Results = append(message.Results, result)
+		}
+		return message, true
+	case *dto.CountryDetails:
+		return CountryDetailsMessage(*d), true
+	case *[]dto.CountryDetails:
+		message := &ListCountryDetailsResponse{}
+		for _, details := range *d{
+			message.Countries = append(message.Countries, CountryDetailsMessage(details))
+		}
+		return message, true
+	case *[]model.Country:
+		message := &ListCountriesResponse{}
+		for _, country := range *d{
+			message.Countries = append(message.Countries, CountryMessage(country))
+		}
+		return message, true
+	case *[]model.MobileOperator:
+		message := &ListOperatorsResponse{}
+		for _, operator := range *d{
+			message.Operators = append(message.Operators, OperatorMessage(operator))
+		}
+		return message, true
+	}
+	return nil, false
+}
@@ -0,0 +1,269 @@
+package rpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative msisdn.proto
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/dto"
+	"github.com/robesmi/MSISDNApp/model/errs"
+	"github.com/robesmi/MSISDNApp/normalize"
+	"github.com/robesmi/MSISDNApp/service"
+	"github.com/robesmi/MSISDNApp/vault"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server answers the LookupService and PlanService RPCs with the MSISDNService
+type Server struct {
+	UnimplementedLookupServiceServer
+	UnimplementedPlanServiceServer
+	Service service.MSISDNService
+	Logger zerolog.Logger
+}
+
+// NewServer returns a gRPC server with both services registered, which only accepts
+// calls carrying an access token of a user, the same as /service/api
+func NewServer(msservice service.MSISDNService, vault vault.VaultInterface, logger zerolog.Logger) *grpc.Server{
+
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(authorizeUnary(vault)),
+		grpc.StreamInterceptor(authorizeStream(vault)),
+	)
+	handler := &Server{Service: msservice, Logger: logger}
+	RegisterLookupServiceServer(server, handler)
+	RegisterPlanServiceServer(server, handler)
+	return server
+}
+
+// Lookup looks up a single number, failing with InvalidArgument when it can't be read
+// and NotFound when it isn't in the numbering plan
+func (s *Server) Lookup(ctx context.Context, req *LookupRequest) (*LookupResponse, error){
+
+	response, err := s.lookup(req)
+	if err != nil{
+		return nil, s.lookupStatus(err, "Lookup")
+	}
+	return response, nil
+}
+
+// LookupStream answers every number on the stream with its result, or the reason it
+// couldn't be looked up, until the client closes its side
+func (s *Server) LookupStream(stream LookupService_LookupStreamServer) error{
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF{
+			return nil
+		}
+		if err != nil{
+			return err
+		}
+		result := &LookupResult{Number: req.Number}
+		response, lookupErr := s.lookup(req)
+		if lookupErr != nil{
+			if status.Code(s.lookupStatus(lookupErr, "LookupStream")) == codes.Internal{
+				return status.Error(codes.Internal, lookupErr.Error())
+			}
+			result.Error = lookupErr.Error()
+		}else{
+			result.Result = response
+		}
+		if err := stream.Send(result); err != nil{
+			return err
+		}
+	}
+}
+
+// ListCountries lists every country rule of the numbering plan
+func (s *Server) ListCountries(ctx context.Context, req *ListCountriesRequest) (*ListCountriesResponse, error){
+
+	countries, err := s.Service.GetAllCountries()
+	if err != nil{
+		s.Logger.Error().Err(err).Str("package","rpc").Str("context","ListCountries").Msg("Error listing countries")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	response := &ListCountriesResponse{}
+	for _, country := range *countries{
+		response.Countries = append(response.Countries, CountryMessage(country))
+	}
+	return response, nil
+}
+
+// ListOperators lists every range of the numbering plan, or those of a single country
+func (s *Server) ListOperators(ctx context.Context, req *ListOperatorsRequest) (*ListOperatorsResponse, error){
+
+	operators, err := s.Service.GetAllMobileOperators()
+	if err != nil{
+		s.Logger.Error().Err(err).Str("package","rpc").Str("context","ListOperators").Msg("Error listing operators")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	response := &ListOperatorsResponse{}
+	for _, operator := range *operators{
+		if req.CountryIdentifier != "" && !strings.EqualFold(operator.CountryIdentifier, req.CountryIdentifier){
+			continue
+		}
+		response.Operators = append(response.Operators, OperatorMessage(operator))
+	}
+	return response, nil
+}
+
+// lookup normalizes the number of the request and looks it up with the numbering plan
+// in effect at as_of, or the current one
+func (s *Server) lookup(req *LookupRequest) (*LookupResponse, error){
+
+	var region *normalize.Region
+	if req.Region != ""{
+		var err error
+		if region, err = s.Service.GetRegion(req.Region); err != nil{
+			return nil, err
+		}
+	}
+	normalized, err := normalize.Normalize(req.Number, region)
+	if err != nil{
+		return nil, err
+	}
+
+	var response *dto.NumberLookupResponse
+	if req.AsOf != nil{
+		if err := req.AsOf.CheckValid(); err != nil{
+			return nil, errs.NewInvalidNumberError("as_of is not a valid time")
+		}
+		response, err = s.Service.LookupMSISDNAt(normalized.Number, req.AsOf.AsTime())
+	}else{
+		response, err = s.Service.LookupMSISDN(normalized.Number)
+	}
+	if err != nil{
+		return nil, err
+	}
+	response.Normalization = normalized.Steps
+	return LookupMessage(normalized.Number, response), nil
+}
+
+// lookupStatus turns an error of a lookup into the status the client gets, logging
+// the ones that aren't the client's fault
+func (s *Server) lookupStatus(err error, context string) error{
+
+	switch err.(type) {
+	case *errs.InvalidNumberError, *errs.CountryNotFoundError:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case *errs.NumberNotFoundError, *errs.NoCarriersFoundError:
+		return status.Error(codes.NotFound, err.Error())
+	}
+	s.Logger.Error().Err(err).Str("package","rpc").Str("context",context).Msg("Error making lookup")
+	return status.Error(codes.Internal, err.Error())
+}
+
+// LookupMessage converts the lookup of the normalized number
+func LookupMessage(msisdn string, response *dto.NumberLookupResponse) *LookupResponse{
+
+	message := &LookupResponse{
+		Msisdn: msisdn,
+		Mno: response.MNO,
+		OperatorId: int32(response.OperatorID),
+		HostNetwork: response.HostNetwork,
+		CountryCode: response.CC,
+		SubscriberNumber: response.SN,
+		CountryIdentifier: response.CI,
+		NumberType: response.Type,
+		Ported: response.Ported,
+		RangeHolder: response.RangeHolder,
+		Formats: &NumberFormats{
+			E164: response.Formats.E164,
+			International: response.Formats.International,
+			National: response.Formats.National,
+			Rfc3966: response.Formats.RFC3966,
+		},
+		NormalizationSteps: response.Normalization,
+		AsOf: timestamp(response.AsOf),
+	}
+	for _, code := range response.NetworkCodes{
+		message.NetworkCodes = append(message.NetworkCodes, &NetworkCode{Mcc: code.MCC, Mnc: code.MNC})
+	}
+	if response.Country != nil{
+		message.Country = CountryDetailsMessage(*response.Country)
+	}
+	return message
+}
+
+// CountryDetailsMessage converts the details of a country
+func CountryDetailsMessage(details dto.CountryDetails) *CountryDetails{
+	return &CountryDetails{
+		Name: details.Name,
+		NativeName: details.NativeName,
+		IsoAlpha2: details.Alpha2,
+		IsoAlpha3: details.Alpha3,
+		IsoNumeric: details.Numeric,
+		TimeZones: details.TimeZones,
+		Currency: details.Currency,
+		Region: details.Region,
+		Continent: details.Continent,
+	}
+}
+
+// CountryMessage converts a country rule of the numbering plan
+func CountryMessage(country model.Country) *Country{
+	return &Country{
+		CountryIdentifier: country.CountryIdentifier,
+		CountryCode: country.CountryCode,
+		Pattern: country.CountryNumberFormat,
+		ExcludedPattern: country.ExcludedFormat,
+		CountryCodeLength: int32(country.CountryCodeLength),
+		TrunkPrefix: country.TrunkPrefix,
+		InternationalPrefix: country.InternationalPrefix,
+		NsnMinLength: int32(country.NSNMinLength),
+		NsnMaxLength: int32(country.NSNMaxLength),
+		NumberGrouping: country.NumberGrouping,
+		Priority: int32(country.Priority),
+		EffectiveFrom: timestamp(country.EffectiveFrom),
+		EffectiveTo: timestamp(country.EffectiveTo),
+	}
+}
+
+// OperatorMessage converts a range of the numbering plan
+func OperatorMessage(operator model.MobileOperator) *Operator{
+	return &Operator{
+		Id: int32(operator.ID),
+		CountryIdentifier: operator.CountryIdentifier,
+		Pattern: operator.PrefixFormat,
+		ExcludedPattern: operator.ExcludedFormat,
+		OperatorId: int32(operator.OperatorID),
+		Mno: operator.MNO,
+		NetworkCodes: networkCodes(operator.NetworkCodes),
+		HostNetwork: operator.HostNetwork,
+		PrefixLength: int32(operator.PrefixLength),
+		NumberType: operator.NumberType,
+		Priority: int32(operator.Priority),
+		EffectiveFrom: timestamp(operator.EffectiveFrom),
+		EffectiveTo: timestamp(operator.EffectiveTo),
+	}
+}
+
+// networkCodes parses the MCC-MNC pairs of a range, written as "294-01,294-02"
+func networkCodes(codes string) []*NetworkCode{
+
+	var parsed []*NetworkCode
+	for _, code := range strings.Split(codes, ","){
+		mcc, mnc, ok := strings.Cut(strings.TrimSpace(code), "-")
+		if ok{
+			parsed = append(parsed, &NetworkCode{Mcc: mcc, Mnc: mnc})
+		}
+	}
+	return parsed
+}
+
+// timestamp returns the time as a message, or nil when it's unbounded
+func timestamp(t *time.Time) *timestamppb.Timestamp{
+
+	if t == nil{
+		return nil
+	}
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,221 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/robesmi/MSISDNApp/mocks/service"
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/dto"
+	"github.com/robesmi/MSISDNApp/model/errs"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var mockLookupService *service.MockMSISDNService
+var server *Server
+
+func setup(t *testing.T) func(){
+
+	ctrl := gomock.NewController(t)
+	mockLookupService = service.NewMockMSISDNService(ctrl)
+	server = &Server{Service: mockLookupService, Logger: zerolog.Nop()}
+
+	return func(){
+		server = nil
+		defer ctrl.Finish()
+	}
+}
+
+func TestLookup(t *testing.T) {
+
+	tt := []struct{
+		Name string
+		Number string
+		CallsService bool
+		ServiceError error
+		ExpectedCode codes.Code
+	}{
+		{
+			Name:			"Valid number",
+			Number:			"+389 77 123 456",
+			CallsService:	true,
+			ExpectedCode:	codes.OK,
+		},
+		{
+			Name:			"Invalid number",
+			Number:			"lorem ipsum",
+			ExpectedCode:	codes.InvalidArgument,
+		},
+		{
+			Name:			"Number not found",
+			Number:			"123456789",
+			CallsService:	true,
+			ServiceError:	errs.NewNumberNotFoundError(),
+			ExpectedCode:	codes.NotFound,
+		},
+		{
+			Name:			"Service failure",
+			Number:			"38977123456",
+			CallsService:	true,
+			ServiceError:	errs.NewUnexpectedError("connection refused"),
+			ExpectedCode:	codes.Internal,
+		},
+	}
+
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Arrange
+			teardown := setup(t)
+			defer teardown()
+			if test.CallsService{
+				if test.ServiceError != nil{
+					mockLookupService.EXPECT().LookupMSISDN(gomock.Any()).Return(nil, test.ServiceError)
+				}else{
+					mockLookupService.EXPECT().LookupMSISDN("38977123456").Return(&dto.NumberLookupResponse{
+						MNO: "A1",
+						CC: "389",
+						SN: "123456",
+						CI: "mk",
+						NetworkCodes: []dto.NetworkCode{{MCC: "294", MNC: "01"}},
+					}, nil)
+				}
+			}
+
+			//Act
+			response, err := server.Lookup(context.Background(), &LookupRequest{Number: test.Number})
+
+			//Assert
+			if status.Code(err) != test.ExpectedCode{
+				t.Errorf("Error in TestLookup:\n expected %s\n got %v", test.ExpectedCode, err)
+			}
+			if test.ExpectedCode == codes.OK && (response.Msisdn != "38977123456" || response.Mno != "A1" || len(response.NetworkCodes) != 1 || len(response.NormalizationSteps) == 0){
+				t.Errorf("Error in TestLookup:\n got %v", response)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
+
+// lookupStream plays the client side of LookupStream, sending the requests and keeping the results
+type lookupStream struct {
+	grpc.ServerStream
+	requests []*LookupRequest
+	results []*LookupResult
+}
+
+func (s *lookupStream) Recv() (*LookupRequest, error){
+
+	if len(s.requests) == 0{
+		return nil, io.EOF
+	}
+	req := s.requests[0]
+	s.requests = s.requests[1:]
+	return req, nil
+}
+
+func (s *lookupStream) Send(result *LookupResult) error{
+	s.results = append(s.results, result)
+	return nil
+}
+
+func TestLookupStream(t *testing.T) {
+
+	//Arrange
+	teardown := setup(t)
+	defer teardown()
+	mockLookupService.EXPECT().LookupMSISDN("38977123456").Return(&dto.NumberLookupResponse{MNO: "A1", CC: "389", CI: "mk"}, nil)
+	mockLookupService.EXPECT().LookupMSISDN("123456789").Return(nil, errs.NewNumberNotFoundError())
+	stream := &lookupStream{requests: []*LookupRequest{
+		{Number: "38977123456"},
+		{Number: "lorem ipsum"},
+		{Number: "123456789"},
+	}}
+
+	//Act
+	err := server.LookupStream(stream)
+
+	//Assert
+	if err != nil{
+		t.Fatalf("Error in TestLookupStream:\n expected no error\n got %v", err)
+	}
+	if len(stream.results) != 3{
+		t.Fatalf("Error in TestLookupStream:\n expected 3 results\n got %v", stream.results)
+	}
+	if stream.results[0].Result == nil || stream.results[0].Result.Mno != "A1"{
+		t.Errorf("Error in TestLookupStream:\n expected a result for the first number\n got %v", stream.results[0])
+	}
+	for _, result := range stream.results[1:]{
+		if result.Result != nil || result.Error == ""{
+			t.Errorf("Error in TestLookupStream:\n expected an error for %s\n got %v", result.Number, result)
+		}
+	}
+}
+
+func TestListOperators(t *testing.T) {
+
+	//Arrange
+	teardown := setup(t)
+	defer teardown()
+	mockLookupService.EXPECT().GetAllMobileOperators().Return(&[]model.MobileOperator{
+		{ID: 1, CountryIdentifier: "mk", PrefixFormat: "^7[0-2]", MNO: "A1", NetworkCodes: "294-01"},
+		{ID: 2, CountryIdentifier: "at", PrefixFormat: "^664", MNO: "A1", NetworkCodes: "232-01,232-09"},
+	}, nil)
+
+	//Act
+	response, err := server.ListOperators(context.Background(), &ListOperatorsRequest{CountryIdentifier: "AT"})
+
+	//Assert
+	if err != nil{
+		t.Fatalf("Error in TestListOperators:\n expected no error\n got %v", err)
+	}
+	if len(response.Operators) != 1 || response.Operators[0].Id != 2 || len(response.Operators[0].NetworkCodes) != 2{
+		t.Errorf("Error in TestListOperators:\n expected the range of at\n got %v", response.Operators)
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+
+	tt := []struct{
+		Name string
+		Metadata metadata.MD
+	}{
+		{
+			Name:		"No metadata",
+		},
+		{
+			Name:		"No token",
+			Metadata:	metadata.Pairs("authorization", "Bearer"),
+		},
+		{
+			Name:		"Not a bearer token",
+			Metadata:	metadata.Pairs("authorization", "Basic dXNlcjpwYXNz"),
+		},
+	}
+
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Arrange
+			ctx := context.Background()
+			if test.Metadata != nil{
+				ctx = metadata.NewIncomingContext(ctx, test.Metadata)
+			}
+
+			//Act
+			err := authorize(ctx, nil)
+
+			//Assert
+			if status.Code(err) != codes.Unauthenticated{
+				t.Errorf("Error in TestAuthorize:\n expected %s\n got %v", codes.Unauthenticated, err)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
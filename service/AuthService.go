@@ -71,7 +71,7 @@ func (s DefaultAuthService) RegisterNativeUser(username string, password string,
 			return nil, encErr
 		}
 	
-		accessToken , atErr := createAccessToken(role, s.Vault)
+		accessToken , atErr := createAccessToken(newID, role, s.Vault)
 		if atErr != nil{
 			return nil, atErr
 		}
@@ -130,7 +130,7 @@ func (s DefaultAuthService) LoginNativeUser(username string, password string) (*
 	}
 
 	// Create new tokens and update the refresh token in db
-	accessToken , atErr := createAccessToken(user.Role, s.Vault)
+	accessToken , atErr := createAccessToken(user.UUID, user.Role, s.Vault)
 	if atErr != nil{
 		return nil, atErr
 	}
@@ -172,7 +172,7 @@ func (s DefaultAuthService)RegisterImportedUser(username string) (*dto.LoginResp
 		
 		newID := uuid.NewString()
 		
-		accessToken , atErr := createAccessToken("user", s.Vault)
+		accessToken , atErr := createAccessToken(newID, "user", s.Vault)
 		if atErr != nil{
 			return nil, atErr
 		}
@@ -220,7 +220,7 @@ func (s DefaultAuthService)LoginImportedUser(username string) (*dto.LoginRespons
 	}
 
 	// Create new tokens and update the refresh token in db
-	accessToken , atErr := createAccessToken(user.Role, s.Vault)
+	accessToken , atErr := createAccessToken(user.UUID, user.Role, s.Vault)
 	if atErr != nil{
 		return nil, atErr
 	}
@@ -250,7 +250,7 @@ func (s DefaultAuthService)RefreshTokens(id string, token string) (*dto.LoginRes
 	if user.RefreshToken != token{
 		return nil, errs.NewRefreshTokenMismatch()
 	}
-	accessToken , atErr := createAccessToken(user.Role, s.Vault)
+	accessToken , atErr := createAccessToken(user.UUID, user.Role, s.Vault)
 	if atErr != nil{
 		return nil, atErr
 	}
@@ -25,7 +25,7 @@ func TestRegisterNativeUserValidInput(t *testing.T) {
 		AccessToken: "test1",
 		RefreshToken: "test2",
 	}
-	createAccessToken = func(role string, vault vault.VaultInterface) (string,error) {
+	createAccessToken = func(userid string, role string, vault vault.VaultInterface) (string,error) {
 		return expResponse.AccessToken, nil
 	}
 	createRefreshToken = func(userid string, vault vault.VaultInterface) (string, error) {
@@ -120,7 +120,7 @@ func TestLoginNativeUserCorrectInput(t *testing.T) {
 		AccessToken: "test1",
 		RefreshToken: "test2",
 	}
-	createAccessToken = func(role string, vault vault.VaultInterface) (string,error) {
+	createAccessToken = func(userid string, role string, vault vault.VaultInterface) (string,error) {
 		return expResponse.AccessToken, nil
 	}
 	createRefreshToken = func(userid string, vault vault.VaultInterface) (string, error) {
@@ -170,7 +170,7 @@ func TestRegisterImportedUserValidInput(t *testing.T) {
 		AccessToken: "test1",
 		RefreshToken: "test2",
 	}
-	createAccessToken = func(role string, vault vault.VaultInterface) (string,error) {
+	createAccessToken = func(userid string, role string, vault vault.VaultInterface) (string,error) {
 		return expResponse.AccessToken, nil
 	}
 	createRefreshToken = func(userid string, vault vault.VaultInterface) (string, error) {
@@ -246,7 +246,7 @@ func TestLoginImportedUserValidInput(t *testing.T) {
 		AccessToken: "test1",
 		RefreshToken: "test2",
 	}
-	createAccessToken = func(role string, vault vault.VaultInterface) (string,error) {
+	createAccessToken = func(userid string, role string, vault vault.VaultInterface) (string,error) {
 		return expResponse.AccessToken, nil
 	}
 	createRefreshToken = func(userid string, vault vault.VaultInterface) (string, error) {
@@ -293,7 +293,7 @@ func TestRefreshTokensValid(t *testing.T) {
 		AccessToken: "test1",
 		RefreshToken: "test2",
 	}
-	createAccessToken = func(role string, vault vault.VaultInterface) (string,error) {
+	createAccessToken = func(userid string, role string, vault vault.VaultInterface) (string,error) {
 		return expResponse.AccessToken, nil
 	}
 	createRefreshToken = func(userid string, vault vault.VaultInterface) (string, error) {
@@ -335,7 +335,7 @@ func TestRefreshTokensInvalid(t *testing.T) {
 		AccessToken: "test1",
 		RefreshToken: "test2",
 	}
-	createAccessToken = func(role string, vault vault.VaultInterface) (string,error) {
+	createAccessToken = func(userid string, role string, vault vault.VaultInterface) (string,error) {
 		return expResponse.AccessToken, nil
 	}
 	createRefreshToken = func(userid string, vault vault.VaultInterface) (string, error) {
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/dto"
+	"github.com/robesmi/MSISDNApp/model/errs"
+)
+
+var (
+	alpha2Code = regexp.MustCompile("^[a-z]{2}$")
+	alpha3Code = regexp.MustCompile("^[A-Z]{3}$")
+	numericCode = regexp.MustCompile("^[0-9]{3}$")
+)
+
+// GetCountryDetails returns the details of the country with the ISO 3166-1 alpha-2,
+// alpha-3 or numeric code, in any case
+func (s DefaultMSISDNService) GetCountryDetails(code string) (*dto.CountryDetails, error){
+
+	code = strings.TrimSpace(code)
+	if alpha2 := strings.ToLower(code); alpha2Code.MatchString(alpha2){
+		details, err := s.repo.GetCountryDetails(alpha2)
+		if err != nil{
+			return nil, err
+		}
+		response := countryDetailsDto(details)
+		return &response, nil
+	}
+
+	all, err := s.repo.GetAllCountryDetails()
+	if err != nil{
+		return nil, err
+	}
+	for _, details := range *all{
+		if strings.EqualFold(details.Alpha3, code) || details.Numeric == code{
+			response := countryDetailsDto(&details)
+			return &response, nil
+		}
+	}
+	return nil, errs.NewCountryNotFoundError()
+}
+
+func (s DefaultMSISDNService) GetAllCountryDetails() (*[]dto.CountryDetails, error){
+
+	all, err := s.repo.GetAllCountryDetails()
+	if err != nil{
+		return nil, err
+	}
+	response := make([]dto.CountryDetails, 0, len(*all))
+	for _, details := range *all{
+		response = append(response, countryDetailsDto(&details))
+	}
+	return &response, nil
+}
+
+// SaveCountryDetails adds the details of a country or replaces the ones it has. The
+// ISO codes are required, the rest of the details are optional
+func (s DefaultMSISDNService) SaveCountryDetails(req *dto.CountryDetailsRequest) (error){
+
+	details := model.CountryDetails{
+		CountryIdentifier: strings.ToLower(strings.TrimSpace(req.CountryIdentifier)),
+		Name: strings.TrimSpace(req.Name),
+		NativeName: strings.TrimSpace(req.NativeName),
+		Alpha3: strings.ToUpper(strings.TrimSpace(req.Alpha3)),
+		Numeric: strings.TrimSpace(req.Numeric),
+		Currency: strings.ToUpper(strings.TrimSpace(req.Currency)),
+		Region: strings.TrimSpace(req.Region),
+		Continent: strings.TrimSpace(req.Continent),
+	}
+	var zones []string
+	for _, zone := range strings.Split(req.TimeZones, ","){
+		if zone = strings.TrimSpace(zone); zone != ""{
+			zones = append(zones, zone)
+		}
+	}
+	details.TimeZones = strings.Join(zones, ",")
+
+	switch {
+	case !alpha2Code.MatchString(details.CountryIdentifier):
+		return errs.NewInvalidCountryDetailsError("The country identifier must be an ISO 3166-1 alpha-2 code")
+	case !alpha3Code.MatchString(details.Alpha3):
+		return errs.NewInvalidCountryDetailsError("The alpha-3 code must be three letters")
+	case !numericCode.MatchString(details.Numeric):
+		return errs.NewInvalidCountryDetailsError("The numeric code must be three digits")
+	case details.Currency != "" && !alpha3Code.MatchString(details.Currency):
+		return errs.NewInvalidCountryDetailsError("The currency must be an ISO 4217 code")
+	case details.Name == "":
+		return errs.NewInvalidCountryDetailsError("The country needs a name")
+	}
+
+	if err := s.repo.SaveCountryDetails(&details); err != nil{
+		return err
+	}
+	s.cache.Invalidate()
+	return nil
+}
+
+// lookupCountryDetails returns the details of the country a number belongs to, or nil
+// for countries without any
+func (s DefaultMSISDNService) lookupCountryDetails(ci string) (*dto.CountryDetails, error){
+
+	details, err := s.repo.GetCountryDetails(ci)
+	if err != nil{
+		if _, ok := err.(*errs.CountryNotFoundError); ok{
+			return nil, nil
+		}
+		return nil, err
+	}
+	response := countryDetailsDto(details)
+	return &response, nil
+}
+
+func countryDetailsDto(details *model.CountryDetails) dto.CountryDetails{
+
+	response := dto.CountryDetails{
+		Name: details.Name,
+		NativeName: details.NativeName,
+		Alpha2: strings.ToUpper(details.CountryIdentifier),
+		Alpha3: details.Alpha3,
+		Numeric: details.Numeric,
+		Currency: details.Currency,
+		Region: details.Region,
+		Continent: details.Continent,
+	}
+	if details.TimeZones != ""{
+		response.TimeZones = strings.Split(details.TimeZones, ",")
+	}
+	return response
+}
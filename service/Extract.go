@@ -0,0 +1,48 @@
+package service
+
+import (
+	"github.com/robesmi/MSISDNApp/model/dto"
+	"github.com/robesmi/MSISDNApp/model/errs"
+	"github.com/robesmi/MSISDNApp/normalize"
+)
+
+// ExtractMSISDNs finds the phone numbers written in the text and looks each of them up,
+// reading numbers in national format as numbers of the region when one is given.
+// Stretches of text that can't be normalized into a MSISDN are left out, while numbers
+// that aren't found are returned with the reason
+func (s DefaultMSISDNService) ExtractMSISDNs(text string, regionHint string) (*dto.ExtractionResponse, error){
+
+	var region *normalize.Region
+	if regionHint != ""{
+		var err error
+		if region, err = s.GetRegion(regionHint); err != nil{
+			return nil, err
+		}
+	}
+
+	response := dto.ExtractionResponse{Numbers: []dto.ExtractedNumber{}}
+	for _, candidate := range normalize.FindCandidates(text){
+		normalized, err := normalize.Normalize(candidate.Text, region)
+		if err != nil{
+			continue
+		}
+		found := dto.ExtractedNumber{
+			Text: candidate.Text,
+			Start: candidate.Start,
+			End: candidate.End,
+			Number: normalized.Number,
+			Normalization: normalized.Steps,
+		}
+		result, err := s.LookupMSISDN(normalized.Number)
+		switch err.(type) {
+		case nil:
+			found.Result = result
+		case *errs.NumberNotFoundError, *errs.NoCarriersFoundError:
+			found.Error = err.Error()
+		default:
+			return nil, err
+		}
+		response.Numbers = append(response.Numbers, found)
+	}
+	return &response, nil
+}
@@ -0,0 +1,497 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/errs"
+	"github.com/robesmi/MSISDNApp/normalize"
+	"github.com/robesmi/MSISDNApp/repository"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// jobRunners is how many jobs are processed at the same time
+	jobRunners = 2
+	// jobLookupWorkers is how many lookups of a single job run at the same time
+	jobLookupWorkers = 8
+	// jobChunkSize is how many numbers are processed between progress checkpoints
+	jobChunkSize = 500
+	// maxJobFileSize is the largest accepted upload in bytes
+	maxJobFileSize = 64 << 20
+)
+
+var jobResultHeader = []string{"input", "msisdn", "mno", "country_code", "subscriber_number", "country_identifier", "error"}
+
+// DefaultJobService runs bulk lookup jobs in the background. The uploaded files and
+// the results are kept in a directory, while the job state is saved in the repository
+// after every chunk so that jobs interrupted by a restart continue where they stopped
+type DefaultJobService struct {
+	repo repository.JobRepository
+	lookup MSISDNService
+	dir string
+	logger zerolog.Logger
+
+	mu sync.Mutex
+	wake *sync.Cond
+	queue []string
+	cancels map[string]context.CancelFunc
+}
+
+func NewJobService(repo repository.JobRepository, lookup MSISDNService, dir string, logger zerolog.Logger) *DefaultJobService{
+	s := &DefaultJobService{
+		repo: repo,
+		lookup: lookup,
+		dir: dir,
+		logger: logger,
+		cancels: make(map[string]context.CancelFunc),
+	}
+	s.wake = sync.NewCond(&s.mu)
+	return s
+}
+
+//go:generate mockgen -destination=../mocks/service/mockJobService.go -package=service github.com/robesmi/MSISDNApp/service JobService
+type JobService interface {
+	// CreateJob takes an owner uuid, a file name and a CSV or plain text file with a number
+	// in the first column of each row, saves the file and queues a job for it
+	CreateJob(string, string, io.Reader) (*model.LookupJob, error)
+	// GetJob takes an owner uuid and a job id and returns the job, or a JobNotFoundError
+	// if the job doesn't exist or belongs to someone else
+	GetJob(string, string) (*model.LookupJob, error)
+	// GetJobsByOwner returns all jobs of the user, newest first
+	GetJobsByOwner(string) (*[]model.LookupJob, error)
+	// CancelJob takes an owner uuid and a job id and stops the job if it hasn't finished
+	CancelJob(string, string) (error)
+	// OpenJobResult takes an owner uuid and a job id and returns the enriched CSV of a completed job
+	OpenJobResult(string, string) (io.ReadCloser, error)
+}
+
+// Start queues the jobs left unfinished by a previous run and starts processing the queue
+func (s *DefaultJobService) Start() (error){
+
+	if err := os.MkdirAll(s.dir, 0o750); err != nil{
+		return err
+	}
+	jobs, err := s.repo.GetUnfinishedJobs()
+	if err != nil{
+		return err
+	}
+	for _, job := range *jobs{
+		s.enqueue(job.ID)
+	}
+	for i := 0; i < jobRunners; i++ {
+		go s.runner()
+	}
+	return nil
+}
+
+func (s *DefaultJobService) CreateJob(owner string, fileName string, file io.Reader) (*model.LookupJob, error){
+
+	id := uuid.NewString()
+	inputPath := s.inputPath(id)
+
+	dst, err := os.Create(inputPath)
+	if err != nil{
+		return nil, errs.NewUnexpectedError(err.Error())
+	}
+	written, copyErr := io.Copy(dst, io.LimitReader(file, maxJobFileSize + 1))
+	closeErr := dst.Close()
+	if copyErr != nil || closeErr != nil{
+		os.Remove(inputPath)
+		return nil, errs.NewUnexpectedError(errors.Join(copyErr, closeErr).Error())
+	}
+	if written > maxJobFileSize{
+		os.Remove(inputPath)
+		return nil, errs.NewInvalidFileError("the file can't be larger than 64MB")
+	}
+
+	total, err := countNumbers(inputPath)
+	if err == nil && total == 0{
+		err = errs.NewInvalidFileError("no numbers found")
+	}
+	if err != nil{
+		os.Remove(inputPath)
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	job := model.LookupJob{
+		ID: id,
+		Owner: owner,
+		FileName: filepath.Base(fileName),
+		Status: model.JobQueued,
+		Total: total,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.CreateJob(&job); err != nil{
+		os.Remove(inputPath)
+		return nil, err
+	}
+	s.enqueue(id)
+	return &job, nil
+}
+
+func (s *DefaultJobService) GetJob(owner string, id string) (*model.LookupJob, error){
+
+	job, err := s.repo.GetJobById(id)
+	if err != nil{
+		return nil, err
+	}
+	if job.Owner != owner{
+		return nil, errs.NewJobNotFoundError()
+	}
+	return job, nil
+}
+
+func (s *DefaultJobService) GetJobsByOwner(owner string) (*[]model.LookupJob, error){
+	return s.repo.GetJobsByOwner(owner)
+}
+
+func (s *DefaultJobService) CancelJob(owner string, id string) (error){
+
+	if _, err := s.GetJob(owner, id); err != nil{
+		return err
+	}
+
+	// The lock keeps a runner from picking the job up or finishing it in the meantime
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cancel, ok := s.cancels[id]; ok{
+		cancel()
+		return nil
+	}
+	job, err := s.repo.GetJobById(id)
+	if err != nil{
+		return err
+	}
+	if job.Finished(){
+		return errs.NewJobFinishedError()
+	}
+	return s.repo.UpdateJobStatus(id, model.JobCancelled, "")
+}
+
+func (s *DefaultJobService) OpenJobResult(owner string, id string) (io.ReadCloser, error){
+
+	job, err := s.GetJob(owner, id)
+	if err != nil{
+		return nil, err
+	}
+	if job.Status != model.JobCompleted{
+		return nil, errs.NewJobNotFinishedError()
+	}
+	file, err := os.Open(s.resultPath(id))
+	if err != nil{
+		return nil, errs.NewUnexpectedError(err.Error())
+	}
+	return file, nil
+}
+
+func (s *DefaultJobService) inputPath(id string) string{
+	return filepath.Join(s.dir, id + ".input")
+}
+
+func (s *DefaultJobService) resultPath(id string) string{
+	return filepath.Join(s.dir, id + ".csv")
+}
+
+func (s *DefaultJobService) enqueue(id string){
+
+	s.mu.Lock()
+	s.queue = append(s.queue, id)
+	s.mu.Unlock()
+	s.wake.Signal()
+}
+
+func (s *DefaultJobService) runner(){
+
+	for {
+		s.mu.Lock()
+		for len(s.queue) == 0{
+			s.wake.Wait()
+		}
+		id := s.queue[0]
+		s.queue = s.queue[1:]
+		ctx, cancel := context.WithCancel(context.Background())
+		s.cancels[id] = cancel
+		s.mu.Unlock()
+
+		s.runJob(ctx, id)
+	}
+}
+
+func (s *DefaultJobService) runJob(ctx context.Context, id string){
+
+	job, err := s.repo.GetJobById(id)
+	if err != nil{
+		s.logger.Error().Err(err).Str("package","service").Str("context","runJob").Str("job", id).Msg("Error loading lookup job")
+		s.finishJob(id, "", "")
+		return
+	}
+	if job.Finished(){
+		s.finishJob(id, "", "")
+		return
+	}
+	if job.Status != model.JobRunning{
+		if err := s.repo.UpdateJobStatus(id, model.JobRunning, ""); err != nil{
+			s.logger.Error().Err(err).Str("package","service").Str("context","runJob").Str("job", id).Msg("Error starting lookup job")
+		}
+	}
+
+	status, message := model.JobCompleted, ""
+	if err := s.processJob(ctx, job); err != nil{
+		if errors.Is(err, context.Canceled){
+			status = model.JobCancelled
+		}else{
+			status, message = model.JobFailed, err.Error()
+		}
+	}
+	s.finishJob(id, status, message)
+}
+
+// finishJob saves the final status of a job, if any, and forgets its cancel function
+func (s *DefaultJobService) finishJob(id string, status string, message string){
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cancel, ok := s.cancels[id]; ok{
+		cancel()
+		delete(s.cancels, id)
+	}
+	if status == ""{
+		return
+	}
+	if err := s.repo.UpdateJobStatus(id, status, message); err != nil{
+		s.logger.Error().Err(err).Str("package","service").Str("context","finishJob").Str("job", id).Msg("Error finishing lookup job")
+	}
+}
+
+// processJob looks up the numbers of the job in chunks, appending each chunk to the
+// results file and saving the progress before moving to the next one
+func (s *DefaultJobService) processJob(ctx context.Context, job *model.LookupJob) (error){
+
+	in, err := os.Open(s.inputPath(job.ID))
+	if err != nil{
+		return err
+	}
+	defer in.Close()
+
+	out, err := s.openResult(job)
+	if err != nil{
+		return err
+	}
+	defer out.Close()
+
+	numbers := newNumberReader(in)
+	for i := 0; i < job.Processed; i++ {
+		if _, err := numbers.Next(); err != nil{
+			return err
+		}
+	}
+
+	writer := csv.NewWriter(out)
+	processed, failed := job.Processed, job.Failed
+	for {
+		if err := ctx.Err(); err != nil{
+			return err
+		}
+
+		var chunk []string
+		var readErr error
+		for len(chunk) < jobChunkSize{
+			var number string
+			number, readErr = numbers.Next()
+			if readErr != nil{
+				break
+			}
+			chunk = append(chunk, number)
+		}
+		if readErr != nil && readErr != io.EOF{
+			return readErr
+		}
+
+		rows, chunkFailed := s.lookupChunk(chunk)
+		if err := writer.WriteAll(rows); err != nil{
+			return err
+		}
+		processed += len(chunk)
+		failed += chunkFailed
+		if err := s.repo.UpdateJobProgress(job.ID, processed, failed); err != nil{
+			return err
+		}
+
+		if readErr == io.EOF{
+			return nil
+		}
+	}
+}
+
+// openResult opens the results file of a job positioned after the rows already processed,
+// or starts a new one when there's nothing to resume
+func (s *DefaultJobService) openResult(job *model.LookupJob) (*os.File, error){
+
+	path := s.resultPath(job.ID)
+	if job.Processed > 0{
+		file, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err == nil{
+			if err = truncateRecords(file, job.Processed + 1); err == nil{
+				return file, nil
+			}
+			file.Close()
+		}
+		job.Processed, job.Failed = 0, 0
+	}
+
+	file, err := os.Create(path)
+	if err != nil{
+		return nil, err
+	}
+	writer := csv.NewWriter(file)
+	writer.Write(jobResultHeader)
+	writer.Flush()
+	if err := writer.Error(); err != nil{
+		file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+// lookupChunk looks up the numbers concurrently and returns a result row for each of
+// them in the same order, along with the amount of numbers that failed
+func (s *DefaultJobService) lookupChunk(chunk []string) ([][]string, int){
+
+	rows := make([][]string, len(chunk))
+	var failed int32
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < jobLookupWorkers; w++ {
+		wg.Add(1)
+		go func(){
+			defer wg.Done()
+			for i := range indexes{
+				row, ok := s.lookupRow(chunk[i])
+				rows[i] = row
+				if !ok{
+					atomic.AddInt32(&failed, 1)
+				}
+			}
+		}()
+	}
+	for i := range chunk{
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return rows, int(failed)
+}
+
+func (s *DefaultJobService) lookupRow(input string) ([]string, bool){
+
+	normalized, err := normalize.Normalize(input, nil)
+	if err != nil{
+		return []string{input, "", "", "", "", "", err.Error()}, false
+	}
+	resp, err := s.lookup.LookupMSISDN(normalized.Number)
+	if err != nil{
+		return []string{input, normalized.Number, "", "", "", "", err.Error()}, false
+	}
+	return []string{input, normalized.Number, resp.MNO, resp.CC, resp.SN, resp.CI, ""}, true
+}
+
+// numberReader reads the first column of a CSV or plain text file, one number per row
+type numberReader struct {
+	r *csv.Reader
+	started bool
+}
+
+func newNumberReader(r io.Reader) *numberReader{
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+	return &numberReader{r: reader}
+}
+
+// Next returns the next number, skipping the first row if it's a header without any digits
+func (n *numberReader) Next() (string, error){
+
+	record, err := n.NextRecord()
+	if err != nil{
+		return "", err
+	}
+	return record[0], nil
+}
+
+// NextRecord returns the next row with its first column trimmed, skipping the first row
+// if it's a header without any digits in its first column
+func (n *numberReader) NextRecord() ([]string, error){
+
+	for {
+		record, err := n.r.Read()
+		if err != nil{
+			return nil, err
+		}
+		record[0] = strings.TrimSpace(record[0])
+		if !n.started{
+			n.started = true
+			record[0] = strings.TrimPrefix(record[0], "\ufeff")
+			if !strings.ContainsAny(record[0], "0123456789"){
+				continue
+			}
+		}
+		return record, nil
+	}
+}
+
+func countNumbers(path string) (int, error){
+
+	file, err := os.Open(path)
+	if err != nil{
+		return 0, errs.NewUnexpectedError(err.Error())
+	}
+	defer file.Close()
+
+	numbers := newNumberReader(file)
+	total := 0
+	for {
+		_, err := numbers.Next()
+		if err == io.EOF{
+			return total, nil
+		}
+		if err != nil{
+			return 0, errs.NewInvalidFileError(err.Error())
+		}
+		total++
+	}
+}
+
+// truncateRecords cuts the CSV file after the given amount of records and moves to its end
+func truncateRecords(file *os.File, records int) (error){
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	for i := 0; i < records; i++ {
+		if _, err := reader.Read(); err != nil{
+			return err
+		}
+	}
+	offset := reader.InputOffset()
+	if err := file.Truncate(offset); err != nil{
+		return err
+	}
+	_, err := file.Seek(offset, io.SeekStart)
+	return err
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/robesmi/MSISDNApp/mocks/repository"
+	servicemocks "github.com/robesmi/MSISDNApp/mocks/service"
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/dto"
+	"github.com/robesmi/MSISDNApp/model/errs"
+	"github.com/rs/zerolog"
+)
+
+var mockJobRepo *repository.MockJobRepository
+var mockLookup *servicemocks.MockMSISDNService
+var jobService *DefaultJobService
+
+func setupJobs(t *testing.T) func(){
+
+	ctrl := gomock.NewController(t)
+	mockJobRepo = repository.NewMockJobRepository(ctrl)
+	mockLookup = servicemocks.NewMockMSISDNService(ctrl)
+	jobService = NewJobService(mockJobRepo, mockLookup, t.TempDir(), zerolog.Nop())
+
+	return func(){
+		jobService = nil
+		ctrl.Finish()
+	}
+}
+
+func TestCreateJobCountsNumbers(t *testing.T) {
+
+	//Arrange
+	teardown := setupJobs(t)
+	defer teardown()
+
+	file := "number,name\n38977123456,Ana\n\"+389 71 123 456\",Marko\n"
+	var saved model.LookupJob
+	mockJobRepo.EXPECT().CreateJob(gomock.Any()).DoAndReturn(func(job *model.LookupJob) error {
+		saved = *job
+		return nil
+	})
+
+	//Act
+	job, err := jobService.CreateJob("owner1", "contacts.csv", strings.NewReader(file))
+
+	//Assert
+	if err != nil{
+		t.Fatalf("Error in TestCreateJobCountsNumbers:\n expected %s\n got %s", "nil", err)
+	}
+	if job.Total != 2 || saved.Total != 2{
+		t.Errorf("Error in TestCreateJobCountsNumbers:\n expected %d numbers\n got %d", 2, job.Total)
+	}
+	if job.Status != model.JobQueued || job.Owner != "owner1"{
+		t.Errorf("Error in TestCreateJobCountsNumbers:\n expected a queued job of owner1\n got %s job of %s", job.Status, job.Owner)
+	}
+}
+
+func TestCreateJobEmptyFile(t *testing.T) {
+
+	//Arrange
+	teardown := setupJobs(t)
+	defer teardown()
+
+	//Act
+	_, err := jobService.CreateJob("owner1", "empty.csv", strings.NewReader("number\n"))
+
+	//Assert
+	if _, ok := err.(*errs.InvalidFileError); !ok{
+		t.Errorf("Error in TestCreateJobEmptyFile:\n expected %s\n got %v", "InvalidFileError", err)
+	}
+}
+
+func TestProcessJobWritesResults(t *testing.T) {
+
+	//Arrange
+	teardown := setupJobs(t)
+	defer teardown()
+
+	job := model.LookupJob{ID: "job1", Owner: "owner1", Status: model.JobRunning, Total: 3}
+	os.WriteFile(jobService.inputPath(job.ID), []byte("38977123456\nlorem ipsum\n123456789\n"), 0o600)
+
+	found := dto.NumberLookupResponse{MNO: "A1", CC: "389", SN: "123456", CI: "mk"}
+	mockLookup.EXPECT().LookupMSISDN("38977123456").Return(&found, nil)
+	mockLookup.EXPECT().LookupMSISDN("123456789").Return(nil, errs.NewNumberNotFoundError())
+	mockJobRepo.EXPECT().UpdateJobProgress(job.ID, 3, 2).Return(nil)
+
+	//Act
+	err := jobService.processJob(context.Background(), &job)
+
+	//Assert
+	if err != nil{
+		t.Fatalf("Error in TestProcessJobWritesResults:\n expected %s\n got %s", "nil", err)
+	}
+	result, _ := os.ReadFile(jobService.resultPath(job.ID))
+	lines := strings.Split(strings.TrimSpace(string(result)), "\n")
+	if len(lines) != 4{
+		t.Fatalf("Error in TestProcessJobWritesResults:\n expected %d lines\n got %s", 4, result)
+	}
+	if lines[1] != "38977123456,38977123456,A1,389,123456,mk,"{
+		t.Errorf("Error in TestProcessJobWritesResults:\n expected the first number to be enriched\n got %s", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "lorem ipsum,") || !strings.HasPrefix(lines[3], "123456789,"){
+		t.Errorf("Error in TestProcessJobWritesResults:\n expected results in input order\n got %s", result)
+	}
+}
+
+func TestProcessJobResumes(t *testing.T) {
+
+	//Arrange
+	teardown := setupJobs(t)
+	defer teardown()
+
+	job := model.LookupJob{ID: "job1", Owner: "owner1", Status: model.JobRunning, Total: 2, Processed: 1}
+	os.WriteFile(jobService.inputPath(job.ID), []byte("38977123456\n38971123456\n"), 0o600)
+	// The second row was written before the progress was saved and should be redone
+	os.WriteFile(jobService.resultPath(job.ID), []byte(strings.Join(jobResultHeader, ",") + "\n38977123456,38977123456,A1,389,123456,mk,\n38971123456,38971123456,Telekom,389,1234"), 0o600)
+
+	found := dto.NumberLookupResponse{MNO: "Telekom", CC: "389", SN: "123456", CI: "mk"}
+	mockLookup.EXPECT().LookupMSISDN("38971123456").Return(&found, nil)
+	mockJobRepo.EXPECT().UpdateJobProgress(job.ID, 2, 0).Return(nil)
+
+	//Act
+	err := jobService.processJob(context.Background(), &job)
+
+	//Assert
+	if err != nil{
+		t.Fatalf("Error in TestProcessJobResumes:\n expected %s\n got %s", "nil", err)
+	}
+	result, _ := os.ReadFile(jobService.resultPath(job.ID))
+	lines := strings.Split(strings.TrimSpace(string(result)), "\n")
+	if len(lines) != 3 || lines[2] != "38971123456,38971123456,Telekom,389,123456,mk,"{
+		t.Errorf("Error in TestProcessJobResumes:\n expected the interrupted row to be rewritten\n got %s", result)
+	}
+}
+
+func TestCancelQueuedJob(t *testing.T) {
+
+	//Arrange
+	teardown := setupJobs(t)
+	defer teardown()
+
+	job := model.LookupJob{ID: "job1", Owner: "owner1", Status: model.JobQueued}
+	mockJobRepo.EXPECT().GetJobById(job.ID).Return(&job, nil).Times(2)
+	mockJobRepo.EXPECT().UpdateJobStatus(job.ID, model.JobCancelled, "").Return(nil)
+
+	//Act
+	err := jobService.CancelJob("owner1", job.ID)
+
+	//Assert
+	if err != nil{
+		t.Errorf("Error in TestCancelQueuedJob:\n expected %s\n got %s", "nil", err)
+	}
+}
+
+func TestGetJobOfAnotherOwner(t *testing.T) {
+
+	//Arrange
+	teardown := setupJobs(t)
+	defer teardown()
+
+	job := model.LookupJob{ID: "job1", Owner: "owner1", Status: model.JobQueued}
+	mockJobRepo.EXPECT().GetJobById(job.ID).Return(&job, nil)
+
+	//Act
+	_, err := jobService.GetJob("owner2", job.ID)
+
+	//Assert
+	if _, ok := err.(*errs.JobNotFoundError); !ok{
+		t.Errorf("Error in TestGetJobOfAnotherOwner:\n expected %s\n got %v", "JobNotFoundError", err)
+	}
+}
@@ -4,59 +4,319 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/robesmi/MSISDNApp/lookupcache"
 	"github.com/robesmi/MSISDNApp/model"
 	"github.com/robesmi/MSISDNApp/model/dto"
+	"github.com/robesmi/MSISDNApp/model/errs"
+	"github.com/robesmi/MSISDNApp/normalize"
+	"github.com/robesmi/MSISDNApp/numplan"
+	"github.com/robesmi/MSISDNApp/portability"
 	"github.com/robesmi/MSISDNApp/repository"
 )
 
 type DefaultMSISDNService struct {
 	repo repository.MSISDNRepository
+	ported *portability.Store
+	// cache holds recent results of the current numbering plan, nil when caching is off
+	cache *lookupcache.Cache
 }
 
-func NewMSISDNService(repository repository.MSISDNRepository) DefaultMSISDNService{
-	return DefaultMSISDNService{repository}
+func NewMSISDNService(repository repository.MSISDNRepository, ported *portability.Store) DefaultMSISDNService{
+	return DefaultMSISDNService{repository, ported, nil}
 }
 
+// NewCachedMSISDNService returns a service that answers repeated lookups from the
+// cache, which is invalidated whenever a country or range is added or removed
+func NewCachedMSISDNService(repository repository.MSISDNRepository, ported *portability.Store, cache *lookupcache.Cache) DefaultMSISDNService{
+	return DefaultMSISDNService{repository, ported, cache}
+}
+
+//go:generate mockgen -destination=../mocks/service/mockMSISDNService.go -package=service github.com/robesmi/MSISDNApp/service MSISDNService
 type MSISDNService interface {
 	LookupMSISDN(string) (*dto.NumberLookupResponse, error)
+	LookupMSISDNAt(string, time.Time) (*dto.NumberLookupResponse, error)
+	ValidateMSISDN(string) (*dto.ValidationResponse, error)
+	ValidateMSISDNAt(string, time.Time) (*dto.ValidationResponse, error)
+	LookupPartial(string) (*dto.PartialLookupResponse, error)
+	LookupPartialAt(string, time.Time) (*dto.PartialLookupResponse, error)
+	ExtractMSISDNs(string, string) (*dto.ExtractionResponse, error)
+	GetRegion(string) (*normalize.Region, error)
 	AddNewCountry(*dto.CountryRequest) (error)
 	AddNewMobileOperator(*dto.OperatorRequest) (error)
 	GetAllCountries() (*[]model.Country, error)
 	GetAllMobileOperators() (*[]model.MobileOperator, error)
-	RemoveCountry(string) (error)
-	RemoveOperator(string) (error)
+	RemoveCountry(string, string) (error)
+	RemoveOperator(string, string) (error)
+	GetOverlaps() (*[]model.RuleOverlap, error)
+	// SelfCheck checks that every rule in effect can be looked up, see CheckRules
+	SelfCheck() (*[]model.RuleCheck, error)
+	AddNewNetworkOperator(*dto.NetworkOperatorRequest) (error)
+	GetAllNetworkOperators() (*[]model.NetworkOperator, error)
+	RemoveNetworkOperator(string) (error)
+	// GetCacheStats returns the counters of the lookup cache, all zero when there's none
+	GetCacheStats() lookupcache.Stats
+	// GetCountryDetails returns the details of a country by any of its ISO 3166-1 codes, or a CountryNotFoundError
+	GetCountryDetails(string) (*dto.CountryDetails, error)
+	GetAllCountryDetails() (*[]dto.CountryDetails, error)
+	SaveCountryDetails(*dto.CountryDetailsRequest) (error)
 }
 
-// LookupMSISDN takes a full MSISDN as a string and returns
-// a response containing the MNO, country code, subscriber number
-// and the country identifier in ISO 3166-1-alpha-2 format
-// or an error otherwise
-//go:generate mockgen -destination=../mocks/service/mockMSISDNService.go -package=service github.com/robesmi/MSISDNApp/service MSISDNService
+// LookupMSISDN takes a full MSISDN as a string and returns a response containing the MNO,
+// country code, subscriber number and the country identifier in ISO 3166-1-alpha-2 format
+// along with the number formatted for display, or an error otherwise. Ported numbers get
+// the MNO they were ported to, with the MNO holding their range as the original range
+// holder. The MCC/MNC pairs and host network are those of the MNO the number belongs to
 func (s DefaultMSISDNService) LookupMSISDN(input string) (*dto.NumberLookupResponse, error){
+
+	generation := s.cache.Generation()
+	response, cached, err := s.cache.Get(input)
+	if !cached{
+		response, err = s.lookupPlan(input, time.Now().UTC())
+		_, notFound := err.(*errs.NumberNotFoundError)
+		_, noCarrier := err.(*errs.NoCarriersFoundError)
+		if err == nil || notFound || noCarrier{
+			s.cache.Put(input, generation, response, err)
+		}
+	}
+	if err != nil{
+		return nil, err
+	}
+	if err := s.applyPorting(response, input); err != nil{
+		return nil, err
+	}
+	return response, nil
+}
+
+// LookupMSISDNAt looks up a full MSISDN with the numbering plan in effect at
+// the given time, to explain records made under an earlier plan. The porting
+// history isn't kept, so ported numbers get the MNO they are ported to now
+func (s DefaultMSISDNService) LookupMSISDNAt(input string, at time.Time) (*dto.NumberLookupResponse, error){
+
+	response, err := s.lookupMSISDN(input, at.UTC())
+	if err != nil{
+		return nil, err
+	}
+	asOf := at.UTC()
+	response.AsOf = &asOf
+	return response, nil
+}
+
+func (s DefaultMSISDNService) lookupMSISDN(input string, at time.Time) (*dto.NumberLookupResponse, error){
+
+	response, err := s.lookupPlan(input, at)
+	if err != nil{
+		return nil, err
+	}
+	if err := s.applyPorting(response, input); err != nil{
+		return nil, err
+	}
+	return response, nil
+}
+
+// lookupPlan looks a number up in the numbering plan in effect at the time, which
+// is all that's cached as the ported numbers change independently of the plan
+func (s DefaultMSISDNService) lookupPlan(input string, at time.Time) (*dto.NumberLookupResponse, error){
 	
-	countryResponse, err := s.repo.LookupCountryCode(input)
+	countryResponse, err := s.repo.LookupCountryCode(input, at)
 	if err != nil {
 		return nil, err
 	}
-	significantNumber := fmt.Sprint(input[countryResponse.CountryCodeLength:])
+	// The significant number follows the calling code, which for countries sharing
+	// one, like the NANP countries, includes the area code telling them apart
+	significantNumber := fmt.Sprint(input[len(countryResponse.CountryCode):])
 	
-	mnoResponse, err := s.repo.LookupMobileOperator(countryResponse.CountryIdentifier, significantNumber)
+	mnoResponse, err := s.repo.LookupMobileOperator(countryResponse.CountryIdentifier, significantNumber, at)
 	if err != nil{
 		return nil, err
 	}
 
 	subscriberNumber := fmt.Sprint(significantNumber[mnoResponse.PrefixLength:])
+	details, err := s.lookupCountryDetails(countryResponse.CountryIdentifier)
+	if err != nil{
+		return nil, err
+	}
 
 	var response = dto.NumberLookupResponse{
 		MNO: mnoResponse.MNO,
+		OperatorID: mnoResponse.OperatorID,
+		NetworkCodes: parseNetworkCodes(mnoResponse.NetworkCodes),
+		HostNetwork: mnoResponse.HostNetwork,
+		Type: mnoResponse.NumberType,
 		SN: subscriberNumber,
 		CI: countryResponse.CountryIdentifier,
 		CC: countryResponse.CountryCode,
+		Country: details,
+		Formats: formatNumber(countryResponse.CountryCode, significantNumber, countryResponse.TrunkPrefix, countryResponse.NumberGrouping),
 	}
 	return &response, nil
 }
 
+// applyPorting gives a ported number the details of the MNO it was ported to, keeping
+// the MNO holding its range as the original range holder
+func (s DefaultMSISDNService) applyPorting(response *dto.NumberLookupResponse, input string) (error){
+
+	if mno, ok := s.ported.Lookup(input); ok{
+		response.Ported = true
+		response.RangeHolder = response.MNO
+		response.MNO = mno
+		response.OperatorID, response.NetworkCodes, response.HostNetwork = 0, nil, ""
+		operator, err := s.repo.GetNetworkOperatorByName(mno)
+		if err == nil{
+			response.OperatorID = operator.ID
+			response.NetworkCodes = parseNetworkCodes(operator.NetworkCodes)
+			response.HostNetwork = operator.HostNetwork
+		}else if _, ok := err.(*errs.OperatorNotFoundError); !ok{
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateMSISDN takes a full MSISDN as a string and returns a verdict on
+// its validity with the reason for it, along with the lookup result of valid
+// numbers. An error is only returned when the check itself fails
+func (s DefaultMSISDNService) ValidateMSISDN(input string) (*dto.ValidationResponse, error){
+	return s.validateMSISDN(input, nil)
+}
+
+// ValidateMSISDNAt gives the verdict on a full MSISDN with the numbering plan in
+// effect at the given time, like LookupMSISDNAt
+func (s DefaultMSISDNService) ValidateMSISDNAt(input string, at time.Time) (*dto.ValidationResponse, error){
+
+	at = at.UTC()
+	return s.validateMSISDN(input, &at)
+}
+
+func (s DefaultMSISDNService) validateMSISDN(input string, asOf *time.Time) (*dto.ValidationResponse, error){
+
+	response := dto.ValidationResponse{Number: input}
+	var result *dto.NumberLookupResponse
+	var err error
+	at := time.Now().UTC()
+	if asOf == nil{
+		result, err = s.LookupMSISDN(input)
+	}else{
+		at = *asOf
+		result, err = s.LookupMSISDNAt(input, at)
+	}
+	if err == nil{
+		response.Verdict = model.ValidityValid
+		response.Reason = "The number belongs to a known range"
+		response.CountryIdentifier = result.CI
+		response.Result = result
+		return &response, nil
+	}
+	_, notFound := err.(*errs.NumberNotFoundError)
+	_, noCarrier := err.(*errs.NoCarriersFoundError)
+	if !notFound && !noCarrier{
+		return nil, err
+	}
+
+	country, ccErr := s.repo.LookupCallingCode(input, at)
+	if ccErr != nil{
+		if _, ok := ccErr.(*errs.CountryNotFoundError); ok{
+			response.Verdict = model.ValidityInvalidCountryCode
+			response.Reason = "The number doesn't start with a known country code"
+			return &response, nil
+		}
+		return nil, ccErr
+	}
+	response.CountryIdentifier = country.CountryIdentifier
+
+	nsnLength := len(input) - len(country.CountryCode)
+	switch {
+	case country.NSNMinLength > 0 && nsnLength < country.NSNMinLength:
+		response.Verdict = model.ValidityTooShort
+		response.Reason = fmt.Sprintf("Numbers of %s have at least %d digits after the country code", country.CountryIdentifier, country.NSNMinLength)
+	case country.NSNMaxLength > 0 && nsnLength > country.NSNMaxLength:
+		response.Verdict = model.ValidityTooLong
+		response.Reason = fmt.Sprintf("Numbers of %s have at most %d digits after the country code", country.CountryIdentifier, country.NSNMaxLength)
+	case noCarrier:
+		response.Verdict = model.ValidityUnknownRange
+		response.Reason = "The number isn't part of any known range of " + country.CountryIdentifier
+	default:
+		response.Verdict = model.ValidityPossiblyValid
+		response.Reason = "The number has a valid length but doesn't match the format of " + country.CountryIdentifier
+	}
+	return &response, nil
+}
+
+// LookupPartial takes the start of a MSISDN still being typed and returns the countries
+// and ranges it can still belong to, best ranked first, along with how many more digits
+// it can take and whether it can already be looked up. Porting isn't taken into account
+func (s DefaultMSISDNService) LookupPartial(input string) (*dto.PartialLookupResponse, error){
+	return s.lookupPartial(input, nil)
+}
+
+// LookupPartialAt works like LookupPartial with the numbering plan in effect at the given time
+func (s DefaultMSISDNService) LookupPartialAt(input string, at time.Time) (*dto.PartialLookupResponse, error){
+
+	at = at.UTC()
+	return s.lookupPartial(input, &at)
+}
+
+func (s DefaultMSISDNService) lookupPartial(input string, asOf *time.Time) (*dto.PartialLookupResponse, error){
+
+	at := time.Now().UTC()
+	if asOf != nil{
+		at = *asOf
+	}
+	match, err := s.repo.LookupPartial(input, at)
+	if err != nil{
+		return nil, err
+	}
+	response := dto.PartialLookupResponse{
+		Number: input,
+		AsOf: asOf,
+		Complete: match.Complete,
+		MinRemaining: match.MinRemaining,
+		MaxRemaining: match.MaxRemaining,
+		Countries: []dto.PartialCountry{},
+		Operators: []dto.PartialOperator{},
+	}
+	for _, candidate := range match.Countries{
+		response.Countries = append(response.Countries, dto.PartialCountry{
+			CountryIdentifier: candidate.Country.CountryIdentifier,
+			CountryCode: candidate.Country.CountryCode,
+			MinRemaining: candidate.MinRemaining,
+			MaxRemaining: candidate.MaxRemaining,
+		})
+	}
+	for _, candidate := range match.Operators{
+		response.Operators = append(response.Operators, dto.PartialOperator{
+			CountryIdentifier: candidate.Operator.CountryIdentifier,
+			MNO: candidate.Operator.MNO,
+			OperatorID: candidate.Operator.OperatorID,
+			Type: candidate.Operator.NumberType,
+			MinRemaining: candidate.MinRemaining,
+			MaxRemaining: candidate.MaxRemaining,
+		})
+	}
+	return &response, nil
+}
+
+// GetRegion takes a country identifier and returns the dialing rules needed
+// to normalize numbers written in the national format of that country
+func (s DefaultMSISDNService) GetRegion(ci string) (*normalize.Region, error){
+
+	country, err := s.repo.GetCountryByIdentifier(strings.ToLower(ci))
+	if err != nil{
+		return nil, err
+	}
+	region := normalize.Region{
+		CallingCode: country.CountryCode,
+		TrunkPrefix: country.TrunkPrefix,
+	}
+	for _, prefix := range strings.Split(country.InternationalPrefix, ","){
+		if prefix = strings.TrimSpace(prefix); prefix != ""{
+			region.InternationalPrefixes = append(region.InternationalPrefixes, prefix)
+		}
+	}
+	return &region, nil
+}
+
 func (s DefaultMSISDNService) GetAllCountries() (*[]model.Country, error){
 
 	resp, err := s.repo.GetAllCountries()
@@ -78,14 +338,59 @@ func (s DefaultMSISDNService) GetAllMobileOperators() (*[]model.MobileOperator,
 
 func (s DefaultMSISDNService) AddNewCountry( counReq *dto.CountryRequest) (error){
 
-	codeLength, err := strconv.Atoi(counReq.CountryCodeLength)
+	codeLength, err := parseCodeLength(counReq.CountryCode, counReq.CountryCodeLength)
+	if err != nil{
+		return err
+	}
+	nsnMin, nsnMax, err := parseLengths(counReq.NSNMinLength, counReq.NSNMaxLength)
 	if err != nil{
 		return err
 	}
-	res := s.repo.AddNewCountry(counReq.CountryNumberFormat, counReq.CountryCode, strings.ToLower(counReq.CountryIdentifier), codeLength)
+	priority, explicit, err := parsePriority(counReq.Priority)
+	if err != nil{
+		return err
+	}
+	from, to, err := parsePeriod(counReq.EffectiveFrom, counReq.EffectiveTo)
+	if err != nil{
+		return err
+	}
+	if err := validateRule(counReq.CountryNumberFormat, counReq.ExcludedFormat); err != nil{
+		return err
+	}
+	if err := numplan.CheckCallingCode(counReq.CountryNumberFormat, counReq.CountryCode); err != nil{
+		return err
+	}
+	country := model.Country{
+		CountryNumberFormat: counReq.CountryNumberFormat,
+		ExcludedFormat: counReq.ExcludedFormat,
+		CountryCode: counReq.CountryCode,
+		CountryIdentifier: strings.ToLower(counReq.CountryIdentifier),
+		CountryCodeLength: codeLength,
+		TrunkPrefix: counReq.TrunkPrefix,
+		InternationalPrefix: counReq.InternationalPrefix,
+		NumberGrouping: counReq.NumberGrouping,
+		NSNMinLength: nsnMin,
+		NSNMaxLength: nsnMax,
+		Priority: priority,
+		EffectiveFrom: from,
+		EffectiveTo: to,
+	}
+	existing, err := s.repo.GetAllCountries()
+	if err != nil{
+		return err
+	}
+	overlaps, err := numplan.CountryOverlaps(country, *existing)
+	if err != nil{
+		return err
+	}
+	if err := checkOverlaps(overlaps, explicit); err != nil{
+		return err
+	}
+	res := s.repo.AddNewCountry(&country)
 	if res != nil{
 		return res
 	}
+	s.cache.Invalidate()
 	return nil
 }
 
@@ -95,27 +400,327 @@ func (s DefaultMSISDNService) AddNewMobileOperator( mobileReq *dto.OperatorReque
 	if err != nil{
 		return err
 	}
-	res := s.repo.AddNewMobileOperator(strings.ToLower(mobileReq.CountryIdentifier), mobileReq.PrefixFormat, mobileReq.MNO, prefLength)
+	operatorID, err := strconv.Atoi(mobileReq.OperatorID)
+	if err != nil{
+		return err
+	}
+	priority, explicit, err := parsePriority(mobileReq.Priority)
+	if err != nil{
+		return err
+	}
+	from, to, err := parsePeriod(mobileReq.EffectiveFrom, mobileReq.EffectiveTo)
+	if err != nil{
+		return err
+	}
+	if err := validateRule(mobileReq.PrefixFormat, mobileReq.ExcludedFormat); err != nil{
+		return err
+	}
+	numberType := mobileReq.NumberType
+	if numberType == ""{
+		numberType = model.NumberTypeMobile
+	}
+	operator := model.MobileOperator{
+		CountryIdentifier: strings.ToLower(mobileReq.CountryIdentifier),
+		PrefixFormat: mobileReq.PrefixFormat,
+		ExcludedFormat: mobileReq.ExcludedFormat,
+		OperatorID: operatorID,
+		PrefixLength: prefLength,
+		NumberType: numberType,
+		Priority: priority,
+		EffectiveFrom: from,
+		EffectiveTo: to,
+	}
+	existing, err := s.repo.GetAllMobileOperators()
+	if err != nil{
+		return err
+	}
+	overlaps, err := numplan.OperatorOverlaps(operator, *existing)
+	if err != nil{
+		return err
+	}
+	if err := checkOverlaps(overlaps, explicit); err != nil{
+		return err
+	}
+	res := s.repo.AddNewMobileOperator(&operator)
 	if res != nil{
 		return res
 	}
+	s.cache.Invalidate()
 	return nil
 }
 
-func (s DefaultMSISDNService) RemoveCountry(prefix string) (error){
+// RemoveCountry ends the country rules with the pattern at the optional end time,
+// or right away when there isn't one. Lookups of earlier times still find them
+func (s DefaultMSISDNService) RemoveCountry(prefix string, effectiveTo string) (error){
 
-	err := s.repo.RemoveCountry(prefix)
+	at, err := parseEnd(effectiveTo)
+	if err != nil{
+		return err
+	}
+	err = s.repo.RemoveCountry(prefix, at)
 	if err != nil {
 		return err
 	}
+	s.cache.Invalidate()
 	return nil
 }
 
-func (s DefaultMSISDNService) RemoveOperator(prefix string) (error){
+// RemoveOperator ends the operator rules with the pattern at the optional end time,
+// or right away when there isn't one. Lookups of earlier times still find them
+func (s DefaultMSISDNService) RemoveOperator(prefix string, effectiveTo string) (error){
 
-	err := s.repo.RemoveOperator(prefix)
+	at, err := parseEnd(effectiveTo)
+	if err != nil{
+		return err
+	}
+	err = s.repo.RemoveOperator(prefix, at)
 	if err != nil {
 		return err
 	}
+	s.cache.Invalidate()
 	return nil
-}
\ No newline at end of file
+}
+
+func (s DefaultMSISDNService) AddNewNetworkOperator(operatorReq *dto.NetworkOperatorRequest) (error){
+
+	hostID := 0
+	if operatorReq.HostOperatorID != ""{
+		id, err := strconv.Atoi(operatorReq.HostOperatorID)
+		if err != nil{
+			return err
+		}
+		hostID = id
+	}
+	status := operatorReq.Status
+	if status == ""{
+		status = model.OperatorStatusActive
+	}
+	operator := model.NetworkOperator{
+		BrandName: strings.TrimSpace(operatorReq.BrandName),
+		LegalName: strings.TrimSpace(operatorReq.LegalName),
+		Status: status,
+		NetworkCodes: strings.ReplaceAll(operatorReq.NetworkCodes, " ", ""),
+		HostOperatorID: hostID,
+	}
+	if operator.LegalName == ""{
+		operator.LegalName = operator.BrandName
+	}
+	return s.repo.AddNewNetworkOperator(&operator)
+}
+
+func (s DefaultMSISDNService) GetAllNetworkOperators() (*[]model.NetworkOperator, error){
+	return s.repo.GetAllNetworkOperators()
+}
+
+func (s DefaultMSISDNService) RemoveNetworkOperator(id string) (error){
+
+	operatorID, err := strconv.Atoi(id)
+	if err != nil{
+		return err
+	}
+	return s.repo.RemoveNetworkOperator(operatorID)
+}
+
+func (s DefaultMSISDNService) GetCacheStats() lookupcache.Stats{
+	return s.cache.Stats()
+}
+
+// GetOverlaps returns every pair of rules matching a common number, which is
+// resolved by the rules' priorities and then by which one is more specific
+func (s DefaultMSISDNService) GetOverlaps() (*[]model.RuleOverlap, error){
+
+	countries, err := s.repo.GetAllCountries()
+	if err != nil{
+		return nil, err
+	}
+	operators, err := s.repo.GetAllMobileOperators()
+	if err != nil{
+		return nil, err
+	}
+	overlaps := numplan.FindOverlaps(*countries, *operators)
+	return &overlaps, nil
+}
+
+// SelfCheck generates an example number for every country and range in effect and looks
+// it up the way LookupMSISDN does, leaving out porting, to confirm it resolves to the rule.
+// Rules that can't be reached are reported with their problems, see numplan.CheckRules
+func (s DefaultMSISDNService) SelfCheck() (*[]model.RuleCheck, error){
+
+	countries, err := s.repo.GetAllCountries()
+	if err != nil{
+		return nil, err
+	}
+	operators, err := s.repo.GetAllMobileOperators()
+	if err != nil{
+		return nil, err
+	}
+	at := time.Now().UTC()
+	checks := numplan.CheckRules(*countries, *operators, at)
+	for i := range checks{
+		check := &checks[i]
+		if len(check.Problems) != 0{
+			continue
+		}
+		var found string
+		if check.Kind == model.RuleKindCountry{
+			country, err := s.repo.LookupCountryCode(check.Example, at)
+			if err == nil && country.CountryIdentifier == check.CountryIdentifier{
+				continue
+			}
+			if err == nil{
+				found = "country " + country.CountryIdentifier
+			}else{
+				found = err.Error()
+			}
+		}else{
+			response, err := s.lookupPlan(check.Example, at)
+			if err == nil && response.CI == check.CountryIdentifier && response.MNO == check.MNO{
+				continue
+			}
+			if err == nil{
+				found = response.CI + " " + response.MNO
+			}else{
+				found = err.Error()
+			}
+		}
+		check.Problems = append(check.Problems, model.RuleProblemLookupMismatch)
+		check.Detail = fmt.Sprintf("The example number %s looks up to %s", check.Example, found)
+	}
+	return &checks, nil
+}
+
+// validateRule checks that a rule's pattern, and the pattern of the ranges it
+// excludes when there is one, are written in the supported dialect
+func validateRule(format string, excluded string) (error){
+
+	if err := numplan.ValidatePattern(format); err != nil{
+		return err
+	}
+	if excluded == ""{
+		return nil
+	}
+	return numplan.ValidatePattern(excluded)
+}
+
+// checkOverlaps rejects a new rule that overlaps existing ones, unless it was given
+// an explicit priority that differs from theirs and so decides which rule wins
+func checkOverlaps(overlaps []model.RuleOverlap, explicit bool) (error){
+
+	for _, overlap := range overlaps{
+		if !explicit{
+			return errs.NewRuleOverlapError(fmt.Sprintf("The rule overlaps %s, both match %s. Set a priority to add it anyway", overlap.Second, overlap.Example))
+		}
+		if overlap.FirstPriority == overlap.SecondPriority{
+			return errs.NewRuleOverlapError(fmt.Sprintf("The rule overlaps %s with the same priority, both match %s", overlap.Second, overlap.Example))
+		}
+	}
+	return nil
+}
+
+// parsePriority parses an optional rule priority, reporting whether one was given
+func parsePriority(value string) (int, bool, error){
+
+	if value == ""{
+		return 0, false, nil
+	}
+	priority, err := strconv.Atoi(value)
+	if err != nil{
+		return 0, false, err
+	}
+	return priority, true, nil
+}
+
+// effectiveLayouts are the accepted formats of effective dates, which are read as UTC
+var effectiveLayouts = []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02"}
+
+// parseTime parses an optional effective date, where empty means unbounded
+func parseTime(value string) (*time.Time, error){
+
+	if value == ""{
+		return nil, nil
+	}
+	for _, layout := range effectiveLayouts{
+		if parsed, err := time.Parse(layout, value); err == nil{
+			parsed = parsed.UTC()
+			return &parsed, nil
+		}
+	}
+	return nil, errs.NewInvalidPeriodError("Invalid date " + value + ", expected a date like 2006-01-02 or 2006-01-02T15:04")
+}
+
+// parsePeriod parses the optional start and end of a rule's effective period
+func parsePeriod(from string, to string) (*time.Time, *time.Time, error){
+
+	start, err := parseTime(from)
+	if err != nil{
+		return nil, nil, err
+	}
+	end, err := parseTime(to)
+	if err != nil{
+		return nil, nil, err
+	}
+	if start != nil && end != nil && !start.Before(*end){
+		return nil, nil, errs.NewInvalidPeriodError("The rule must take effect before it ends")
+	}
+	return start, end, nil
+}
+
+// parseEnd parses the optional time a rule ends at, which defaults to now
+func parseEnd(value string) (time.Time, error){
+
+	end, err := parseTime(value)
+	if err != nil{
+		return time.Time{}, err
+	}
+	if end == nil{
+		return time.Now().UTC(), nil
+	}
+	return *end, nil
+}
+
+// parseNetworkCodes splits a comma separated list of MCC-MNC pairs
+func parseNetworkCodes(codes string) []dto.NetworkCode{
+
+	var parsed []dto.NetworkCode
+	for _, code := range strings.Split(codes, ","){
+		mcc, mnc, ok := strings.Cut(strings.TrimSpace(code), "-")
+		if ok{
+			parsed = append(parsed, dto.NetworkCode{MCC: mcc, MNC: mnc})
+		}
+	}
+	return parsed
+}
+
+// parseCodeLength returns the length of the calling code, which is optional as it can't
+// be anything but the number of digits of the code
+func parseCodeLength(code string, value string) (int, error){
+
+	if value == ""{
+		return len(code), nil
+	}
+	length, err := strconv.Atoi(value)
+	if err != nil{
+		return 0, err
+	}
+	if length != len(code){
+		return 0, errs.NewInvalidPatternError(fmt.Sprintf("The country code length must be %d, the number of digits of the country code %s", len(code), code))
+	}
+	return length, nil
+}
+
+// parseLengths parses optional national significant number lengths, where empty means unknown
+func parseLengths(min string, max string) (int, int, error){
+
+	lengths := [2]int{}
+	for i, value := range []string{min, max}{
+		if value == ""{
+			continue
+		}
+		length, err := strconv.Atoi(value)
+		if err != nil{
+			return 0, 0, err
+		}
+		lengths[i] = length
+	}
+	return lengths[0], lengths[1], nil
+}
@@ -3,18 +3,22 @@ package service
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/robesmi/MSISDNApp/lookupcache"
 	"github.com/robesmi/MSISDNApp/mocks/repository"
 	"github.com/robesmi/MSISDNApp/mocks/vault"
 	"github.com/robesmi/MSISDNApp/model"
 	"github.com/robesmi/MSISDNApp/model/dto"
 	"github.com/robesmi/MSISDNApp/model/errs"
+	"github.com/robesmi/MSISDNApp/portability"
 )
 
 var mockMSISDNRepo *repository.MockMSISDNRepository
 var mockUserRepo *repository.MockUserRepository
 var mockVault *vault.MockVaultInterface
+var portedNumbers *portability.Store
 var lookupService MSISDNService
 var authService AuthService
 
@@ -24,7 +28,8 @@ func setup(t *testing.T) func(){
 	ctrl := gomock.NewController(t)
 	mockMSISDNRepo = repository.NewMockMSISDNRepository(ctrl)
 	mockUserRepo = repository.NewMockUserRepository(ctrl)
-	lookupService = NewMSISDNService(mockMSISDNRepo)
+	portedNumbers = portability.NewStore()
+	lookupService = NewMSISDNService(mockMSISDNRepo, portedNumbers)
 	mockVault = vault.NewMockVaultInterface(ctrl)
 	authService = ReturnAuthService(mockUserRepo, mockVault)
 
@@ -44,7 +49,7 @@ func TestNonExistantCountryNumber(t *testing.T) {
 	input := "6934567890"
 	expErr := errs.NewNumberNotFoundError()
 
-	mockMSISDNRepo.EXPECT().LookupCountryCode(input).Return(nil, expErr)
+	mockMSISDNRepo.EXPECT().LookupCountryCode(input, gomock.Any()).Return(nil, expErr)
 
 	// Act
 	_, err := lookupService.LookupMSISDN(input)
@@ -73,8 +78,8 @@ func TestNonExistantOperatorNumber(t *testing.T) {
 	}
 
 	gomock.InOrder(
-		mockMSISDNRepo.EXPECT().LookupCountryCode(input).Return(&expCountryResponse, nil),
-		mockMSISDNRepo.EXPECT().LookupMobileOperator(expCountryResponse.CountryIdentifier, nextInput).Return(nil, expErr),
+		mockMSISDNRepo.EXPECT().LookupCountryCode(input, gomock.Any()).Return(&expCountryResponse, nil),
+		mockMSISDNRepo.EXPECT().LookupMobileOperator(expCountryResponse.CountryIdentifier, nextInput, gomock.Any()).Return(nil, expErr),
 	)
 
 	// Act
@@ -103,17 +108,29 @@ func TestValidNumber(t *testing.T) {
 	expMOResponse := dto.MobileOperatorLookupResponse{
 		MNO: "A1",
 		PrefixLength: 2,
+		NumberType: model.NumberTypeMobile,
+		OperatorID: 2,
+		NetworkCodes: "294-03",
 	}
 	expFunctionResponse := dto.NumberLookupResponse{
 		MNO: "A1",
+		OperatorID: 2,
 		CC: "389",
 		SN: "123456",
 		CI: "mk",
+		Type: model.NumberTypeMobile,
 	} 
 
+	mockMSISDNRepo.EXPECT().GetCountryDetails("mk").Return(&model.CountryDetails{
+		CountryIdentifier: "mk",
+		Name: "North Macedonia",
+		Alpha3: "MKD",
+		Numeric: "807",
+		TimeZones: "Europe/Skopje",
+	}, nil)
 	gomock.InOrder(
-		mockMSISDNRepo.EXPECT().LookupCountryCode(input).Return(&expCountryResponse, nil),
-		mockMSISDNRepo.EXPECT().LookupMobileOperator(expCountryResponse.CountryIdentifier, secondInput).Return(&expMOResponse, nil),
+		mockMSISDNRepo.EXPECT().LookupCountryCode(input, gomock.Any()).Return(&expCountryResponse, nil),
+		mockMSISDNRepo.EXPECT().LookupMobileOperator(expCountryResponse.CountryIdentifier, secondInput, gomock.Any()).Return(&expMOResponse, nil),
 	)
 
 	// Act
@@ -123,6 +140,82 @@ func TestValidNumber(t *testing.T) {
 	if err != nil || response == nil || !response.Compare(expFunctionResponse){
 		t.Error("Failed while testing valid number")
 	}
+	if response != nil && (len(response.NetworkCodes) != 1 || response.NetworkCodes[0] != dto.NetworkCode{MCC: "294", MNC: "03"}){
+		t.Errorf("Error in TestValidNumber:\n expected = %s\n got = %v", "294-03", response.NetworkCodes)
+	}
+	if response != nil && (response.Country == nil || response.Country.Alpha2 != "MK" || len(response.Country.TimeZones) != 1){
+		t.Errorf("Error in TestValidNumber:\n expected = %s\n got = %+v", "the details of mk", response.Country)
+	}
+}
+
+func TestLookupMSISDNAt(t *testing.T) {
+
+	teardown := setup(t)
+	defer teardown()
+
+	// Arrange
+	input := "38977123456"
+	asOf := time.Date(2023, time.June, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	mockMSISDNRepo.EXPECT().GetCountryDetails("mk").Return(nil, errs.NewCountryNotFoundError()).AnyTimes()
+	gomock.InOrder(
+		mockMSISDNRepo.EXPECT().LookupCountryCode(input, asOf.UTC()).Return(&dto.CountryLookupResponse{CountryCode: "389", CountryIdentifier: "mk", CountryCodeLength: 3}, nil),
+		mockMSISDNRepo.EXPECT().LookupMobileOperator("mk", "77123456", asOf.UTC()).Return(&dto.MobileOperatorLookupResponse{MNO: "Telekom", PrefixLength: 2}, nil),
+	)
+
+	// Act
+	response, err := lookupService.LookupMSISDNAt(input, asOf)
+
+	//Assert
+	if err != nil{
+		t.Fatalf("Error in TestLookupMSISDNAt:\n expected = %s\n got = %s", "nil", err)
+	}
+	if response.MNO != "Telekom"{
+		t.Errorf("Error in TestLookupMSISDNAt:\n expected = %s\n got = %s", "Telekom", response.MNO)
+	}
+	if response.AsOf == nil || !response.AsOf.Equal(asOf){
+		t.Errorf("Error in TestLookupMSISDNAt:\n expected = %s\n got = %v", asOf, response.AsOf)
+	}
+}
+
+func TestPortedNumber(t *testing.T) {
+
+	teardown := setup(t)
+	defer teardown()
+
+	// Arrange
+	input := "38977123456"
+	builder := portability.NewBuilder()
+	builder.Add(input, "Telekom")
+	portedNumbers.Replace(builder)
+
+	expCountryResponse := dto.CountryLookupResponse{CountryCode: "389", CountryIdentifier: "mk", CountryCodeLength: 3}
+	expMOResponse := dto.MobileOperatorLookupResponse{MNO: "A1", PrefixLength: 2, NumberType: model.NumberTypeMobile}
+	expFunctionResponse := dto.NumberLookupResponse{
+		MNO: "Telekom",
+		OperatorID: 1,
+		CC: "389",
+		SN: "123456",
+		CI: "mk",
+		Type: model.NumberTypeMobile,
+		Ported: true,
+		RangeHolder: "A1",
+	}
+
+	mockMSISDNRepo.EXPECT().GetCountryDetails("mk").Return(nil, errs.NewCountryNotFoundError()).AnyTimes()
+	gomock.InOrder(
+		mockMSISDNRepo.EXPECT().LookupCountryCode(input, gomock.Any()).Return(&expCountryResponse, nil),
+		mockMSISDNRepo.EXPECT().LookupMobileOperator("mk", "77123456", gomock.Any()).Return(&expMOResponse, nil),
+		mockMSISDNRepo.EXPECT().GetNetworkOperatorByName("Telekom").Return(&model.NetworkOperator{ID: 1, BrandName: "Telekom", NetworkCodes: "294-01"}, nil),
+	)
+
+	// Act
+	response, err := lookupService.LookupMSISDN(input)
+
+	//Assert
+	if err != nil || response == nil || !response.Compare(expFunctionResponse){
+		t.Errorf("Error in TestPortedNumber:\n expected = %v\n got = %v", expFunctionResponse, response)
+	}
 }
 
 func TestGetAllCountries(t *testing.T) {
@@ -206,13 +299,19 @@ func TestAddNewCountryValid(t *testing.T) {
 	defer teardown()
 
 	counReq := dto.CountryRequest{
-		CountryNumberFormat: "test",
-		CountryCode: "t1",
+		CountryNumberFormat: "^1[0-9]{6}$",
+		CountryCode: "1",
 		CountryIdentifier: "tt1",
-		CountryCodeLength: "2",
+		CountryCodeLength: "1",
 	}
 
-	mockMSISDNRepo.EXPECT().AddNewCountry(counReq.CountryNumberFormat, counReq.CountryCode, counReq.CountryIdentifier, gomock.AssignableToTypeOf(2)).Return(nil)
+	mockMSISDNRepo.EXPECT().GetAllCountries().Return(&[]model.Country{}, nil)
+	mockMSISDNRepo.EXPECT().AddNewCountry(gomock.Any()).DoAndReturn(func(country *model.Country) error {
+		if country.CountryCodeLength != 1 || country.CountryIdentifier != counReq.CountryIdentifier{
+			t.Errorf("Error in TestAddNewCountryValid:\n expected = %s\n got = %v", "the requested country", country)
+		}
+		return nil
+	})
 
 
 	//Act
@@ -234,12 +333,18 @@ func TestAddNewOperatorValid(t *testing.T) {
 
 	mobileReq := dto.OperatorRequest{
 		CountryIdentifier: "test1",
-		PrefixFormat: "tt1",
-		MNO: "t1",
+		PrefixFormat: "^1[0-9]{6}$",
+		OperatorID: "1",
 		PrefixLength: "2",
 	}
 
-	mockMSISDNRepo.EXPECT().AddNewMobileOperator(mobileReq.CountryIdentifier, mobileReq.PrefixFormat, mobileReq.MNO, gomock.AssignableToTypeOf(2))
+	mockMSISDNRepo.EXPECT().GetAllMobileOperators().Return(&[]model.MobileOperator{}, nil)
+	mockMSISDNRepo.EXPECT().AddNewMobileOperator(gomock.Any()).DoAndReturn(func(operator *model.MobileOperator) error {
+		if operator.PrefixLength != 2 || operator.NumberType != model.NumberTypeMobile{
+			t.Errorf("Error in TestAddNewOperatorValid:\n expected = %s\n got = %v", "a mobile range with prefix length 2", operator)
+		}
+		return nil
+	})
 
 
 	//Act
@@ -251,4 +356,772 @@ func TestAddNewOperatorValid(t *testing.T) {
 		t.Errorf("Error in TestAddNewOperatorValid:\n expected = %s\n got = %s", "nil", err)
 	}
 	
-}
\ No newline at end of file
+}
+
+func TestAddNewCountryOverlap(t *testing.T) {
+
+	existing := []model.Country{
+		{CountryNumberFormat: "^389[0-9]{8}$", CountryCode: "389", CountryIdentifier: "mk", CountryCodeLength: 3},
+	}
+	tt := []struct{
+		Name string
+		Priority string
+		Added bool
+	}{
+		{
+			Name:		"Without a priority",
+			Priority:	"",
+			Added:		false,
+		},
+		{
+			Name:		"With the same priority",
+			Priority:	"0",
+			Added:		false,
+		},
+		{
+			Name:		"With a different priority",
+			Priority:	"1",
+			Added:		true,
+		},
+	}
+
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Arrange
+			teardown := setup(t)
+			defer teardown()
+
+			counReq := dto.CountryRequest{
+				CountryNumberFormat: "^38970[0-9]{6}$",
+				CountryCode: "389",
+				CountryIdentifier: "xk",
+				CountryCodeLength: "3",
+				Priority: test.Priority,
+			}
+			mockMSISDNRepo.EXPECT().GetAllCountries().Return(&existing, nil)
+			if test.Added{
+				mockMSISDNRepo.EXPECT().AddNewCountry(gomock.Any()).Return(nil)
+			}
+
+			//Act
+			err := lookupService.AddNewCountry(&counReq)
+
+			//Assert
+			if test.Added && err != nil{
+				t.Errorf("Error in TestAddNewCountryOverlap:\n expected = %s\n got = %s", "nil", err)
+			}
+			if _, ok := err.(*errs.RuleOverlapError); !test.Added && !ok{
+				t.Errorf("Error in TestAddNewCountryOverlap:\n expected = %s\n got = %v", "RuleOverlapError", err)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
+
+func TestAddNewOperatorInvalidPattern(t *testing.T) {
+
+	tt := []struct{
+		Name string
+		PrefixFormat string
+		ExcludedFormat string
+	}{
+		{
+			Name:			"Lookahead",
+			PrefixFormat:	"(?!^5329[0-9]{5}$)^53[0-7][0-9]{6}$",
+		},
+		{
+			Name:			"Nested repetition",
+			PrefixFormat:	"^(53[0-9]+)+$",
+		},
+		{
+			Name:			"Any character",
+			PrefixFormat:	"^53.{7}$",
+		},
+		{
+			Name:			"Invalid exclusion",
+			PrefixFormat:	"^53[0-7][0-9]{6}$",
+			ExcludedFormat:	"^5329[0-9]{5",
+		},
+	}
+
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Arrange
+			teardown := setup(t)
+			defer teardown()
+
+			mobileReq := dto.OperatorRequest{
+				CountryIdentifier: "pl",
+				PrefixFormat: test.PrefixFormat,
+				ExcludedFormat: test.ExcludedFormat,
+				OperatorID: "1",
+				PrefixLength: "2",
+			}
+
+			//Act
+			err := lookupService.AddNewMobileOperator(&mobileReq)
+
+			//Assert
+			if _, ok := err.(*errs.InvalidPatternError); !ok{
+				t.Errorf("Error in TestAddNewOperatorInvalidPattern:\n expected = %s\n got = %v", "InvalidPatternError", err)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
+
+func TestAddNewOperatorInvalidPeriod(t *testing.T) {
+
+	tt := []struct{
+		Name string
+		EffectiveFrom string
+		EffectiveTo string
+	}{
+		{
+			Name:			"Unknown date format",
+			EffectiveFrom:	"01/03/2024",
+		},
+		{
+			Name:			"Ends before it takes effect",
+			EffectiveFrom:	"2024-03-01",
+			EffectiveTo:	"2024-02-01T12:00",
+		},
+	}
+
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Arrange
+			teardown := setup(t)
+			defer teardown()
+
+			mobileReq := dto.OperatorRequest{
+				CountryIdentifier: "mk",
+				PrefixFormat: "^77[0-9]{6}$",
+				OperatorID: "1",
+				PrefixLength: "2",
+				EffectiveFrom: test.EffectiveFrom,
+				EffectiveTo: test.EffectiveTo,
+			}
+
+			//Act
+			err := lookupService.AddNewMobileOperator(&mobileReq)
+
+			//Assert
+			if _, ok := err.(*errs.InvalidPeriodError); !ok{
+				t.Errorf("Error in TestAddNewOperatorInvalidPeriod:\n expected = %s\n got = %v", "InvalidPeriodError", err)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
+
+func TestRemoveOperatorScheduled(t *testing.T) {
+
+	//Arrange
+	teardown := setup(t)
+	defer teardown()
+
+	end := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	mockMSISDNRepo.EXPECT().RemoveOperator("^77[0-9]{6}$", end).Return(nil)
+
+	//Act
+	err := lookupService.RemoveOperator("^77[0-9]{6}$", "2024-03-01")
+
+	//Assert
+	if err != nil{
+		t.Errorf("Error in TestRemoveOperatorScheduled:\n expected = %s\n got = %s", "nil", err)
+	}
+}
+
+func TestAddNewOperatorOtherCountry(t *testing.T) {
+
+	//Arrange
+	teardown := setup(t)
+	defer teardown()
+
+	mobileReq := dto.OperatorRequest{
+		CountryIdentifier: "pl",
+		PrefixFormat: "^77[0-9]{6}$",
+		OperatorID: "1",
+		PrefixLength: "2",
+	}
+	existing := []model.MobileOperator{
+		{CountryIdentifier: "mk", PrefixFormat: "^77[0-9]{6}$", MNO: "A1", PrefixLength: 2},
+	}
+	mockMSISDNRepo.EXPECT().GetAllMobileOperators().Return(&existing, nil)
+	mockMSISDNRepo.EXPECT().AddNewMobileOperator(gomock.Any()).Return(nil)
+
+	//Act
+	err := lookupService.AddNewMobileOperator(&mobileReq)
+
+	//Assert
+	if err != nil{
+		t.Errorf("Error in TestAddNewOperatorOtherCountry:\n expected = %s\n got = %s", "nil", err)
+	}
+}
+
+func TestGetOverlaps(t *testing.T) {
+
+	//Arrange
+	teardown := setup(t)
+	defer teardown()
+
+	countries := []model.Country{
+		{CountryNumberFormat: "^389[0-9]{8}$", CountryIdentifier: "mk"},
+	}
+	operators := []model.MobileOperator{
+		{CountryIdentifier: "mk", PrefixFormat: "^7[0-9]{7}$", MNO: "Telekom"},
+		{CountryIdentifier: "mk", PrefixFormat: "^77[0-9]{6}$", MNO: "A1"},
+	}
+	mockMSISDNRepo.EXPECT().GetAllCountries().Return(&countries, nil)
+	mockMSISDNRepo.EXPECT().GetAllMobileOperators().Return(&operators, nil)
+
+	//Act
+	res, err := lookupService.GetOverlaps()
+
+	//Assert
+	if err != nil{
+		t.Fatalf("Error in TestGetOverlaps:\n expected = %s\n got = %s", "nil", err)
+	}
+	if len(*res) != 1 || (*res)[0].Winner != "^77[0-9]{6}$" || (*res)[0].Example != "77000000"{
+		t.Errorf("Error in TestGetOverlaps:\n expected = %s\n got = %v", "the more specific operator winning", *res)
+	}
+}
+
+func TestGetRegion(t *testing.T) {
+
+	//Arrange
+	teardown := setup(t)
+	defer teardown()
+
+	country := model.Country{CountryCode: "389", CountryIdentifier: "mk", TrunkPrefix: "0", InternationalPrefix: "00, 011"}
+	mockMSISDNRepo.EXPECT().GetCountryByIdentifier("mk").Return(&country, nil)
+
+	//Act
+	region, err := lookupService.GetRegion("MK")
+
+	//Assert
+	if err != nil{
+		t.Fatalf("Error in TestGetRegion:\n expected = %s\n got = %s", "nil", err)
+	}
+	if region.CallingCode != "389" || region.TrunkPrefix != "0"{
+		t.Errorf("Error in TestGetRegion:\n expected = %s\n got = %v", "389 with trunk prefix 0", region)
+	}
+	if len(region.InternationalPrefixes) != 2 || region.InternationalPrefixes[1] != "011"{
+		t.Errorf("Error in TestGetRegion:\n expected = %s\n got = %v", "[00 011]", region.InternationalPrefixes)
+	}
+}
+
+func TestValidateMSISDN(t *testing.T) {
+
+	mk := model.Country{CountryCode: "389", CountryIdentifier: "mk", CountryCodeLength: 3, NSNMinLength: 8, NSNMaxLength: 8}
+	at := model.Country{CountryCode: "43", CountryIdentifier: "at", CountryCodeLength: 2}
+
+	tt := []struct{
+		Name string
+		Input string
+		LookupErr error
+		Country *model.Country
+		ExpectedVerdict string
+	}{
+		{
+			Name:				"Unknown country code",
+			Input:				"6934567890",
+			LookupErr:			errs.NewNumberNotFoundError(),
+			ExpectedVerdict:	model.ValidityInvalidCountryCode,
+		},
+		{
+			Name:				"Too short",
+			Input:				"3897712345",
+			LookupErr:			errs.NewNumberNotFoundError(),
+			Country:			&mk,
+			ExpectedVerdict:	model.ValidityTooShort,
+		},
+		{
+			Name:				"Too long",
+			Input:				"389771234567",
+			LookupErr:			errs.NewNumberNotFoundError(),
+			Country:			&mk,
+			ExpectedVerdict:	model.ValidityTooLong,
+		},
+		{
+			Name:				"Unknown range",
+			Input:				"38979123456",
+			LookupErr:			errs.NewNoCarriersFoundError(),
+			Country:			&mk,
+			ExpectedVerdict:	model.ValidityUnknownRange,
+		},
+		{
+			Name:				"Unknown lengths",
+			Input:				"4312345",
+			LookupErr:			errs.NewNumberNotFoundError(),
+			Country:			&at,
+			ExpectedVerdict:	model.ValidityPossiblyValid,
+		},
+	}
+
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Arrange
+			teardown := setup(t)
+			defer teardown()
+
+			if _, ok := test.LookupErr.(*errs.NoCarriersFoundError); ok{
+				mockMSISDNRepo.EXPECT().LookupCountryCode(test.Input, gomock.Any()).Return(&dto.CountryLookupResponse{CountryCode: "389", CountryIdentifier: "mk", CountryCodeLength: 3}, nil)
+				mockMSISDNRepo.EXPECT().LookupMobileOperator("mk", test.Input[3:], gomock.Any()).Return(nil, test.LookupErr)
+			}else{
+				mockMSISDNRepo.EXPECT().LookupCountryCode(test.Input, gomock.Any()).Return(nil, test.LookupErr)
+			}
+			if test.Country != nil{
+				mockMSISDNRepo.EXPECT().LookupCallingCode(test.Input, gomock.Any()).Return(test.Country, nil)
+			}else{
+				mockMSISDNRepo.EXPECT().LookupCallingCode(test.Input, gomock.Any()).Return(nil, errs.NewCountryNotFoundError())
+			}
+
+			//Act
+			resp, err := lookupService.ValidateMSISDN(test.Input)
+
+			//Assert
+			if err != nil{
+				t.Fatalf("Error in TestValidateMSISDN:\n expected = %s\n got = %s", "nil", err)
+			}
+			if resp.Verdict != test.ExpectedVerdict{
+				t.Errorf("Error in TestValidateMSISDN:\n expected = %s\n got = %s", test.ExpectedVerdict, resp.Verdict)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
+
+func TestValidateValidMSISDN(t *testing.T) {
+
+	//Arrange
+	teardown := setup(t)
+	defer teardown()
+
+	input := "38977123456"
+	mockMSISDNRepo.EXPECT().LookupCountryCode(input, gomock.Any()).Return(&dto.CountryLookupResponse{CountryCode: "389", CountryIdentifier: "mk", CountryCodeLength: 3}, nil)
+	mockMSISDNRepo.EXPECT().LookupMobileOperator("mk", "77123456", gomock.Any()).Return(&dto.MobileOperatorLookupResponse{MNO: "A1", PrefixLength: 2}, nil)
+	mockMSISDNRepo.EXPECT().GetCountryDetails("mk").Return(nil, errs.NewCountryNotFoundError()).AnyTimes()
+
+	//Act
+	resp, err := lookupService.ValidateMSISDN(input)
+
+	//Assert
+	if err != nil || resp.Verdict != model.ValidityValid || resp.Result == nil || resp.Result.MNO != "A1"{
+		t.Errorf("Error in TestValidateValidMSISDN:\n expected = %s\n got = %v, %v", "a valid A1 number", resp, err)
+	}
+}
+
+func TestValidateMSISDNAt(t *testing.T) {
+
+	//Arrange
+	teardown := setup(t)
+	defer teardown()
+
+	input := "38977123456"
+	at := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	country := model.Country{CountryCode: "389", CountryIdentifier: "mk", NSNMinLength: 8, NSNMaxLength: 8}
+	mockMSISDNRepo.EXPECT().LookupCountryCode(input, at).Return(&dto.CountryLookupResponse{CountryCode: "389", CountryIdentifier: "mk", CountryCodeLength: 3}, nil)
+	mockMSISDNRepo.EXPECT().LookupMobileOperator("mk", "77123456", at).Return(nil, errs.NewNoCarriersFoundError())
+	mockMSISDNRepo.EXPECT().LookupCallingCode(input, at).Return(&country, nil)
+
+	//Act
+	resp, err := lookupService.ValidateMSISDNAt(input, at)
+
+	//Assert
+	if err != nil || resp.Verdict != model.ValidityUnknownRange{
+		t.Errorf("Error in TestValidateMSISDNAt:\n expected = %s\n got = %v, %v", model.ValidityUnknownRange, resp, err)
+	}
+}
+
+func TestAddNewNetworkOperator(t *testing.T) {
+
+	//Arrange
+	teardown := setup(t)
+	defer teardown()
+
+	operatorReq := dto.NetworkOperatorRequest{
+		BrandName: "CTT",
+		NetworkCodes: "268-11, 268-12",
+		HostOperatorID: "3",
+	}
+	mockMSISDNRepo.EXPECT().AddNewNetworkOperator(gomock.Any()).DoAndReturn(func(operator *model.NetworkOperator) error {
+		if operator.Status != model.OperatorStatusActive || operator.LegalName != "CTT" || operator.NetworkCodes != "268-11,268-12" || operator.HostOperatorID != 3{
+			t.Errorf("Error in TestAddNewNetworkOperator:\n expected = %s\n got = %v", "an active MVNO hosted on operator 3", operator)
+		}
+		return nil
+	})
+
+	//Act
+	err := lookupService.AddNewNetworkOperator(&operatorReq)
+
+	//Assert
+	if err != nil{
+		t.Errorf("Error in TestAddNewNetworkOperator:\n expected = %s\n got = %s", "nil", err)
+	}
+}
+
+// expectPlanLookup expects the plan lookup of 38977123456 the given number of times
+func expectPlanLookup(times int){
+
+	mockMSISDNRepo.EXPECT().LookupCountryCode("38977123456", gomock.Any()).Return(&dto.CountryLookupResponse{
+		CountryCode: "389",
+		CountryIdentifier: "mk",
+		CountryCodeLength: 3,
+	}, nil).Times(times)
+	mockMSISDNRepo.EXPECT().LookupMobileOperator("mk", "77123456", gomock.Any()).Return(&dto.MobileOperatorLookupResponse{
+		MNO: "A1",
+		PrefixLength: 2,
+	}, nil).Times(times)
+	mockMSISDNRepo.EXPECT().GetCountryDetails("mk").Return(nil, errs.NewCountryNotFoundError()).AnyTimes()
+}
+
+func TestCachedLookup(t *testing.T) {
+
+	teardown := setup(t)
+	defer teardown()
+
+	//Arrange
+	cache := lookupcache.New(10, time.Minute, time.Second)
+	cachedService := NewCachedMSISDNService(mockMSISDNRepo, portedNumbers, cache)
+	expectPlanLookup(1)
+	mockMSISDNRepo.EXPECT().GetNetworkOperatorByName("Telekom").Return(nil, errs.NewOperatorNotFoundError())
+
+	//Act
+	first, firstErr := cachedService.LookupMSISDN("38977123456")
+	portedNumbers.Apply([]portability.Change{{MSISDN: "38977123456", MNO: "Telekom"}})
+	second, secondErr := cachedService.LookupMSISDN("38977123456")
+
+	//Assert
+	if firstErr != nil || secondErr != nil || first.MNO != "A1"{
+		t.Fatalf("Error in TestCachedLookup:\n expected %s\n got %v, %v", "two lookups without errors", firstErr, secondErr)
+	}
+	if !second.Ported || second.MNO != "Telekom" || second.RangeHolder != "A1"{
+		t.Errorf("Error in TestCachedLookup:\n expected %s\n got %+v", "porting applied to the cached result", second)
+	}
+	if stats := cachedService.GetCacheStats(); stats.Hits != 1 || stats.Misses != 1{
+		t.Errorf("Error in TestCachedLookup:\n expected %s\n got %+v", "1 hit and 1 miss", stats)
+	}
+}
+
+func TestCachedLookupInvalidatedByAddNewCountry(t *testing.T) {
+
+	teardown := setup(t)
+	defer teardown()
+
+	//Arrange
+	cachedService := NewCachedMSISDNService(mockMSISDNRepo, portedNumbers, lookupcache.New(10, time.Minute, time.Second))
+	expectPlanLookup(2)
+	mockMSISDNRepo.EXPECT().GetAllCountries().Return(&[]model.Country{}, nil)
+	mockMSISDNRepo.EXPECT().AddNewCountry(gomock.Any()).Return(nil)
+
+	//Act
+	cachedService.LookupMSISDN("38977123456")
+	addErr := cachedService.AddNewCountry(&dto.CountryRequest{
+		CountryNumberFormat: "^1[0-9]{6}$",
+		CountryCode: "1",
+		CountryIdentifier: "tt1",
+		CountryCodeLength: "1",
+	})
+	_, err := cachedService.LookupMSISDN("38977123456")
+
+	//Assert
+	if addErr != nil || err != nil{
+		t.Errorf("Error in TestCachedLookupInvalidatedByAddNewCountry:\n expected %s\n got %v, %v", "nil", addErr, err)
+	}
+	if stats := cachedService.GetCacheStats(); stats.Hits != 0 || stats.Invalidations != 1{
+		t.Errorf("Error in TestCachedLookupInvalidatedByAddNewCountry:\n expected %s\n got %+v", "no hits and 1 invalidation", stats)
+	}
+}
+
+func TestCachedLookupNotFound(t *testing.T) {
+
+	teardown := setup(t)
+	defer teardown()
+
+	//Arrange
+	cachedService := NewCachedMSISDNService(mockMSISDNRepo, portedNumbers, lookupcache.New(10, time.Minute, time.Second))
+	mockMSISDNRepo.EXPECT().LookupCountryCode("6934567890", gomock.Any()).Return(nil, errs.NewNumberNotFoundError()).Times(1)
+
+	//Act
+	_, firstErr := cachedService.LookupMSISDN("6934567890")
+	_, secondErr := cachedService.LookupMSISDN("6934567890")
+
+	//Assert
+	_, firstNotFound := firstErr.(*errs.NumberNotFoundError)
+	_, secondNotFound := secondErr.(*errs.NumberNotFoundError)
+	if !firstNotFound || !secondNotFound{
+		t.Errorf("Error in TestCachedLookupNotFound:\n expected %s\n got %v, %v", "NumberNotFoundError twice", firstErr, secondErr)
+	}
+	if cachedService.GetCacheStats().NegativeHits != 1{
+		t.Errorf("Error in TestCachedLookupNotFound:\n expected %d\n got %d", 1, cachedService.GetCacheStats().NegativeHits)
+	}
+}
+
+func TestGetCountryDetails(t *testing.T) {
+
+	teardown := setup(t)
+	defer teardown()
+
+	//Arrange
+	austria := model.CountryDetails{CountryIdentifier: "at", Name: "Austria", Alpha3: "AUT", Numeric: "040"}
+	mockMSISDNRepo.EXPECT().GetCountryDetails("at").Return(&austria, nil)
+	mockMSISDNRepo.EXPECT().GetAllCountryDetails().Return(&[]model.CountryDetails{austria}, nil).AnyTimes()
+
+	tests := []struct{
+		Code string
+		Found bool
+	}{
+		{"AT", true},
+		{"aut", true},
+		{"040", true},
+		{"40", false},
+		{"DEU", false},
+	}
+
+	for _, test := range tests{
+		fn := func(t *testing.T){
+
+			//Act
+			details, err := lookupService.GetCountryDetails(test.Code)
+
+			//Assert
+			if test.Found && (err != nil || details.Alpha2 != "AT"){
+				t.Errorf("Error in TestGetCountryDetails:\n expected %s\n got %v, %v", "AT", details, err)
+			}
+			if _, ok := err.(*errs.CountryNotFoundError); !test.Found && !ok{
+				t.Errorf("Error in TestGetCountryDetails:\n expected %s\n got %v", "CountryNotFoundError", err)
+			}
+		}
+		t.Run(test.Code, fn)
+	}
+}
+
+func TestSaveCountryDetails(t *testing.T) {
+
+	teardown := setup(t)
+	defer teardown()
+
+	//Arrange
+	mockMSISDNRepo.EXPECT().SaveCountryDetails(&model.CountryDetails{
+		CountryIdentifier: "pt",
+		Name: "Portugal",
+		Alpha3: "PRT",
+		Numeric: "620",
+		TimeZones: "Europe/Lisbon,Atlantic/Madeira",
+		Currency: "EUR",
+	}).Return(nil)
+
+	//Act
+	err := lookupService.SaveCountryDetails(&dto.CountryDetailsRequest{
+		CountryIdentifier: "PT", Name: "Portugal", Alpha3: "prt", Numeric: "620", TimeZones: "Europe/Lisbon, Atlantic/Madeira", Currency: "eur",
+	})
+	invalidErr := lookupService.SaveCountryDetails(&dto.CountryDetailsRequest{
+		CountryIdentifier: "pt", Name: "Portugal", Alpha3: "PRT", Numeric: "62",
+	})
+
+	//Assert
+	if err != nil{
+		t.Errorf("Error in TestSaveCountryDetails:\n expected %s\n got %s", "nil", err)
+	}
+	if _, ok := invalidErr.(*errs.InvalidCountryDetailsError); !ok{
+		t.Errorf("Error in TestSaveCountryDetails:\n expected %s\n got %v", "InvalidCountryDetailsError", invalidErr)
+	}
+}
+
+func TestAddNewCountryCallingCode(t *testing.T) {
+
+	tests := []struct{
+		Name string
+		Format string
+		Code string
+		CodeLength string
+	}{
+		{Name: "area code as the calling code", Format: "^1246[0-9]{7}$", Code: "1246", CodeLength: "3"},
+		{Name: "length of another code", Format: "^1246[0-9]{7}$", Code: "1", CodeLength: "3"},
+		{Name: "pattern of another code", Format: "^48[0-9]{9}$", Code: "389"},
+	}
+
+	for _, test := range tests{
+		fn := func(t *testing.T){
+
+			//Arrange
+			teardown := setup(t)
+			defer teardown()
+
+			//Act
+			err := lookupService.AddNewCountry(&dto.CountryRequest{
+				CountryNumberFormat: test.Format,
+				CountryCode: test.Code,
+				CountryIdentifier: "bb",
+				CountryCodeLength: test.CodeLength,
+			})
+
+			//Assert
+			if _, ok := err.(*errs.InvalidPatternError); !ok{
+				t.Errorf("Error in TestAddNewCountryCallingCode:\n expected %s\n got %v", "InvalidPatternError", err)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
+
+func TestSharedCallingCodeNumber(t *testing.T) {
+
+	teardown := setup(t)
+	defer teardown()
+
+	//Arrange
+	mockMSISDNRepo.EXPECT().LookupCountryCode("12462301234", gomock.Any()).Return(&dto.CountryLookupResponse{
+		CountryCode: "1",
+		CountryIdentifier: "bb",
+		CountryCodeLength: 1,
+	}, nil)
+	mockMSISDNRepo.EXPECT().LookupMobileOperator("bb", "2462301234", gomock.Any()).Return(&dto.MobileOperatorLookupResponse{
+		MNO: "Liberty Latin America",
+		PrefixLength: 6,
+	}, nil)
+	mockMSISDNRepo.EXPECT().GetCountryDetails("bb").Return(nil, errs.NewCountryNotFoundError())
+
+	//Act
+	response, err := lookupService.LookupMSISDN("12462301234")
+
+	//Assert
+	if err != nil{
+		t.Fatalf("Error in TestSharedCallingCodeNumber:\n expected %s\n got %s", "nil", err)
+	}
+	if response.CC != "1" || response.CI != "bb" || response.SN != "1234"{
+		t.Errorf("Error in TestSharedCallingCodeNumber:\n expected %s\n got %s %s %s", "1 bb 1234", response.CC, response.CI, response.SN)
+	}
+}
+
+func TestSelfCheck(t *testing.T) {
+
+	teardown := setup(t)
+	defer teardown()
+
+	//Arrange
+	mockMSISDNRepo.EXPECT().GetAllCountries().Return(&[]model.Country{
+		{CountryNumberFormat: "^389[0-9]{8}$", CountryCode: "389", CountryIdentifier: "mk", CountryCodeLength: 3},
+	}, nil)
+	mockMSISDNRepo.EXPECT().GetAllMobileOperators().Return(&[]model.MobileOperator{
+		{CountryIdentifier: "mk", PrefixFormat: "^77[0-9]{6}$", MNO: "A1"},
+		{CountryIdentifier: "mk", PrefixFormat: "^78[0-9]{7}$", MNO: "Telekom"},
+	}, nil)
+	mockMSISDNRepo.EXPECT().LookupCountryCode(gomock.Any(), gomock.Any()).Return(&dto.CountryLookupResponse{
+		CountryCode: "389",
+		CountryIdentifier: "mk",
+		CountryCodeLength: 3,
+	}, nil).Times(2)
+	mockMSISDNRepo.EXPECT().LookupMobileOperator("mk", gomock.Any(), gomock.Any()).Return(&dto.MobileOperatorLookupResponse{MNO: "Vip", PrefixLength: 2}, nil)
+	mockMSISDNRepo.EXPECT().GetCountryDetails("mk").Return(nil, errs.NewCountryNotFoundError()).AnyTimes()
+
+	//Act
+	checks, err := lookupService.SelfCheck()
+
+	//Assert
+	if err != nil || len(*checks) != 3{
+		t.Fatalf("Error in TestSelfCheck:\n expected %s\n got %v, %v", "3 checks", checks, err)
+	}
+	expected := [][]string{nil, {model.RuleProblemLookupMismatch}, {model.RuleProblemOutsideCountry, model.RuleProblemUnreachable}}
+	for i, check := range *checks{
+		if len(check.Problems) != len(expected[i]) || (len(expected[i]) > 0 && check.Problems[0] != expected[i][0]){
+			t.Errorf("Error in TestSelfCheck:\n expected %s to have problems %v\n got %v", check.Pattern, expected[i], check.Problems)
+		}
+	}
+}
+
+func TestLookupPartial(t *testing.T) {
+
+	teardown := setup(t)
+	defer teardown()
+
+	//Arrange
+	mockMSISDNRepo.EXPECT().LookupPartial("38977", gomock.Any()).Return(&model.PartialMatch{
+		MinRemaining: 6,
+		MaxRemaining: 6,
+		Countries: []model.PartialCountry{
+			{Country: model.Country{CountryCode: "389", CountryIdentifier: "mk"}, MinRemaining: 6, MaxRemaining: 6},
+		},
+		Operators: []model.PartialOperator{
+			{Operator: model.MobileOperator{CountryIdentifier: "mk", MNO: "A1", OperatorID: 2, NumberType: model.NumberTypeMobile}, MinRemaining: 6, MaxRemaining: 6},
+		},
+	}, nil)
+
+	//Act
+	response, err := lookupService.LookupPartial("38977")
+
+	//Assert
+	if err != nil{
+		t.Fatalf("Error in TestLookupPartial:\n expected %s\n got %s", "nil", err)
+	}
+	if response.Complete || response.MinRemaining != 6 || response.MaxRemaining != 6{
+		t.Errorf("Error in TestLookupPartial:\n expected %s\n got %v", "6 remaining digits", response)
+	}
+	if len(response.Countries) != 1 || response.Countries[0].CountryCode != "389" ||
+		len(response.Operators) != 1 || response.Operators[0].MNO != "A1" || response.Operators[0].OperatorID != 2{
+		t.Errorf("Error in TestLookupPartial:\n expected %s\n got %v", "mk and A1", response)
+	}
+}
+
+func TestLookupPartialAt(t *testing.T) {
+
+	teardown := setup(t)
+	defer teardown()
+
+	//Arrange
+	at := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockMSISDNRepo.EXPECT().LookupPartial("38977", at).Return(&model.PartialMatch{MinRemaining: 6, MaxRemaining: 6}, nil)
+
+	//Act
+	response, err := lookupService.LookupPartialAt("38977", at)
+
+	//Assert
+	if err != nil || response.AsOf == nil || !response.AsOf.Equal(at){
+		t.Errorf("Error in TestLookupPartialAt:\n expected %s\n got %v, %v", "a partial lookup as of 2020", response, err)
+	}
+}
+
+func TestExtractMSISDNs(t *testing.T) {
+
+	teardown := setup(t)
+	defer teardown()
+
+	//Arrange
+	country := model.Country{CountryCode: "389", CountryIdentifier: "mk", TrunkPrefix: "0", InternationalPrefix: "00"}
+	mockMSISDNRepo.EXPECT().GetCountryByIdentifier("mk").Return(&country, nil)
+	mockMSISDNRepo.EXPECT().LookupCountryCode("38970123456", gomock.Any()).Return(&dto.CountryLookupResponse{
+		CountryCode: "389",
+		CountryIdentifier: "mk",
+		CountryCodeLength: 3,
+	}, nil)
+	mockMSISDNRepo.EXPECT().LookupMobileOperator("mk", "70123456", gomock.Any()).Return(&dto.MobileOperatorLookupResponse{
+		MNO: "Telekom",
+		PrefixLength: 2,
+	}, nil)
+	mockMSISDNRepo.EXPECT().GetCountryDetails("mk").Return(nil, errs.NewCountryNotFoundError())
+	mockMSISDNRepo.EXPECT().LookupCountryCode("48510123456", gomock.Any()).Return(nil, errs.NewNumberNotFoundError())
+
+	//Act
+	response, err := lookupService.ExtractMSISDNs("Call me on 070 123 456 after 5, or on +48 510 123 456", "mk")
+
+	//Assert
+	if err != nil{
+		t.Fatalf("Error in TestExtractMSISDNs:\n expected %s\n got %s", "nil", err)
+	}
+	if len(response.Numbers) != 2{
+		t.Fatalf("Error in TestExtractMSISDNs:\n expected %s\n got %v", "2 numbers", response.Numbers)
+	}
+	first, second := response.Numbers[0], response.Numbers[1]
+	if first.Text != "070 123 456" || first.Start != 11 || first.End != 22 || first.Result == nil || first.Result.MNO != "Telekom"{
+		t.Errorf("Error in TestExtractMSISDNs:\n expected %s\n got %+v", "070 123 456 at 11-22 of Telekom", first)
+	}
+	if second.Number != "48510123456" || second.Result != nil || second.Error == ""{
+		t.Errorf("Error in TestExtractMSISDNs:\n expected %s\n got %+v", "48510123456 not found", second)
+	}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/robesmi/MSISDNApp/model/dto"
+)
+
+// formatNumber takes a calling code, a national significant number, the trunk prefix and
+// number grouping of its country and returns the number in every supported format
+func formatNumber(cc string, nsn string, trunkPrefix string, grouping string) dto.NumberFormats{
+
+	grouped := groupDigits(nsn, grouping)
+	return dto.NumberFormats{
+		E164: "+" + cc + nsn,
+		International: "+" + cc + " " + grouped,
+		National: trunkPrefix + grouped,
+		RFC3966: "tel:+" + cc + "-" + visualSeparators(grouped),
+	}
+}
+
+// groupDigits lays out the digits with the first grouping pattern that applies to the
+// number, or returns them as they are if none does
+func groupDigits(nsn string, grouping string) string{
+
+	for _, entry := range strings.Split(grouping, ";"){
+		pattern := strings.TrimSpace(entry)
+		if prefix, rest, ok := strings.Cut(pattern, ":"); ok{
+			if !strings.HasPrefix(nsn, prefix){
+				continue
+			}
+			pattern = rest
+		}
+		if pattern == "" || strings.Count(pattern, "X") != len(nsn){
+			continue
+		}
+
+		var grouped strings.Builder
+		digit := 0
+		for _, r := range pattern{
+			if r == 'X'{
+				grouped.WriteByte(nsn[digit])
+				digit++
+			}else{
+				grouped.WriteRune(r)
+			}
+		}
+		return grouped.String()
+	}
+	return nsn
+}
+
+// visualSeparators replaces the separators between groups of digits with the
+// hyphens used in tel URIs
+func visualSeparators(grouped string) string{
+
+	var uri strings.Builder
+	separated := false
+	for _, r := range grouped{
+		if r >= '0' && r <= '9'{
+			if separated && uri.Len() != 0{
+				uri.WriteByte('-')
+			}
+			uri.WriteRune(r)
+			separated = false
+		}else{
+			separated = true
+		}
+	}
+	return uri.String()
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/robesmi/MSISDNApp/model/dto"
+)
+
+func TestFormatNumber(t *testing.T) {
+
+	tt := []struct{
+		Name string
+		CC string
+		NSN string
+		TrunkPrefix string
+		Grouping string
+		Expected dto.NumberFormats
+	}{
+		{
+			Name:			"Mobile number",
+			CC:				"389",
+			NSN:			"70123456",
+			TrunkPrefix:	"0",
+			Grouping:		"2:X XXX XXXX;XX XXX XXX",
+			Expected:		dto.NumberFormats{
+				E164:			"+38970123456",
+				International:	"+389 70 123 456",
+				National:		"070 123 456",
+				RFC3966:		"tel:+389-70-123-456",
+			},
+		},
+		{
+			Name:			"Pattern limited to a prefix",
+			CC:				"389",
+			NSN:			"23123456",
+			TrunkPrefix:	"0",
+			Grouping:		"2:X XXX XXXX;XX XXX XXX",
+			Expected:		dto.NumberFormats{
+				E164:			"+38923123456",
+				International:	"+389 2 312 3456",
+				National:		"02 312 3456",
+				RFC3966:		"tel:+389-2-312-3456",
+			},
+		},
+		{
+			Name:			"No pattern with a matching length",
+			CC:				"48",
+			NSN:			"5101234567",
+			Grouping:		"XXX XXX XXX",
+			Expected:		dto.NumberFormats{
+				E164:			"+485101234567",
+				International:	"+48 5101234567",
+				National:		"5101234567",
+				RFC3966:		"tel:+48-5101234567",
+			},
+		},
+	}
+
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Act
+			formats := formatNumber(test.CC, test.NSN, test.TrunkPrefix, test.Grouping)
+
+			//Assert
+			if formats != test.Expected{
+				t.Errorf("Error in TestFormatNumber:\n expected %v\n got %v", test.Expected, formats)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
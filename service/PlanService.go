@@ -0,0 +1,406 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/numplan"
+	"github.com/robesmi/MSISDNApp/planfile"
+	"github.com/robesmi/MSISDNApp/repository"
+)
+
+var (
+	countryCodeFormat = regexp.MustCompile(`^\d{1,6}$`)
+	countryIdentifierFormat = regexp.MustCompile(`^[a-z]{2}$`)
+	trunkPrefixFormat = regexp.MustCompile(`^\d{0,4}$`)
+	internationalPrefixFormat = regexp.MustCompile(`^\d{1,4}(,\d{1,4})*$`)
+	numberGroupingFormat = regexp.MustCompile(`^[X0-9 :;().\-/]*$`)
+)
+
+// DefaultPlanService imports and exports the numbering plan as a whole
+type DefaultPlanService struct {
+	repo repository.MSISDNRepository
+}
+
+func NewPlanService(repo repository.MSISDNRepository) DefaultPlanService{
+	return DefaultPlanService{repo}
+}
+
+//go:generate mockgen -destination=../mocks/service/mockPlanService.go -package=service github.com/robesmi/MSISDNApp/service PlanService
+type PlanService interface {
+	// ImportPlan reads a numbering plan file in the format and compares its rules with the
+	// saved ones, returning what was added, changed, removed and which rows are invalid.
+	// The file holds the whole plan, so saved rules missing from it are removed, except
+	// for the ones with the pattern of an invalid row. When apply is set the changes of
+	// the valid rows are saved in a single transaction
+	ImportPlan(io.Reader, string, bool) (*model.PlanDiff, error)
+	// ExportPlan writes every saved rule, including the expired and scheduled ones, as a
+	// numbering plan file in the format, which imports again without changes
+	ExportPlan(io.Writer, string) (error)
+}
+
+// planKey identifies a rule across imports: countries by their pattern and operator
+// ranges by their country and pattern, along with when they take effect
+func planKey(kind string, ci string, pattern string, from *time.Time) string{
+
+	key := kind + "|" + pattern
+	if kind == model.RuleKindOperator{
+		key = kind + "|" + ci + "|" + pattern
+	}
+	if from != nil{
+		key += "|" + from.UTC().Format(time.RFC3339)
+	}
+	return key
+}
+
+func (s DefaultPlanService) ImportPlan(file io.Reader, format string, apply bool) (*model.PlanDiff, error){
+
+	records, err := planfile.Read(file, format)
+	if err != nil{
+		return nil, err
+	}
+	savedCountries, err := s.repo.GetAllCountries()
+	if err != nil{
+		return nil, err
+	}
+	savedOperators, err := s.repo.GetAllMobileOperators()
+	if err != nil{
+		return nil, err
+	}
+	networkOperators, err := s.repo.GetAllNetworkOperators()
+	if err != nil{
+		return nil, err
+	}
+	known := knownOperators{
+		ids: make(map[string]int, len(*networkOperators)),
+		names: make(map[int]string, len(*networkOperators)),
+	}
+	for _, operator := range *networkOperators{
+		known.ids[operator.BrandName] = operator.ID
+		known.names[operator.ID] = operator.BrandName
+	}
+
+	var diff model.PlanDiff
+	var update model.PlanUpdate
+	invalid := func(record planfile.Record, ci string, pattern string, from *time.Time, reason error){
+		diff.Changes = append(diff.Changes, model.PlanChange{
+			Change: model.PlanChangeInvalid,
+			Kind: record.Kind,
+			Row: record.Row,
+			CountryIdentifier: ci,
+			Pattern: pattern,
+			EffectiveFrom: from,
+			Details: reason.Error(),
+		})
+	}
+	// protected holds the patterns of invalid rows, whose saved rules are kept as they are
+	protected := make(map[string]bool)
+	rows := make(map[string]int)
+
+	saved := make(map[string]model.Country, len(*savedCountries))
+	for _, country := range *savedCountries{
+		saved[planKey(model.RuleKindCountry, "", country.CountryNumberFormat, country.EffectiveFrom)] = country
+	}
+	var countries []model.Country
+	countryIdentifiers := make(map[string]bool)
+	for _, record := range records{
+		if record.Kind == model.RuleKindOperator{
+			continue
+		}
+		c := record.Country
+		key := planKey(model.RuleKindCountry, "", c.CountryNumberFormat, c.EffectiveFrom)
+		if record.Err == nil{
+			if row, ok := rows[key]; ok{
+				record.Err = fmt.Errorf("the rule is a duplicate of row %d", row)
+			}else{
+				record.Err = validateImportedCountry(&c)
+			}
+		}
+		if record.Err != nil{
+			protected[planKey(model.RuleKindCountry, "", c.CountryNumberFormat, nil)] = true
+			invalid(record, c.CountryIdentifier, c.CountryNumberFormat, c.EffectiveFrom, record.Err)
+			continue
+		}
+		rows[key] = record.Row
+		countryIdentifiers[c.CountryIdentifier] = true
+		countries = append(countries, c)
+
+		previous, ok := saved[key]
+		if !ok{
+			update.AddedCountries = append(update.AddedCountries, c)
+			diff.Changes = append(diff.Changes, planChange(model.PlanChangeAdded, record, c.CountryIdentifier, c.CountryNumberFormat, c.EffectiveFrom, ""))
+			continue
+		}
+		delete(saved, key)
+		if details := countryChanges(previous, c); details != ""{
+			c.ID = previous.ID
+			update.ChangedCountries = append(update.ChangedCountries, c)
+			diff.Changes = append(diff.Changes, planChange(model.PlanChangeChanged, record, c.CountryIdentifier, c.CountryNumberFormat, c.EffectiveFrom, details))
+		}
+	}
+
+	savedRanges := make(map[string]model.MobileOperator, len(*savedOperators))
+	for _, operator := range *savedOperators{
+		savedRanges[planKey(model.RuleKindOperator, operator.CountryIdentifier, operator.PrefixFormat, operator.EffectiveFrom)] = operator
+	}
+	var operators []model.MobileOperator
+	for _, record := range records{
+		if record.Kind != model.RuleKindOperator{
+			continue
+		}
+		o := record.Operator
+		key := planKey(model.RuleKindOperator, o.CountryIdentifier, o.PrefixFormat, o.EffectiveFrom)
+		if record.Err == nil{
+			if row, ok := rows[key]; ok{
+				record.Err = fmt.Errorf("the rule is a duplicate of row %d", row)
+			}else{
+				record.Err = validateImportedOperator(&o, countryIdentifiers, known)
+			}
+		}
+		if record.Err != nil{
+			protected[planKey(model.RuleKindOperator, o.CountryIdentifier, o.PrefixFormat, nil)] = true
+			invalid(record, o.CountryIdentifier, o.PrefixFormat, o.EffectiveFrom, record.Err)
+			continue
+		}
+		rows[key] = record.Row
+		operators = append(operators, o)
+
+		previous, ok := savedRanges[key]
+		if !ok{
+			update.AddedOperators = append(update.AddedOperators, o)
+			diff.Changes = append(diff.Changes, planChange(model.PlanChangeAdded, record, o.CountryIdentifier, o.PrefixFormat, o.EffectiveFrom, ""))
+			continue
+		}
+		delete(savedRanges, key)
+		if details := operatorChanges(previous, o); details != ""{
+			o.ID = previous.ID
+			update.ChangedOperators = append(update.ChangedOperators, o)
+			diff.Changes = append(diff.Changes, planChange(model.PlanChangeChanged, record, o.CountryIdentifier, o.PrefixFormat, o.EffectiveFrom, details))
+		}
+	}
+
+	// What's left of the saved rules is missing from the file, listed in the order they were saved
+	for _, country := range *savedCountries{
+		if _, ok := saved[planKey(model.RuleKindCountry, "", country.CountryNumberFormat, country.EffectiveFrom)]; !ok{
+			continue
+		}
+		if protected[planKey(model.RuleKindCountry, "", country.CountryNumberFormat, nil)]{
+			countries = append(countries, country)
+			continue
+		}
+		update.RemovedCountries = append(update.RemovedCountries, country.ID)
+		diff.Changes = append(diff.Changes, planChange(model.PlanChangeRemoved, planfile.Record{Kind: model.RuleKindCountry}, country.CountryIdentifier, country.CountryNumberFormat, country.EffectiveFrom, ""))
+	}
+	for _, operator := range *savedOperators{
+		if _, ok := savedRanges[planKey(model.RuleKindOperator, operator.CountryIdentifier, operator.PrefixFormat, operator.EffectiveFrom)]; !ok{
+			continue
+		}
+		if protected[planKey(model.RuleKindOperator, operator.CountryIdentifier, operator.PrefixFormat, nil)]{
+			operators = append(operators, operator)
+			continue
+		}
+		update.RemovedOperators = append(update.RemovedOperators, operator.ID)
+		diff.Changes = append(diff.Changes, planChange(model.PlanChangeRemoved, planfile.Record{Kind: model.RuleKindOperator}, operator.CountryIdentifier, operator.PrefixFormat, operator.EffectiveFrom, ""))
+	}
+
+	for _, change := range diff.Changes{
+		switch change.Change{
+		case model.PlanChangeAdded:
+			diff.Added++
+		case model.PlanChangeChanged:
+			diff.Changed++
+		case model.PlanChangeRemoved:
+			diff.Removed++
+		case model.PlanChangeInvalid:
+			diff.Invalid++
+		}
+	}
+	diff.Overlaps = numplan.FindOverlaps(countries, operators)
+
+	if !apply{
+		return &diff, nil
+	}
+	if diff.Added + diff.Changed + diff.Removed > 0{
+		if err := s.repo.UpdatePlan(&update); err != nil{
+			return nil, err
+		}
+	}
+	diff.Applied = true
+	return &diff, nil
+}
+
+func (s DefaultPlanService) ExportPlan(file io.Writer, format string) (error){
+
+	countries, err := s.repo.GetAllCountries()
+	if err != nil{
+		return err
+	}
+	operators, err := s.repo.GetAllMobileOperators()
+	if err != nil{
+		return err
+	}
+	return planfile.Write(file, format, *countries, *operators)
+}
+
+func planChange(change string, record planfile.Record, ci string, pattern string, from *time.Time, details string) model.PlanChange{
+	return model.PlanChange{
+		Change: change,
+		Kind: record.Kind,
+		Row: record.Row,
+		CountryIdentifier: ci,
+		Pattern: pattern,
+		EffectiveFrom: from,
+		Details: details,
+	}
+}
+
+// validateImportedCountry checks the fields of an imported country the way the admin
+// page checks a new one, filling in the default international prefix
+func validateImportedCountry(country *model.Country) (error){
+
+	if err := validateRule(country.CountryNumberFormat, country.ExcludedFormat); err != nil{
+		return err
+	}
+	if !countryCodeFormat.MatchString(country.CountryCode){
+		return fmt.Errorf("country_code must be 1-6 digits, got %q", country.CountryCode)
+	}
+	if !countryIdentifierFormat.MatchString(country.CountryIdentifier){
+		return fmt.Errorf("country_identifier must be 2 letters, got %q", country.CountryIdentifier)
+	}
+	if country.CountryCodeLength == 0{
+		country.CountryCodeLength = len(country.CountryCode)
+	}
+	if country.CountryCodeLength != len(country.CountryCode){
+		return fmt.Errorf("country_code_length must be the number of digits of country_code %s, got %d", country.CountryCode, country.CountryCodeLength)
+	}
+	if err := numplan.CheckCallingCode(country.CountryNumberFormat, country.CountryCode); err != nil{
+		return err
+	}
+	if !trunkPrefixFormat.MatchString(country.TrunkPrefix){
+		return fmt.Errorf("trunk_prefix must be empty or up to 4 digits, got %q", country.TrunkPrefix)
+	}
+	if country.InternationalPrefix == ""{
+		country.InternationalPrefix = "00"
+	}
+	if !internationalPrefixFormat.MatchString(country.InternationalPrefix){
+		return fmt.Errorf("international_prefix must be a comma separated list of up to 4 digit prefixes, got %q", country.InternationalPrefix)
+	}
+	if country.NSNMinLength < 0 || country.NSNMaxLength < 0 || country.NSNMinLength > 15 || country.NSNMaxLength > 15 ||
+		(country.NSNMaxLength > 0 && country.NSNMinLength > country.NSNMaxLength){
+		return fmt.Errorf("nsn_min_length and nsn_max_length must be 0-15 with the minimum not above the maximum, got %d and %d", country.NSNMinLength, country.NSNMaxLength)
+	}
+	if !numberGroupingFormat.MatchString(country.NumberGrouping){
+		return fmt.Errorf("number_grouping can only contain X for digits, separators and prefixes like 2:X XXX XXXX, got %q", country.NumberGrouping)
+	}
+	return validatePeriod(country.EffectiveFrom, country.EffectiveTo)
+}
+
+// knownOperators are the saved network operators by brand name and by id
+type knownOperators struct {
+	ids map[string]int
+	names map[int]string
+}
+
+// validateImportedOperator checks the fields of an imported operator range, which must
+// belong to an imported country and be assigned to a known network operator. The
+// operator is named by its brand name, or by its id in files of schema version 1,
+// and the range gets both
+func validateImportedOperator(operator *model.MobileOperator, countries map[string]bool, known knownOperators) (error){
+
+	if err := validateRule(operator.PrefixFormat, operator.ExcludedFormat); err != nil{
+		return err
+	}
+	if !countries[operator.CountryIdentifier]{
+		return fmt.Errorf("country_identifier %q isn't a valid country of the file", operator.CountryIdentifier)
+	}
+	if operator.MNO != ""{
+		id, ok := known.ids[operator.MNO]
+		if !ok{
+			return fmt.Errorf("operator %q isn't a known network operator", operator.MNO)
+		}
+		if operator.OperatorID != 0 && operator.OperatorID != id{
+			return fmt.Errorf("operator_id %d isn't the id of operator %q", operator.OperatorID, operator.MNO)
+		}
+		operator.OperatorID = id
+	}else{
+		name, ok := known.names[operator.OperatorID]
+		if !ok{
+			return fmt.Errorf("operator_id %d isn't a known network operator", operator.OperatorID)
+		}
+		operator.MNO = name
+	}
+	if operator.PrefixLength < 0 || operator.PrefixLength > 15{
+		return fmt.Errorf("prefix_length must be 0-15, got %d", operator.PrefixLength)
+	}
+	if operator.NumberType == ""{
+		operator.NumberType = model.NumberTypeMobile
+	}
+	if !model.IsNumberType(operator.NumberType){
+		return fmt.Errorf("unknown number_type %q", operator.NumberType)
+	}
+	return validatePeriod(operator.EffectiveFrom, operator.EffectiveTo)
+}
+
+func validatePeriod(from *time.Time, to *time.Time) (error){
+
+	if from != nil && to != nil && !from.Before(*to){
+		return fmt.Errorf("effective_from must be before effective_to")
+	}
+	return nil
+}
+
+// countryChanges lists the fields of an imported country that differ from the saved one
+func countryChanges(saved model.Country, imported model.Country) string{
+
+	var changes fieldChanges
+	changes.compare("excluded_pattern", saved.ExcludedFormat, imported.ExcludedFormat)
+	changes.compare("country_code", saved.CountryCode, imported.CountryCode)
+	changes.compare("country_identifier", saved.CountryIdentifier, imported.CountryIdentifier)
+	changes.compare("country_code_length", saved.CountryCodeLength, imported.CountryCodeLength)
+	changes.compare("trunk_prefix", saved.TrunkPrefix, imported.TrunkPrefix)
+	changes.compare("international_prefix", saved.InternationalPrefix, imported.InternationalPrefix)
+	changes.compare("nsn_min_length", saved.NSNMinLength, imported.NSNMinLength)
+	changes.compare("nsn_max_length", saved.NSNMaxLength, imported.NSNMaxLength)
+	changes.compare("number_grouping", saved.NumberGrouping, imported.NumberGrouping)
+	changes.compare("priority", saved.Priority, imported.Priority)
+	changes.compare("effective_to", formatEffective(saved.EffectiveTo), formatEffective(imported.EffectiveTo))
+	return changes.String()
+}
+
+// operatorChanges lists the fields of an imported operator range that differ from the saved one
+func operatorChanges(saved model.MobileOperator, imported model.MobileOperator) string{
+
+	var changes fieldChanges
+	changes.compare("excluded_pattern", saved.ExcludedFormat, imported.ExcludedFormat)
+	changes.compare("operator", saved.MNO, imported.MNO)
+	changes.compare("prefix_length", saved.PrefixLength, imported.PrefixLength)
+	changes.compare("number_type", saved.NumberType, imported.NumberType)
+	changes.compare("priority", saved.Priority, imported.Priority)
+	changes.compare("effective_to", formatEffective(saved.EffectiveTo), formatEffective(imported.EffectiveTo))
+	return changes.String()
+}
+
+type fieldChanges []string
+
+func (f *fieldChanges) compare(name string, saved interface{}, imported interface{}){
+
+	if saved != imported{
+		*f = append(*f, fmt.Sprintf("%s: %v -> %v", name, saved, imported))
+	}
+}
+
+func (f fieldChanges) String() string{
+	return strings.Join(f, ", ")
+}
+
+func formatEffective(t *time.Time) string{
+
+	if t == nil{
+		return "none"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/robesmi/MSISDNApp/mocks/repository"
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/errs"
+	"github.com/robesmi/MSISDNApp/planfile"
+)
+
+var mockPlanRepo *repository.MockMSISDNRepository
+var planService PlanService
+
+func setupPlan(t *testing.T) func(){
+
+	ctrl := gomock.NewController(t)
+	mockPlanRepo = repository.NewMockMSISDNRepository(ctrl)
+	planService = NewPlanService(mockPlanRepo)
+
+	return func(){
+		planService = nil
+		ctrl.Finish()
+	}
+}
+
+// expectSavedPlan makes the repository return a saved plan with the mk and pl countries,
+// two mk ranges and a pl range
+func expectSavedPlan(){
+
+	mockPlanRepo.EXPECT().GetAllCountries().Return(&[]model.Country{
+		{ID: 1, CountryNumberFormat: "^389[0-9]{8}$", CountryCode: "389", CountryIdentifier: "mk", CountryCodeLength: 3, TrunkPrefix: "0", InternationalPrefix: "00"},
+		{ID: 2, CountryNumberFormat: "^48[0-9]{9}$", CountryCode: "48", CountryIdentifier: "pl", CountryCodeLength: 2, InternationalPrefix: "00"},
+	}, nil)
+	mockPlanRepo.EXPECT().GetAllMobileOperators().Return(&[]model.MobileOperator{
+		{ID: 1, CountryIdentifier: "mk", PrefixFormat: "^7[0-1][0-9]{6}$", OperatorID: 1, MNO: "A1", PrefixLength: 2, NumberType: model.NumberTypeMobile},
+		{ID: 2, CountryIdentifier: "mk", PrefixFormat: "^7[5-9][0-9]{6}$", OperatorID: 2, MNO: "Telekom", PrefixLength: 2, NumberType: model.NumberTypeMobile},
+		{ID: 3, CountryIdentifier: "pl", PrefixFormat: "^5[0-9]{8}$", OperatorID: 3, MNO: "Plus", PrefixLength: 2, NumberType: model.NumberTypeMobile},
+	}, nil)
+	mockPlanRepo.EXPECT().GetAllNetworkOperators().Return(&[]model.NetworkOperator{{ID: 1, BrandName: "A1"}, {ID: 2, BrandName: "Telekom"}, {ID: 3, BrandName: "Plus"}}, nil).AnyTimes()
+}
+
+// importFile keeps the mk country, drops pl, moves a range to another operator, adds a
+// range and has an invalid row with the pattern of a saved pl range
+const importFile = "kind,pattern,country_identifier,country_code,country_code_length,trunk_prefix,operator_id,prefix_length\n" +
+	"country,^389[0-9]{8}$,mk,389,3,0,,\n" +
+	"operator,^7[0-1][0-9]{6}$,mk,,,,1,2\n" +
+	"operator,^7[5-9][0-9]{6}$,mk,,,,1,2\n" +
+	"operator,^2[0-9]{7}$,mk,,,,3,1\n" +
+	"operator,^5[0-9]{8}$,pl,,,,3,2\n"
+
+func TestImportPlanDryRun(t *testing.T) {
+
+	//Arrange
+	teardown := setupPlan(t)
+	defer teardown()
+	expectSavedPlan()
+
+	//Act
+	diff, err := planService.ImportPlan(strings.NewReader(importFile), planfile.FormatCSV, false)
+
+	//Assert
+	if err != nil{
+		t.Fatalf("Error in TestImportPlanDryRun:\n expected %s\n got %s", "nil", err)
+	}
+	if diff.Added != 1 || diff.Changed != 1 || diff.Removed != 1 || diff.Invalid != 1 || diff.Applied{
+		t.Fatalf("Error in TestImportPlanDryRun:\n expected %s\n got %v", "1 added, 1 changed, 1 removed, 1 invalid", diff)
+	}
+	expected := []struct{
+		Change string
+		Pattern string
+	}{
+		{model.PlanChangeChanged, "^7[5-9][0-9]{6}$"},
+		{model.PlanChangeAdded, "^2[0-9]{7}$"},
+		{model.PlanChangeInvalid, "^5[0-9]{8}$"},
+		{model.PlanChangeRemoved, "^48[0-9]{9}$"},
+	}
+	for i, change := range diff.Changes{
+		if change.Change != expected[i].Change || change.Pattern != expected[i].Pattern{
+			t.Errorf("Error in TestImportPlanDryRun:\n expected %s %s\n got %s %s", expected[i].Change, expected[i].Pattern, change.Change, change.Pattern)
+		}
+	}
+	if diff.Changes[0].Details != "operator: Telekom -> A1"{
+		t.Errorf("Error in TestImportPlanDryRun:\n expected %s\n got %s", "operator: Telekom -> A1", diff.Changes[0].Details)
+	}
+}
+
+func TestImportPlanApply(t *testing.T) {
+
+	//Arrange
+	teardown := setupPlan(t)
+	defer teardown()
+	expectSavedPlan()
+
+	mockPlanRepo.EXPECT().UpdatePlan(gomock.Any()).DoAndReturn(func(update *model.PlanUpdate) error {
+		if len(update.AddedOperators) != 1 || len(update.ChangedOperators) != 1 || update.ChangedOperators[0].ID != 2{
+			t.Errorf("Error in TestImportPlanApply:\n expected %s\n got %v", "an added range and range 2 changed", update)
+		}
+		// The saved pl range shares the pattern of the invalid row, so it's kept
+		if len(update.RemovedCountries) != 1 || update.RemovedCountries[0] != 2 || len(update.RemovedOperators) != 0{
+			t.Errorf("Error in TestImportPlanApply:\n expected %s\n got %v", "only country 2 removed", update)
+		}
+		return nil
+	})
+
+	//Act
+	diff, err := planService.ImportPlan(strings.NewReader(importFile), planfile.FormatCSV, true)
+
+	//Assert
+	if err != nil{
+		t.Fatalf("Error in TestImportPlanApply:\n expected %s\n got %s", "nil", err)
+	}
+	if !diff.Applied{
+		t.Error("Error in TestImportPlanApply:\n expected the import to be applied")
+	}
+}
+
+func TestImportPlanOperatorNames(t *testing.T) {
+
+	//Arrange
+	teardown := setupPlan(t)
+	defer teardown()
+	expectSavedPlan()
+	file := "# schema_version: 2\n" +
+		"kind,pattern,country_identifier,country_code,country_code_length,trunk_prefix,operator,prefix_length\n" +
+		"country,^389[0-9]{8}$,mk,389,3,0,,\n" +
+		"country,^48[0-9]{9}$,pl,48,2,,,\n" +
+		"operator,^7[0-1][0-9]{6}$,mk,,,,A1,2\n" +
+		"operator,^7[5-9][0-9]{6}$,mk,,,,A1,2\n" +
+		"operator,^5[0-9]{8}$,pl,,,,Orange,2\n"
+
+	//Act
+	diff, err := planService.ImportPlan(strings.NewReader(file), planfile.FormatCSV, false)
+
+	//Assert
+	if err != nil{
+		t.Fatalf("Error in TestImportPlanOperatorNames:\n expected %s\n got %s", "nil", err)
+	}
+	if diff.Changed != 1 || diff.Invalid != 1 || diff.Changes[0].Details != "operator: Telekom -> A1"{
+		t.Fatalf("Error in TestImportPlanOperatorNames:\n expected %s\n got %v", "a range moved to A1 and an invalid row", diff.Changes)
+	}
+	if diff.Changes[1].Details != `operator "Orange" isn't a known network operator`{
+		t.Errorf("Error in TestImportPlanOperatorNames:\n expected %s\n got %s", "Orange to be unknown", diff.Changes[1].Details)
+	}
+}
+
+func TestImportPlanInvalidFile(t *testing.T) {
+
+	//Arrange
+	teardown := setupPlan(t)
+	defer teardown()
+
+	//Act
+	_, err := planService.ImportPlan(strings.NewReader("kind,country_identifier\n"), planfile.FormatCSV, true)
+
+	//Assert
+	if _, ok := err.(*errs.InvalidFileError); !ok{
+		t.Errorf("Error in TestImportPlanInvalidFile:\n expected %s\n got %v", "InvalidFileError", err)
+	}
+}
+
+func TestExportPlanImportsWithoutChanges(t *testing.T) {
+
+	//Arrange
+	teardown := setupPlan(t)
+	defer teardown()
+	expectSavedPlan()
+	expectSavedPlan()
+
+	//Act
+	var file bytes.Buffer
+	err := planService.ExportPlan(&file, planfile.FormatCSV)
+	if err != nil{
+		t.Fatalf("Error in TestExportPlanImportsWithoutChanges:\n expected %s\n got %s", "nil", err)
+	}
+	diff, err := planService.ImportPlan(&file, planfile.FormatCSV, false)
+
+	//Assert
+	if err != nil{
+		t.Fatalf("Error in TestExportPlanImportsWithoutChanges:\n expected %s\n got %s", "nil", err)
+	}
+	if len(diff.Changes) != 0{
+		t.Errorf("Error in TestExportPlanImportsWithoutChanges:\n expected %s\n got %v", "no changes", diff.Changes)
+	}
+}
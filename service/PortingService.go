@@ -0,0 +1,122 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/errs"
+	"github.com/robesmi/MSISDNApp/normalize"
+	"github.com/robesmi/MSISDNApp/portability"
+	"github.com/robesmi/MSISDNApp/repository"
+)
+
+// portingBatchSize is how many ported numbers are written to the repository at once
+const portingBatchSize = 1000
+
+// DefaultPortingService keeps the ported numbers in the repository and in the
+// in-memory store used by lookups in sync
+type DefaultPortingService struct {
+	repo repository.PortingRepository
+	store *portability.Store
+}
+
+func NewPortingService(repo repository.PortingRepository, store *portability.Store) DefaultPortingService{
+	return DefaultPortingService{repo, store}
+}
+
+//go:generate mockgen -destination=../mocks/service/mockPortingService.go -package=service github.com/robesmi/MSISDNApp/service PortingService
+type PortingService interface {
+	// Reload fills the store with the ported numbers saved in the repository
+	Reload() (error)
+	// LoadPortedNumbers takes a CSV file with a number and the operator it was ported to on each row
+	// and saves them, returning how many rows were loaded. When replace is set the file is a full
+	// export replacing every ported number, otherwise it's an incremental update where a row without
+	// an operator means the number is no longer ported. A file with an invalid row isn't loaded at all
+	LoadPortedNumbers(io.Reader, bool) (int, error)
+	// CountPortedNumbers returns how many numbers are ported
+	CountPortedNumbers() int
+}
+
+func (s DefaultPortingService) Reload() (error){
+
+	builder := portability.NewBuilder()
+	err := s.repo.GetAllPortedNumbers(func(number model.PortedNumber) error {
+		return builder.Add(number.MSISDN, number.MNO)
+	})
+	if err != nil{
+		return err
+	}
+	s.store.Replace(builder)
+	return nil
+}
+
+func (s DefaultPortingService) LoadPortedNumbers(file io.Reader, replace bool) (int, error){
+
+	reader := newNumberReader(file)
+	builder := portability.NewBuilder()
+	var changes []portability.Change
+	count, line := 0, 0
+
+	next := func() ([]model.PortedNumber, error){
+		batch := make([]model.PortedNumber, 0, portingBatchSize)
+		for len(batch) < portingBatchSize{
+			record, err := reader.NextRecord()
+			if err == io.EOF{
+				break
+			}
+			if err != nil{
+				return nil, errs.NewInvalidFileError(err.Error())
+			}
+			line++
+			if record[0] == ""{
+				continue
+			}
+			number, err := parsePortedNumber(record, replace)
+			if err != nil{
+				return nil, errs.NewInvalidFileError(fmt.Sprintf("row %d: %s", line, err.Error()))
+			}
+			if replace{
+				if err := builder.Add(number.MSISDN, number.MNO); err != nil{
+					return nil, err
+				}
+			}else{
+				changes = append(changes, portability.Change{MSISDN: number.MSISDN, MNO: number.MNO})
+			}
+			batch = append(batch, number)
+		}
+		count += len(batch)
+		return batch, nil
+	}
+
+	if err := s.repo.ApplyPortedNumbers(replace, next); err != nil{
+		return 0, err
+	}
+	if replace{
+		s.store.Replace(builder)
+	}else if err := s.store.Apply(changes); err != nil{
+		return 0, err
+	}
+	return count, nil
+}
+
+func (s DefaultPortingService) CountPortedNumbers() int{
+	return s.store.Len()
+}
+
+func parsePortedNumber(record []string, replace bool) (model.PortedNumber, error){
+
+	normalized, err := normalize.Normalize(record[0], nil)
+	if err != nil{
+		return model.PortedNumber{}, err
+	}
+	number := model.PortedNumber{MSISDN: normalized.Number}
+	if len(record) > 1{
+		number.MNO = strings.TrimSpace(record[1])
+	}
+	if replace && number.MNO == ""{
+		return model.PortedNumber{}, errs.NewInvalidNumberError("missing the operator of " + number.MSISDN)
+	}
+	return number, nil
+}
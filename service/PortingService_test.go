@@ -0,0 +1,140 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/robesmi/MSISDNApp/mocks/repository"
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/errs"
+	"github.com/robesmi/MSISDNApp/portability"
+)
+
+var mockPortingRepo *repository.MockPortingRepository
+var portingStore *portability.Store
+var portingService PortingService
+
+func setupPorting(t *testing.T) func(){
+
+	ctrl := gomock.NewController(t)
+	mockPortingRepo = repository.NewMockPortingRepository(ctrl)
+	portingStore = portability.NewStore()
+	portingService = NewPortingService(mockPortingRepo, portingStore)
+
+	return func(){
+		portingService = nil
+		ctrl.Finish()
+	}
+}
+
+// drain reads every batch the service hands to the repository
+func drain(saved *[]model.PortedNumber) func(bool, func() ([]model.PortedNumber, error)) error {
+	return func(replace bool, next func() ([]model.PortedNumber, error)) error {
+		for {
+			batch, err := next()
+			if err != nil || len(batch) == 0{
+				return err
+			}
+			*saved = append(*saved, batch...)
+		}
+	}
+}
+
+func TestLoadPortedNumbersReplace(t *testing.T) {
+
+	//Arrange
+	teardown := setupPorting(t)
+	defer teardown()
+
+	var saved []model.PortedNumber
+	mockPortingRepo.EXPECT().ApplyPortedNumbers(true, gomock.Any()).DoAndReturn(drain(&saved))
+	file := "msisdn,operator\n38977123456,Telekom\n+389 71 123 456,A1\n"
+
+	//Act
+	count, err := portingService.LoadPortedNumbers(strings.NewReader(file), true)
+
+	//Assert
+	if err != nil || count != 2{
+		t.Fatalf("Error in TestLoadPortedNumbersReplace:\n expected %d numbers\n got %d, %v", 2, count, err)
+	}
+	if len(saved) != 2 || saved[1].MSISDN != "38971123456"{
+		t.Errorf("Error in TestLoadPortedNumbersReplace:\n expected the normalized numbers to be saved\n got %v", saved)
+	}
+	if mno, _ := portingStore.Lookup("38971123456"); mno != "A1"{
+		t.Errorf("Error in TestLoadPortedNumbersReplace:\n expected %s\n got %s", "A1", mno)
+	}
+}
+
+func TestLoadPortedNumbersIncremental(t *testing.T) {
+
+	//Arrange
+	teardown := setupPorting(t)
+	defer teardown()
+
+	builder := portability.NewBuilder()
+	builder.Add("38977123456", "Telekom")
+	portingStore.Replace(builder)
+
+	var saved []model.PortedNumber
+	mockPortingRepo.EXPECT().ApplyPortedNumbers(false, gomock.Any()).DoAndReturn(drain(&saved))
+	file := "38977123456,\n38970123456,A1\n"
+
+	//Act
+	count, err := portingService.LoadPortedNumbers(strings.NewReader(file), false)
+
+	//Assert
+	if err != nil || count != 2{
+		t.Fatalf("Error in TestLoadPortedNumbersIncremental:\n expected %d numbers\n got %d, %v", 2, count, err)
+	}
+	if _, ported := portingStore.Lookup("38977123456"); ported{
+		t.Error("Error in TestLoadPortedNumbersIncremental:\n expected the number without an operator to be removed")
+	}
+	if portingService.CountPortedNumbers() != 1{
+		t.Errorf("Error in TestLoadPortedNumbersIncremental:\n expected %d numbers\n got %d", 1, portingService.CountPortedNumbers())
+	}
+}
+
+func TestLoadPortedNumbersInvalidRow(t *testing.T) {
+
+	//Arrange
+	teardown := setupPorting(t)
+	defer teardown()
+
+	var saved []model.PortedNumber
+	mockPortingRepo.EXPECT().ApplyPortedNumbers(true, gomock.Any()).DoAndReturn(drain(&saved))
+	file := "38977123456,Telekom\n38970123456,\n"
+
+	//Act
+	_, err := portingService.LoadPortedNumbers(strings.NewReader(file), true)
+
+	//Assert
+	if _, ok := err.(*errs.InvalidFileError); !ok{
+		t.Errorf("Error in TestLoadPortedNumbersInvalidRow:\n expected %s\n got %v", "InvalidFileError", err)
+	}
+	if portingService.CountPortedNumbers() != 0{
+		t.Error("Error in TestLoadPortedNumbersInvalidRow:\n expected nothing to be loaded")
+	}
+}
+
+func TestReloadPortedNumbers(t *testing.T) {
+
+	//Arrange
+	teardown := setupPorting(t)
+	defer teardown()
+
+	mockPortingRepo.EXPECT().GetAllPortedNumbers(gomock.Any()).DoAndReturn(func(fn func(model.PortedNumber) error) error {
+		return fn(model.PortedNumber{MSISDN: "38977123456", MNO: "Telekom"})
+	})
+
+	//Act
+	err := portingService.Reload()
+
+	//Assert
+	if err != nil{
+		t.Fatalf("Error in TestReloadPortedNumbers:\n expected %s\n got %s", "nil", err)
+	}
+	if mno, _ := portingStore.Lookup("38977123456"); mno != "Telekom"{
+		t.Errorf("Error in TestReloadPortedNumbers:\n expected %s\n got %s", "Telekom", mno)
+	}
+}
@@ -15,14 +15,16 @@ import (
 var GoogleJwkUrl = "https://www.googleapis.com/oauth2/v3/certs"
 
 // CreateAccessToken creates a JWT access token with the custom claim "role" that will
-// be used to check whether the bearer has the permissions to use certain routes
-func CreateAccessToken(role string, vault vault.VaultInterface) (string, error){
+// be used to check whether the bearer has the permissions to use certain routes, and
+// the claim "id" identifying the user that owns resources such as lookup jobs
+func CreateAccessToken(userid string, role string, vault vault.VaultInterface) (string, error){
 
 	claims := make(jwt.MapClaims)
 	claims["exp"] = time.Now().Add(time.Minute * 15).Unix()
 	claims["iat"] = time.Now().Unix()
 	claims["nbf"] = time.Now().Unix()
 	claims["role"] = role
+	claims["id"] = userid
 
 	data, fetchErr := vault.Fetch("appvars", "AccessTokenPrivateKey")
 	if fetchErr != nil{
@@ -2,22 +2,28 @@ package web
 
 import (
 	"fmt"
+	"net"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-contrib/sessions/cookie"
 	"github.com/gin-gonic/gin"
-	_ "github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
+	"github.com/robesmi/MSISDNApp/apiformat"
+	"github.com/robesmi/MSISDNApp/lookupcache"
 	"github.com/robesmi/MSISDNApp/middleware"
+	"github.com/robesmi/MSISDNApp/portability"
 	"github.com/robesmi/MSISDNApp/repository"
+	"github.com/robesmi/MSISDNApp/rpc"
 	"github.com/robesmi/MSISDNApp/service"
 	"github.com/robesmi/MSISDNApp/web/handlers"
 	"github.com/rs/zerolog"
 	vaultapi "github.com/hashicorp/vault/api"
 	"github.com/robesmi/MSISDNApp/vault"
+	"google.golang.org/grpc"
 
 )
 
@@ -43,26 +49,7 @@ func Start(){
 	logger := zerolog.New(os.Stderr).With().Timestamp().Logger()
 
 	// Setup the client for interacting with the vault
-	vault_config := vaultapi.DefaultConfig()
-
-	vAddr, set := os.LookupEnv("VAULT_ADDR")
-	if !set{
-		logger.Error().Msg("VAULT_ADDR is not set in env")
-		os.Exit(1)
-	}else{
-		vault_config.Address = vAddr
-	}
-	
-	token,ok := os.LookupEnv("MY_VAULT_TOKEN")
-	if !ok {
-		logger.Error().Msg("MY_VAULT_TOKEN is not set in env")
-		os.Exit(1)
-	}
-
-	client, vaultErr  := vault.New(vault_config, token)
-	if vaultErr != nil {
-		logger.Error().Err(vaultErr).Str("package","web").Str("context","Start").Msg("Error starting vault client")
-	}
+	client := getVaultClient(&logger)
 
 	// Immediately get some variables that will be needed for setup
 	startupVars, fetchErr := client.Fetch("appvars", "Secret", "PORT")
@@ -70,15 +57,36 @@ func Start(){
 		logger.Error().Err(fetchErr).Str("package","web").Str("context","Start").Msg("Error getting startup variables")
 	}
 	
+	// Nodes serving a snapshot of the numbering plan only answer lookups, so they run without
+	// a database and leave out the accounts, jobs, ported numbers and changes to the plan
+	snapshotPath, fromSnapshot := os.LookupEnv("PLAN_SNAPSHOT")
+
 	// Setup the db connection along with initializing the layers
-	dbClient := getDbClient(client, &logger)
-	msrepo := repository.NewMSISDNRepository(dbClient)
-	aurepo := repository.NewAuthRepository(dbClient)
-	mh := handlers.MSISDNLookupHandler{Service: service.NewMSISDNService(msrepo), Logger: logger}
-	//ah := handlers.AuthHandler{Service: service.ReturnAuthService(aurepo), Logger: logger, Vault: client}
-	ah := handlers.NewAuthHandler(service.ReturnAuthService(aurepo, client), logger, client)
-	aph := handlers.AuthApiHandler{Service: service.ReturnAuthService(aurepo, client), Vault: client}
-	adh := handlers.AdminActionsHandler{AuthService: service.ReturnAuthService(aurepo, client), MSISDNService: service.NewMSISDNService(msrepo), Logger: logger, Vault: client}
+	var dbClient *sqlx.DB
+	var msrepo *repository.MSISDNRepositoryIndex
+	if fromSnapshot{
+		msrepo = getSnapshotRepository(snapshotPath, &logger)
+	}else{
+		dbClient = getDbClient(client, &logger)
+		msrepo = getPlanRepository(dbClient, &logger)
+	}
+	for _, expr := range msrepo.Unsupported(){
+		logger.Warn().Str("package","web").Str("context","Start").Str("pattern", expr).Msg("Pattern not supported by the lookup index, falling back to the database")
+	}
+	portedNumbers := portability.NewStore()
+	lookupCache := getLookupCache(&logger)
+	msrepo.OnReload(lookupCache.Invalidate)
+	msservice := service.NewCachedMSISDNService(msrepo, portedNumbers, lookupCache)
+	mh := handlers.MSISDNLookupHandler{Service: msservice, Logger: logger}
+	newGraphQLHandler := handlers.NewGraphQLHandler
+	if fromSnapshot{
+		newGraphQLHandler = handlers.NewReadOnlyGraphQLHandler
+	}
+	gqh, schemaErr := newGraphQLHandler(msservice, logger, client)
+	if schemaErr != nil{
+		logger.Error().Err(schemaErr).Str("package","web").Str("context","Start").Msg("Error building the GraphQL schema")
+		os.Exit(1)
+	}
 
 	//Wiring
 	router.LoadHTMLGlob("templates/*.html")
@@ -88,6 +96,69 @@ func Start(){
 	
 	router.GET("/", mh.GetMainPage)
 
+	// The API routes turn away requests for a format they can't answer in before running
+	// the auth middleware and handlers, so those don't take effect without a response
+	router.GET("/api/countries", apiformat.Negotiate(), mh.CountriesApi)
+	router.GET("/api/countries/:code", apiformat.Negotiate(), mh.CountryApi)
+
+	router.POST("/graphql", gqh.Query)
+
+	router.POST("/service/api/lookup", apiformat.Negotiate(), middleware.ValidateApiTokenUserSection(client), mh.NumberLookupApi)
+	router.POST("/service/api/lookup/batch", apiformat.Negotiate(), middleware.ValidateApiTokenUserSection(client), mh.NumberLookupBatchApi)
+	router.POST("/service/api/lookup/partial", apiformat.Negotiate(), middleware.ValidateApiTokenUserSection(client), mh.NumberPartialLookupApi)
+	router.POST("/service/api/extract", apiformat.Negotiate(), middleware.ValidateApiTokenUserSection(client), mh.NumberExtractApi)
+	router.POST("/service/api/validate", apiformat.Negotiate(), middleware.ValidateApiTokenUserSection(client), mh.NumberValidateApi)
+
+	userSection := router.Group("/service")
+	userSection.Use(middleware.ValidateTokenUserSection(client))
+	
+	{
+		userSection.GET("/lookup", mh.GetLookupPage)
+		userSection.POST("/lookup", mh.NumberLookup)
+	}
+
+	router.NoRoute( func(c *gin.Context){
+		c.HTML(http.StatusNotFound, "notfound.html", nil)
+	})
+
+	if fromSnapshot{
+		logger.Info().Str("package","web").Str("context","Start").Str("snapshot", snapshotPath).Msg("Serving lookups from the numbering plan snapshot, without accounts, jobs or ported numbers")
+	}else{
+		wireDatabase(router, userSection, client, dbClient, msservice, msrepo, portedNumbers, &logger)
+	}
+
+	//Starting up the gRPC server next to the router
+	go serveGrpc(rpc.NewServer(msservice, client, logger), &logger)
+
+	//Starting up server
+	router.Run(":" + startupVars["PORT"])
+}
+
+// wireDatabase sets up the layers and routes that need the database: the accounts and
+// admin panel, the lookup jobs and the ported numbers, and makes sure the admin user exists
+func wireDatabase(router *gin.Engine, userSection *gin.RouterGroup, client vault.VaultInterface, dbClient *sqlx.DB, msservice service.MSISDNService, msrepo *repository.MSISDNRepositoryIndex, portedNumbers *portability.Store, logger *zerolog.Logger){
+
+	portingService := service.NewPortingService(repository.NewPortingRepository(dbClient), portedNumbers)
+	if portErr := portingService.Reload(); portErr != nil{
+		logger.Error().Err(portErr).Str("package","web").Str("context","wireDatabase").Msg("Error loading ported numbers")
+	}
+	aurepo := repository.NewAuthRepository(dbClient)
+	//ah := handlers.AuthHandler{Service: service.ReturnAuthService(aurepo), Logger: logger, Vault: client}
+	ah := handlers.NewAuthHandler(service.ReturnAuthService(aurepo, client), *logger, client)
+	aph := handlers.AuthApiHandler{Service: service.ReturnAuthService(aurepo, client), Vault: client}
+	planService := service.NewPlanService(msrepo)
+	adh := handlers.AdminActionsHandler{AuthService: service.ReturnAuthService(aurepo, client), MSISDNService: msservice, PortingService: portingService, PlanService: planService, Logger: *logger, Vault: client}
+
+	jobsDir, set := os.LookupEnv("JOBS_DIR")
+	if !set{
+		jobsDir = "jobs"
+	}
+	jobService := service.NewJobService(repository.NewJobRepository(dbClient), msservice, jobsDir, *logger)
+	if jobErr := jobService.Start(); jobErr != nil{
+		logger.Error().Err(jobErr).Str("package","web").Str("context","wireDatabase").Msg("Error starting the lookup job runner")
+	}
+	jh := handlers.LookupJobHandler{Service: jobService, Logger: *logger}
+
 	router.GET("/register", ah.GetRegisterPage)
 	router.POST("/register", ah.HandleNativeRegister)
 
@@ -118,19 +189,25 @@ func Start(){
 		c.Redirect(http.StatusTemporaryRedirect,"/login")
 	})
 
-	router.POST("/api/register", aph.HandleNativeRegisterCall)
-	router.POST("/api/login", aph.HandleNativeLoginCall)
-	router.POST("/api/refresh", aph.RefreshAccessTokenCall)
-	router.POST("/api/logout", aph.LogOutCall)
+	router.POST("/api/register", apiformat.Negotiate(), aph.HandleNativeRegisterCall)
+	router.POST("/api/login", apiformat.Negotiate(), aph.HandleNativeLoginCall)
+	router.POST("/api/refresh", apiformat.Negotiate(), aph.RefreshAccessTokenCall)
+	router.POST("/api/logout", apiformat.Negotiate(), aph.LogOutCall)
 
-	router.POST("/service/api/lookup", middleware.ValidateApiTokenUserSection(client), mh.NumberLookupApi)
+	apiJobs := router.Group("/service/api/jobs")
+	apiJobs.Use(apiformat.Negotiate(), middleware.ValidateApiTokenUserSection(client))
+	{
+		apiJobs.POST("", jh.CreateJobApi)
+		apiJobs.GET("/:id", jh.GetJobStatusApi)
+		apiJobs.POST("/:id/cancel", jh.CancelJobApi)
+		apiJobs.GET("/:id/download", jh.DownloadJobResult)
+	}
 
-	userSection := router.Group("/service")
-	userSection.Use(middleware.ValidateTokenUserSection(client))
-	
 	{
-		userSection.GET("/lookup", mh.GetLookupPage)
-		userSection.POST("/lookup", mh.NumberLookup)
+		userSection.GET("/jobs", jh.GetJobsPage)
+		userSection.POST("/jobs", jh.CreateJob)
+		userSection.POST("/jobs/:id/cancel", jh.CancelJob)
+		userSection.GET("/jobs/:id/download", jh.DownloadJobResult)
 	}
 
 	adminSection := router.Group("/admin")
@@ -148,17 +225,26 @@ func Start(){
 
 		adminSection.POST("/addoperator", adh.InsertNewMobileOperator)
 		adminSection.POST("/removeoperator", adh.RemoveOperator)
+
+		adminSection.POST("/addnetworkoperator", adh.InsertNewNetworkOperator)
+		adminSection.POST("/removenetworkoperator", adh.RemoveNetworkOperator)
+
+		adminSection.POST("/savecountrydetails", adh.SaveCountryDetails)
+
+		adminSection.POST("/loadported", adh.LoadPortedNumbers)
+		adminSection.POST("/importplan", adh.ImportPlan)
+		adminSection.POST("/exportplan", adh.ExportPlan)
 	
 		adminSection.POST("/getusers", adh.GetAllUsers)
 		adminSection.POST("/getcountries", adh.GetAllCountries)
 		adminSection.POST("/getoperators", adh.GetAllMobileOperators)
+		adminSection.POST("/getnetworkoperators", adh.GetAllNetworkOperators)
+		adminSection.POST("/getoverlaps", adh.GetOverlaps)
+		adminSection.POST("/selfcheck", adh.SelfCheck)
+		adminSection.POST("/cachestats", adh.GetCacheStats)
 
 	}
 
-	router.NoRoute( func(c *gin.Context){
-		c.HTML(http.StatusNotFound, "notfound.html", nil)
-	})
-
 	// Initialize an admin user
 	user, userErr := client.Fetch("superuser", "AdminUsername", "AdminPassword")
 	if userErr != nil{
@@ -168,29 +254,166 @@ func Start(){
 	if regErr != nil{
 		logger.Err(regErr).Str("package","web").Str("context","init").Msg("Error during init")
 	}
+}
 
-	//Starting up server
-	router.Run(":" + startupVars["PORT"])
+// defaultGrpcPort is the port the gRPC server listens on when GRPC_PORT isn't set
+const defaultGrpcPort = "9090"
+
+// serveGrpc serves the lookup and numbering plan RPCs on GRPC_PORT, logging why it stopped
+func serveGrpc(server *grpc.Server, logger *zerolog.Logger){
+
+	port, set := os.LookupEnv("GRPC_PORT")
+	if !set{
+		port = defaultGrpcPort
+	}
+	listener, listenErr := net.Listen("tcp", ":" + port)
+	if listenErr != nil{
+		logger.Error().Err(listenErr).Str("package","web").Str("context","serveGrpc").Msg("Error listening for gRPC calls")
+		return
+	}
+	if serveErr := server.Serve(listener); serveErr != nil{
+		logger.Error().Err(serveErr).Str("package","web").Str("context","serveGrpc").Msg("gRPC server stopped")
+	}
 }
 
-// getDbClient initializes the db connection and returns it to Start
+// snapshotInterval is how often a numbering plan snapshot file is checked for changes
+const snapshotInterval = 10 * time.Second
+
+// getPlanRepository loads the numbering plan for lookups from the database, exiting when
+// it can't be loaded
+func getPlanRepository(dbClient *sqlx.DB, logger *zerolog.Logger) *repository.MSISDNRepositoryIndex{
+
+	msrepo, indexErr := repository.NewMSISDNRepositoryIndex(repository.NewMSISDNRepository(dbClient))
+	if indexErr != nil{
+		logger.Error().Err(indexErr).Str("package","web").Str("context","getPlanRepository").Msg("Error loading the numbering plan")
+		os.Exit(1)
+	}
+	return msrepo
+}
+
+// getSnapshotRepository loads the numbering plan for lookups from the snapshot file, which
+// is then reloaded whenever it changes. It exits when the plan can't be loaded
+func getSnapshotRepository(snapshotPath string, logger *zerolog.Logger) *repository.MSISDNRepositoryIndex{
+
+	snapshot, fileErr := repository.NewMSISDNRepositoryFile(snapshotPath)
+	if fileErr != nil{
+		logger.Error().Err(fileErr).Str("package","web").Str("context","getSnapshotRepository").Msg("Error loading the numbering plan snapshot")
+		os.Exit(1)
+	}
+	msrepo, indexErr := repository.NewMSISDNRepositoryIndex(snapshot)
+	if indexErr != nil{
+		logger.Error().Err(indexErr).Str("package","web").Str("context","getSnapshotRepository").Msg("Error loading the numbering plan")
+		os.Exit(1)
+	}
+	snapshot.Watch(snapshotInterval, func(err error){
+		if err == nil{
+			err = msrepo.Reload()
+		}
+		if err != nil{
+			logger.Error().Err(err).Str("package","web").Str("context","getSnapshotRepository").Msg("Error reloading the numbering plan snapshot, keeping the loaded plan")
+			return
+		}
+		logger.Info().Str("package","web").Str("context","getSnapshotRepository").Str("snapshot", snapshotPath).Msg("Reloaded the numbering plan snapshot")
+	})
+	return msrepo
+}
+
+// Defaults of the lookup cache, when LOOKUP_CACHE_SIZE, LOOKUP_CACHE_TTL and
+// LOOKUP_CACHE_NEGATIVE_TTL aren't set
+const (
+	defaultLookupCacheSize = 100000
+	defaultLookupCacheTTL = 5 * time.Minute
+	defaultLookupCacheNegativeTTL = 30 * time.Second
+)
+
+// getLookupCache sets up the cache of lookup results from LOOKUP_CACHE_SIZE, LOOKUP_CACHE_TTL
+// and LOOKUP_CACHE_NEGATIVE_TTL, falling back to the defaults for values that don't parse.
+// A size of 0 turns the cache off
+func getLookupCache(logger *zerolog.Logger) *lookupcache.Cache{
+
+	size := defaultLookupCacheSize
+	if value, set := os.LookupEnv("LOOKUP_CACHE_SIZE"); set{
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0{
+			logger.Warn().Str("package","web").Str("context","getLookupCache").Str("LOOKUP_CACHE_SIZE", value).Msg("Invalid lookup cache size, using the default")
+		}else{
+			size = parsed
+		}
+	}
+	ttl := lookupCacheDuration("LOOKUP_CACHE_TTL", defaultLookupCacheTTL, logger)
+	negativeTTL := lookupCacheDuration("LOOKUP_CACHE_NEGATIVE_TTL", defaultLookupCacheNegativeTTL, logger)
+	return lookupcache.New(size, ttl, negativeTTL)
+}
+
+// lookupCacheDuration reads a duration such as 5m or 30s from the environment variable
+func lookupCacheDuration(name string, fallback time.Duration, logger *zerolog.Logger) time.Duration{
+
+	value, set := os.LookupEnv(name)
+	if !set{
+		return fallback
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed <= 0{
+		logger.Warn().Str("package","web").Str("context","getLookupCache").Str(name, value).Msg("Invalid lookup cache duration, using the default")
+		return fallback
+	}
+	return parsed
+}
+
+// getVaultClient sets up the client for the vault at VAULT_ADDR, exiting when the
+// address or the token aren't set
+func getVaultClient(logger *zerolog.Logger) vault.VaultInterface{
+
+	vault_config := vaultapi.DefaultConfig()
+
+	vAddr, set := os.LookupEnv("VAULT_ADDR")
+	if !set{
+		logger.Error().Msg("VAULT_ADDR is not set in env")
+		os.Exit(1)
+	}else{
+		vault_config.Address = vAddr
+	}
+	
+	token,ok := os.LookupEnv("MY_VAULT_TOKEN")
+	if !ok {
+		logger.Error().Msg("MY_VAULT_TOKEN is not set in env")
+		os.Exit(1)
+	}
+
+	client, vaultErr  := vault.New(vault_config, token)
+	if vaultErr != nil {
+		logger.Error().Err(vaultErr).Str("package","web").Str("context","getVaultClient").Msg("Error starting vault client")
+	}
+	return client
+}
+
+// getDbClient initializes the db connection with the DB_DRIVER and DB_SOURCE variables,
+// or MYSQL_DRIVER and MYSQL_SOURCE when they're not set, creates the tables that don't
+// exist yet and returns it to Start
 func getDbClient(vault vault.VaultInterface, logger *zerolog.Logger) *sqlx.DB{
 
-	dbCreds, fetchErr := vault.Fetch("appvars", "MYSQL_DRIVER", "MYSQL_SOURCE")
+	dbCreds, fetchErr := vault.Fetch("appvars", "DB_DRIVER", "DB_SOURCE", "MYSQL_DRIVER", "MYSQL_SOURCE")
 	if fetchErr != nil {
 		logger.Error().Err(fetchErr).Str("package","web").Str("context","getDbClient").Msg("Error getting db details from vault")
 	}
+	driver, source := dbCreds["DB_DRIVER"], dbCreds["DB_SOURCE"]
+	if driver == ""{
+		driver, source = dbCreds["MYSQL_DRIVER"], dbCreds["MYSQL_SOURCE"]
+	}
 
-
-	client, err := sqlx.Open(dbCreds["MYSQL_DRIVER"],dbCreds["MYSQL_SOURCE"])
+	client, err := repository.Open(driver, source)
 	if err != nil {
 		logger.Error().Err(err).Str("package","web").Str("context","getDbClient").Msg("Error opening db connection")
-		
+		os.Exit(1)
 	}
 	
 	client.SetMaxOpenConns(10)
 	client.SetMaxIdleConns(10)
 	client.SetConnMaxLifetime(time.Hour)
 
+	if schemaErr := repository.CreateSchema(client); schemaErr != nil{
+		logger.Error().Err(schemaErr).Str("package","web").Str("context","getDbClient").Msg("Error creating the db tables")
+	}
+
 	return client
-}
\ No newline at end of file
+}
@@ -0,0 +1,221 @@
+package web
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/planfile"
+	"github.com/robesmi/MSISDNApp/portability"
+	"github.com/robesmi/MSISDNApp/repository"
+	"github.com/robesmi/MSISDNApp/service"
+	"github.com/rs/zerolog"
+)
+
+// RunCommand runs a maintenance command given on the command line, with the same
+// vault and database as the server, and returns the exit code of the process
+func RunCommand(args []string) int{
+
+	logger := zerolog.New(os.Stderr).With().Timestamp().Logger()
+	switch args[0]{
+	case "import":
+		return importCommand(args[1:], &logger)
+	case "export":
+		return exportCommand(args[1:], &logger)
+	case "snapshot":
+		return snapshotCommand(args[1:], &logger)
+	case "selfcheck":
+		return selfCheckCommand(args[1:], &logger)
+	}
+	fmt.Fprintf(os.Stderr, "Unknown command %s, the available commands are: import, export, snapshot, selfcheck\n", args[0])
+	return 2
+}
+
+// importCommand imports a numbering plan file, printing the differences with the saved plan
+func importCommand(args []string, logger *zerolog.Logger) int{
+
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	apply := flags.Bool("apply", false, "save the valid rows instead of only showing the differences")
+	format := flags.String("format", "", "file format, csv, json or yaml, taken from the file extension by default")
+	flags.Usage = func(){
+		fmt.Fprintln(flags.Output(), "Usage: import [-apply] [-format csv|json|yaml] <file>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil{
+		return 2
+	}
+	if flags.NArg() != 1{
+		flags.Usage()
+		return 2
+	}
+	path := flags.Arg(0)
+	if *format == ""{
+		*format = planfile.FormatOf(path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil{
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer file.Close()
+
+	dbClient := getDbClient(getVaultClient(logger), logger)
+	planService := service.NewPlanService(repository.NewMSISDNRepository(dbClient))
+	diff, err := planService.ImportPlan(file, *format, *apply)
+	if err != nil{
+		fmt.Fprintln(os.Stderr, "Error importing numbering plan:", err)
+		return 1
+	}
+	printPlanDiff(os.Stdout, diff)
+	return 0
+}
+
+// exportCommand writes the whole saved numbering plan to a file or the standard output
+func exportCommand(args []string, logger *zerolog.Logger) int{
+
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := flags.String("format", "", "file format, csv, json or yaml, taken from the output file extension or json by default")
+	output := flags.String("o", "", "file to write the plan to instead of the standard output")
+	flags.Usage = func(){
+		fmt.Fprintln(flags.Output(), "Usage: export [-format csv|json|yaml] [-o file]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil{
+		return 2
+	}
+	if flags.NArg() != 0{
+		flags.Usage()
+		return 2
+	}
+	if *format == ""{
+		*format = planfile.FormatOf(*output)
+	}
+	if *format == ""{
+		*format = planfile.FormatJSON
+	}
+
+	dbClient := getDbClient(getVaultClient(logger), logger)
+	planService := service.NewPlanService(repository.NewMSISDNRepository(dbClient))
+	// The plan is written to memory first so that a failed export doesn't leave a partial file
+	var plan bytes.Buffer
+	if err := planService.ExportPlan(&plan, *format); err != nil{
+		fmt.Fprintln(os.Stderr, "Error exporting numbering plan:", err)
+		return 1
+	}
+	if *output == ""{
+		os.Stdout.Write(plan.Bytes())
+		return 0
+	}
+	if err := os.WriteFile(*output, plan.Bytes(), 0644); err != nil{
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// snapshotCommand saves the numbering plan in a snapshot file for lookup nodes running without the database
+func snapshotCommand(args []string, logger *zerolog.Logger) int{
+
+	flags := flag.NewFlagSet("snapshot", flag.ContinueOnError)
+	flags.Usage = func(){
+		fmt.Fprintln(flags.Output(), "Usage: snapshot <file>, written as JSON for .json files and in the compact binary format otherwise")
+	}
+	if err := flags.Parse(args); err != nil{
+		return 2
+	}
+	if flags.NArg() != 1{
+		flags.Usage()
+		return 2
+	}
+
+	dbClient := getDbClient(getVaultClient(logger), logger)
+	if err := repository.WriteSnapshot(flags.Arg(0), repository.NewMSISDNRepository(dbClient)); err != nil{
+		fmt.Fprintln(os.Stderr, "Error writing numbering plan snapshot:", err)
+		return 1
+	}
+	return 0
+}
+
+// selfCheckCommand looks up an example number of every rule in effect and prints the rules
+// with problems, exiting with 1 when there are any
+func selfCheckCommand(args []string, logger *zerolog.Logger) int{
+
+	flags := flag.NewFlagSet("selfcheck", flag.ContinueOnError)
+	all := flags.Bool("all", false, "print every rule with its example number, not only the ones with problems")
+	flags.Usage = func(){
+		fmt.Fprintln(flags.Output(), "Usage: selfcheck [-all]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil{
+		return 2
+	}
+	if flags.NArg() != 0{
+		flags.Usage()
+		return 2
+	}
+
+	dbClient := getDbClient(getVaultClient(logger), logger)
+	msrepo, err := repository.NewMSISDNRepositoryIndex(repository.NewMSISDNRepository(dbClient))
+	if err != nil{
+		fmt.Fprintln(os.Stderr, "Error loading numbering plan:", err)
+		return 1
+	}
+	checks, err := service.NewMSISDNService(msrepo, portability.NewStore()).SelfCheck()
+	if err != nil{
+		fmt.Fprintln(os.Stderr, "Error checking numbering plan:", err)
+		return 1
+	}
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	failed := 0
+	for _, check := range *checks{
+		if len(check.Problems) != 0{
+			failed++
+		}else if !*all{
+			continue
+		}
+		problems := "ok"
+		if len(check.Problems) != 0{
+			problems = strings.Join(check.Problems, ",")
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", problems, check.Kind, check.CountryIdentifier, check.Pattern, check.MNO, check.Example, check.Detail)
+	}
+	table.Flush()
+	fmt.Printf("Checked %d rules, %d with problems\n", len(*checks), failed)
+	if failed != 0{
+		return 1
+	}
+	return 0
+}
+
+func printPlanDiff(out io.Writer, diff *model.PlanDiff){
+
+	table := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	for _, change := range diff.Changes{
+		row := ""
+		if change.Row != 0{
+			row = fmt.Sprintf("row %d", change.Row)
+		}
+		from := ""
+		if change.EffectiveFrom != nil{
+			from = "from " + change.EffectiveFrom.Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", change.Change, change.Kind, row, change.CountryIdentifier, change.Pattern, from, change.Details)
+	}
+	table.Flush()
+	for _, overlap := range diff.Overlaps{
+		fmt.Fprintf(out, "overlap: %s and %s both match %s, resolved to %s\n", overlap.First, overlap.Second, overlap.Example, overlap.Winner)
+	}
+
+	outcome := "Dry run"
+	if diff.Applied{
+		outcome = "Imported"
+	}
+	fmt.Fprintf(out, "%s: %d added, %d changed, %d removed, %d invalid\n", outcome, diff.Added, diff.Changed, diff.Removed, diff.Invalid)
+}
@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -11,6 +14,7 @@ import (
 	"github.com/robesmi/MSISDNApp/model"
 	"github.com/robesmi/MSISDNApp/model/dto"
 	"github.com/robesmi/MSISDNApp/model/errs"
+	"github.com/robesmi/MSISDNApp/planfile"
 	"github.com/robesmi/MSISDNApp/service"
 	"github.com/robesmi/MSISDNApp/vault"
 	"github.com/rs/zerolog"
@@ -19,6 +23,8 @@ import (
 type AdminActionsHandler struct {
 	AuthService service.AuthService
 	MSISDNService service.MSISDNService
+	PortingService service.PortingService
+	PlanService service.PlanService
 	Logger zerolog.Logger
 	Vault vault.VaultInterface
 }
@@ -168,29 +174,28 @@ func (adh AdminActionsHandler) InsertNewCountry(c *gin.Context){
 		return
 	}
 	
-	numberRegex := regexp.MustCompile(`^\d{1}$`)
-	if !numberRegex.MatchString(cReq.CountryCodeLength){
+	if invalid := validateCountryRequest(&cReq); invalid != nil{
 		c.HTML(http.StatusBadRequest, "adminpanel.html", gin.H{
-			"error": "The Country Code Length must be a number",
+			"error": invalid.Error(),
 		})
 		return
 	}
-	codeRegex := regexp.MustCompile(`^\d{1,6}$`)
-	if !codeRegex.MatchString(cReq.CountryCode){
+
+	addErr := adh.MSISDNService.AddNewCountry(&cReq)
+	if isInvalidRule(addErr){
 		c.HTML(http.StatusBadRequest, "adminpanel.html", gin.H{
-			"error": "The Country Code can't be longer than 6 digits",
+			"error": "Error adding new country: " + addErr.Error(),
+			"prevCountryRequest" : cReq,
 		})
 		return
 	}
-	ciRegex := regexp.MustCompile(`^[a-zA-Z]{2}$`)
-	if !ciRegex.MatchString(cReq.CountryIdentifier){
-		c.HTML(http.StatusBadRequest, "adminpanel.html", gin.H{
-			"error": "The Country identifier must be 2 letters",
+	if _, ok := addErr.(*errs.RuleOverlapError); ok{
+		c.HTML(http.StatusConflict, "adminpanel.html", gin.H{
+			"error": "Error adding new country: " + addErr.Error(),
+			"prevCountryRequest" : cReq,
 		})
 		return
 	}
-
-	addErr := adh.MSISDNService.AddNewCountry(&cReq)
 	if addErr != nil{
 		c.HTML(http.StatusInternalServerError, "adminpanel.html", gin.H{
 			"error": "Internal error adding new country, please try again " + addErr.Error(),
@@ -218,30 +223,14 @@ func (adh AdminActionsHandler) GetAllCountries(c *gin.Context){
 	})
 }
 
+// RemoveCountry ends the country rule with the posted pattern at the optional
+// effective end date, or right away when there isn't one
 func (adh AdminActionsHandler) RemoveCountry(c *gin.Context){
 
-	countryPrefixParam, err := io.ReadAll(c.Request.Body)
-	if err != nil {
-		adh.Logger.Error().Err(err).Str("package","handlers").Str("context","RemoveCountry").Msg("Error reading POST body")
-		c.HTML(http.StatusBadRequest, "adminpanel.html", gin.H{
-			"error": "Internal Error: " + err.Error(),
-		})
-		return
-	}
-	countryPrefix := strings.Split(string(countryPrefixParam),"=")[1]
-	decodedPrefix, escErr := url.QueryUnescape(countryPrefix)
-	if escErr != nil{
-		adh.Logger.Error().Err(err).Str("package","handlers").Str("context","RemoveOperator").Msg("Error decoding uri characters")
-		c.HTML(http.StatusBadRequest, "adminpanel.html", gin.H{
-			"error": "Internal Error: " + err.Error(),
-		})
-		return
-	}
-	
-	rmErr := adh.MSISDNService.RemoveCountry(decodedPrefix)
+	rmErr := adh.MSISDNService.RemoveCountry(c.PostForm("countryformat"), c.PostForm("effectiveto"))
 	if rmErr != nil {
 		c.HTML(http.StatusBadRequest, "adminpanel.html", gin.H{
-			"error": "Internal Error: " + rmErr.Error(),
+			"error": "Error removing country: " + rmErr.Error(),
 		})
 		return
 	}
@@ -262,22 +251,28 @@ func (adh AdminActionsHandler) InsertNewMobileOperator(c *gin.Context){
 	}
 
 
-	numberRegex := regexp.MustCompile(`[0-9]{1}`)
-	if !numberRegex.MatchString(mnoReq.PrefixLength){
+	if invalid := validateOperatorRequest(&mnoReq); invalid != nil{
 		c.HTML(http.StatusBadRequest, "adminpanel.html", gin.H{
-			"error": "The Prefix length must be a number",
+			"error": invalid.Error(),
 		})
 		return
 	}
-	ciRegex := regexp.MustCompile(`[a-zA-Z]{2}`)
-	if !ciRegex.MatchString(mnoReq.CountryIdentifier){
+
+	addErr := adh.MSISDNService.AddNewMobileOperator(&mnoReq)
+	if isInvalidRule(addErr){
 		c.HTML(http.StatusBadRequest, "adminpanel.html", gin.H{
-			"error": "The Country identifier must be 2 letters",
+			"error": "Error adding operator: " + addErr.Error(),
+			"prevCountryRequest" : mnoReq,
+		})
+		return
+	}
+	if _, ok := addErr.(*errs.RuleOverlapError); ok{
+		c.HTML(http.StatusConflict, "adminpanel.html", gin.H{
+			"error": "Error adding operator: " + addErr.Error(),
+			"prevCountryRequest" : mnoReq,
 		})
 		return
 	}
-
-	addErr := adh.MSISDNService.AddNewMobileOperator(&mnoReq)
 	if addErr != nil{
 		c.HTML(http.StatusInternalServerError, "adminpanel.html", gin.H{
 			"error": "Internal error adding operator, please try again " + addErr.Error(),
@@ -305,32 +300,371 @@ func (adh AdminActionsHandler) GetAllMobileOperators(c *gin.Context){
 	})
 }
 
+// GetOverlaps lists every pair of rules that match a common number, with the rule
+// lookups resolve such numbers to
+func (adh AdminActionsHandler) GetOverlaps(c *gin.Context){
+
+	overlaps, err := adh.MSISDNService.GetOverlaps()
+	if err != nil{
+		c.HTML(http.StatusInternalServerError, "adminpanel.html", gin.H{
+			"error": "Internal error: " + err.Error(),
+		})
+		return
+	}
+
+	c.HTML(http.StatusOK, "adminpanel.html", gin.H{
+		"overlaps" : overlaps,
+		"message" : fmt.Sprintf("Found %d overlapping rules", len(*overlaps)),
+	})
+}
+
+// SaveCountryDetails adds the posted details of a country or replaces the ones it has
+func (adh AdminActionsHandler) SaveCountryDetails(c *gin.Context){
+
+	detailsReq := dto.CountryDetailsRequest{}
+	if err := c.ShouldBind(&detailsReq); err != nil{
+		adh.Logger.Error().Err(err).Str("package","handlers").Str("context","SaveCountryDetails").Msg("Error saving country details from admin panel")
+		c.HTML(http.StatusBadRequest, "adminpanel.html", gin.H{
+			"error": "Error saving country details " + err.Error(),
+		})
+		return
+	}
+
+	saveErr := adh.MSISDNService.SaveCountryDetails(&detailsReq)
+	if saveErr != nil{
+		if _, ok := saveErr.(*errs.InvalidCountryDetailsError); ok{
+			c.HTML(http.StatusBadRequest, "adminpanel.html", gin.H{
+				"error": saveErr.Error(),
+			})
+			return
+		}
+		c.HTML(http.StatusInternalServerError, "adminpanel.html", gin.H{
+			"error": "Internal error saving country details, please try again " + saveErr.Error(),
+		})
+		return
+	}
+
+	c.Redirect( http.StatusFound, "/admin/panel")
+}
+
+// GetCacheStats shows the counters of the lookup cache
+func (adh AdminActionsHandler) GetCacheStats(c *gin.Context){
+
+	stats := adh.MSISDNService.GetCacheStats()
+	c.HTML(http.StatusOK, "adminpanel.html", gin.H{
+		"message" : fmt.Sprintf("Lookup cache: %d entries, %d hits (%d negative), %d misses, %.1f%% hit ratio, %d evictions, %d invalidations",
+			stats.Entries, stats.Hits, stats.NegativeHits, stats.Misses, stats.HitRatio() * 100, stats.Evictions, stats.Invalidations),
+	})
+}
+
+// SelfCheck looks up an example number of every rule in effect and lists the rules
+// with problems
+func (adh AdminActionsHandler) SelfCheck(c *gin.Context){
+
+	checks, err := adh.MSISDNService.SelfCheck()
+	if err != nil{
+		c.HTML(http.StatusInternalServerError, "adminpanel.html", gin.H{
+			"error": "Internal error: " + err.Error(),
+		})
+		return
+	}
+
+	var failed []model.RuleCheck
+	for _, check := range *checks{
+		if len(check.Problems) != 0{
+			failed = append(failed, check)
+		}
+	}
+	c.HTML(http.StatusOK, "adminpanel.html", gin.H{
+		"checks" : failed,
+		"message" : fmt.Sprintf("Checked %d rules, %d with problems", len(*checks), len(failed)),
+	})
+}
+
+// RemoveOperator ends the operator rule with the posted pattern at the optional
+// effective end date, or right away when there isn't one
 func (adh AdminActionsHandler) RemoveOperator(c *gin.Context){
 
-	operatorPrefixParam, err := io.ReadAll(c.Request.Body)
-	if err != nil {
-		adh.Logger.Error().Err(err).Str("package","handlers").Str("context","RemoveOperator").Msg("Error reading POST body")
+	rmErr := adh.MSISDNService.RemoveOperator(c.PostForm("prefixformat"), c.PostForm("effectiveto"))
+	if rmErr != nil {
 		c.HTML(http.StatusBadRequest, "adminpanel.html", gin.H{
-			"error": "Internal Error: " + err.Error(),
+			"error": "Error removing operator: " + rmErr.Error(),
 		})
 		return
 	}
-	operatorPrefix := strings.Split(string(operatorPrefixParam),"=")[1]
-	decodedPrefix, escErr := url.QueryUnescape(operatorPrefix)
-	if escErr != nil{
-		adh.Logger.Error().Err(err).Str("package","handlers").Str("context","RemoveOperator").Msg("Error decoding uri characters")
+
+	c.Redirect( http.StatusFound, "/admin/panel")
+}
+
+func (adh AdminActionsHandler) InsertNewNetworkOperator(c *gin.Context){
+
+	opReq := dto.NetworkOperatorRequest{}
+	err := c.ShouldBind(&opReq)
+	if err != nil{
+		adh.Logger.Error().Err(err).Str("package","handlers").Str("context","InsertNewNetworkOperator").Msg("Error adding new network operator from admin panel")
 		c.HTML(http.StatusBadRequest, "adminpanel.html", gin.H{
-			"error": "Internal Error: " + err.Error(),
+			"error": "Error adding new network operator" + err.Error(),
+		})
+		return
+	}
+
+	if strings.TrimSpace(opReq.BrandName) == ""{
+		c.HTML(http.StatusBadRequest, "adminpanel.html", gin.H{
+			"error": "The Brand Name can't be empty",
+		})
+		return
+	}
+	if opReq.Status != "" && !model.IsOperatorStatus(opReq.Status){
+		c.HTML(http.StatusBadRequest, "adminpanel.html", gin.H{
+			"error": "Unknown operating status " + opReq.Status,
+		})
+		return
+	}
+	codesRegex := regexp.MustCompile(`^(\d{3}-\d{2,3}( *, *\d{3}-\d{2,3})*)?$`)
+	if !codesRegex.MatchString(strings.TrimSpace(opReq.NetworkCodes)){
+		c.HTML(http.StatusBadRequest, "adminpanel.html", gin.H{
+			"error": "The Network Codes must be a comma separated list of MCC-MNC pairs like 268-01",
+		})
+		return
+	}
+	hostRegex := regexp.MustCompile(`^\d*$`)
+	if !hostRegex.MatchString(opReq.HostOperatorID){
+		c.HTML(http.StatusBadRequest, "adminpanel.html", gin.H{
+			"error": "The Host Operator must be empty or the ID of an operator",
 		})
 		return
 	}
-	rmErr := adh.MSISDNService.RemoveOperator(decodedPrefix)
+
+	addErr := adh.MSISDNService.AddNewNetworkOperator(&opReq)
+	if addErr != nil{
+		c.HTML(http.StatusInternalServerError, "adminpanel.html", gin.H{
+			"error": "Internal error adding network operator, please try again " + addErr.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/admin/panel")
+}
+
+func (adh AdminActionsHandler) GetAllNetworkOperators(c *gin.Context){
+
+	networkOperators, err := adh.MSISDNService.GetAllNetworkOperators()
+	if err != nil{
+		c.HTML(http.StatusInternalServerError, "adminpanel.html", gin.H{
+			"error": "Internal error: " + err.Error(),
+		})
+		return
+	}
+
+	c.HTML(http.StatusOK, "adminpanel.html", gin.H{
+		"networkOperators" : networkOperators,
+	})
+}
+
+// RemoveNetworkOperator removes a network operator, which fails while ranges or
+// MVNOs still reference it
+func (adh AdminActionsHandler) RemoveNetworkOperator(c *gin.Context){
+
+	rmErr := adh.MSISDNService.RemoveNetworkOperator(c.PostForm("id"))
 	if rmErr != nil {
 		c.HTML(http.StatusBadRequest, "adminpanel.html", gin.H{
-			"error": "Internal Error: " + rmErr.Error(),
+			"error": "Error removing network operator, make sure no ranges or MVNOs reference it: " + rmErr.Error(),
 		})
 		return
 	}
 
-	c.Redirect( http.StatusFound, "/admin/panel")
-}
\ No newline at end of file
+	c.Redirect(http.StatusFound, "/admin/panel")
+}
+
+// LoadPortedNumbers takes a CSV portability export with a number and the operator it was
+// ported to on each row, either replacing every ported number or updating them incrementally
+func (adh AdminActionsHandler) LoadPortedNumbers(c *gin.Context){
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil{
+		c.HTML(http.StatusBadRequest, "adminpanel.html", gin.H{
+			"error": "Please select a portability file to upload",
+		})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil{
+		adh.Logger.Error().Err(err).Str("package","handlers").Str("context","LoadPortedNumbers").Msg("Error opening uploaded file")
+		c.HTML(http.StatusInternalServerError, "adminpanel.html", gin.H{
+			"error": "Internal Error: " + err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	replace := c.PostForm("mode") == "replace"
+	count, loadErr := adh.PortingService.LoadPortedNumbers(file, replace)
+	if loadErr != nil{
+		code := http.StatusInternalServerError
+		if _, ok := loadErr.(*errs.InvalidFileError); ok{
+			code = http.StatusBadRequest
+		}else{
+			adh.Logger.Error().Err(loadErr).Str("package","handlers").Str("context","LoadPortedNumbers").Msg("Error loading ported numbers")
+		}
+		c.HTML(code, "adminpanel.html", gin.H{
+			"error": "Error loading ported numbers: " + loadErr.Error(),
+		})
+		return
+	}
+
+	c.HTML(http.StatusOK, "adminpanel.html", gin.H{
+		"message": fmt.Sprintf("Loaded %d rows, %d numbers are ported", count, adh.PortingService.CountPortedNumbers()),
+	})
+}
+
+// ImportPlan compares an uploaded numbering plan file with the saved plan and shows
+// what it adds, changes and removes along with its invalid rows. The valid rows are
+// only saved when the import isn't a dry run
+func (adh AdminActionsHandler) ImportPlan(c *gin.Context){
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil{
+		c.HTML(http.StatusBadRequest, "adminpanel.html", gin.H{
+			"error": "Please select a numbering plan file to upload",
+		})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil{
+		adh.Logger.Error().Err(err).Str("package","handlers").Str("context","ImportPlan").Msg("Error opening uploaded file")
+		c.HTML(http.StatusInternalServerError, "adminpanel.html", gin.H{
+			"error": "Internal Error: " + err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	format := c.PostForm("format")
+	if format == ""{
+		format = planfile.FormatOf(fileHeader.Filename)
+	}
+	apply := c.PostForm("mode") == "apply"
+	diff, importErr := adh.PlanService.ImportPlan(file, format, apply)
+	if importErr != nil{
+		code := http.StatusInternalServerError
+		if _, ok := importErr.(*errs.InvalidFileError); ok{
+			code = http.StatusBadRequest
+		}else{
+			adh.Logger.Error().Err(importErr).Str("package","handlers").Str("context","ImportPlan").Msg("Error importing numbering plan")
+		}
+		c.HTML(code, "adminpanel.html", gin.H{
+			"error": "Error importing numbering plan: " + importErr.Error(),
+		})
+		return
+	}
+
+	outcome := "Dry run"
+	if diff.Applied{
+		outcome = "Imported"
+	}
+	c.HTML(http.StatusOK, "adminpanel.html", gin.H{
+		"message": fmt.Sprintf("%s: %d added, %d changed, %d removed, %d invalid", outcome, diff.Added, diff.Changed, diff.Removed, diff.Invalid),
+		"planChanges": diff.Changes,
+		"overlaps": diff.Overlaps,
+	})
+}
+
+// ExportPlan downloads the whole saved numbering plan as a file in the requested format
+func (adh AdminActionsHandler) ExportPlan(c *gin.Context){
+
+	format := c.DefaultPostForm("format", planfile.FormatJSON)
+	var file bytes.Buffer
+	err := adh.PlanService.ExportPlan(&file, format)
+	if err != nil{
+		code := http.StatusInternalServerError
+		if _, ok := err.(*errs.InvalidFileError); ok{
+			code = http.StatusBadRequest
+		}else{
+			adh.Logger.Error().Err(err).Str("package","handlers").Str("context","ExportPlan").Msg("Error exporting numbering plan")
+		}
+		c.HTML(code, "adminpanel.html", gin.H{
+			"error": "Error exporting numbering plan: " + err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=numbering-plan.%s", strings.ToLower(format)))
+	c.Data(http.StatusOK, planfile.ContentType(format), file.Bytes())
+}
+
+// validateCountryRequest checks the fields of a country the way the admin panel form
+// expects them, defaulting the international prefix to 00
+func validateCountryRequest(cReq *dto.CountryRequest) error{
+
+	numberRegex := regexp.MustCompile(`^\d?$`)
+	if !numberRegex.MatchString(cReq.CountryCodeLength){
+		return errors.New("The Country Code Length must be empty or a number")
+	}
+	codeRegex := regexp.MustCompile(`^\d{1,6}$`)
+	if !codeRegex.MatchString(cReq.CountryCode){
+		return errors.New("The Country Code can't be longer than 6 digits")
+	}
+	ciRegex := regexp.MustCompile(`^[a-zA-Z]{2}$`)
+	if !ciRegex.MatchString(cReq.CountryIdentifier){
+		return errors.New("The Country identifier must be 2 letters")
+	}
+	trunkRegex := regexp.MustCompile(`^\d{0,4}$`)
+	if !trunkRegex.MatchString(cReq.TrunkPrefix){
+		return errors.New("The Trunk Prefix must be empty or up to 4 digits")
+	}
+	if cReq.InternationalPrefix == ""{
+		cReq.InternationalPrefix = "00"
+	}
+	lengthRegex := regexp.MustCompile(`^\d{0,2}$`)
+	if !lengthRegex.MatchString(cReq.NSNMinLength) || !lengthRegex.MatchString(cReq.NSNMaxLength){
+		return errors.New("The National Number Lengths must be empty or numbers")
+	}
+	groupingRegex := regexp.MustCompile(`^[X0-9 :;().\-/]*$`)
+	if !groupingRegex.MatchString(cReq.NumberGrouping){
+		return errors.New("The Number Grouping can only contain X for digits, separators and prefixes like 2:X XXX XXXX")
+	}
+	intlRegex := regexp.MustCompile(`^\d{1,4}(,\d{1,4})*$`)
+	if !intlRegex.MatchString(cReq.InternationalPrefix){
+		return errors.New("The International Prefix must be a comma separated list of up to 4 digit prefixes")
+	}
+	priorityRegex := regexp.MustCompile(`^(-?\d{1,4})?$`)
+	if !priorityRegex.MatchString(cReq.Priority){
+		return errors.New("The Priority must be empty or a number")
+	}
+	return nil
+}
+
+// validateOperatorRequest checks the fields of a range the way the admin panel form expects them
+func validateOperatorRequest(mnoReq *dto.OperatorRequest) error{
+
+	numberRegex := regexp.MustCompile(`[0-9]{1}`)
+	if !numberRegex.MatchString(mnoReq.PrefixLength){
+		return errors.New("The Prefix length must be a number")
+	}
+	ciRegex := regexp.MustCompile(`[a-zA-Z]{2}`)
+	if !ciRegex.MatchString(mnoReq.CountryIdentifier){
+		return errors.New("The Country identifier must be 2 letters")
+	}
+
+	idRegex := regexp.MustCompile(`^\d+$`)
+	if !idRegex.MatchString(mnoReq.OperatorID){
+		return errors.New("Please select the operator the range is assigned to")
+	}
+
+	if mnoReq.NumberType != "" && !model.IsNumberType(mnoReq.NumberType){
+		return errors.New("Unknown number type " + mnoReq.NumberType)
+	}
+	priorityRegex := regexp.MustCompile(`^(-?\d{1,4})?$`)
+	if !priorityRegex.MatchString(mnoReq.Priority){
+		return errors.New("The Priority must be empty or a number")
+	}
+	return nil
+}
+
+// isInvalidRule reports whether a rule was rejected for its pattern or effective period
+func isInvalidRule(err error) bool{
+
+	_, invalidPattern := err.(*errs.InvalidPatternError)
+	_, invalidPeriod := err.(*errs.InvalidPeriodError)
+	return invalidPattern || invalidPeriod
+}
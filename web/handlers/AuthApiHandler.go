@@ -7,6 +7,7 @@ import (
 	"regexp"
 
 	"github.com/gin-gonic/gin"
+	"github.com/robesmi/MSISDNApp/apiformat"
 	"github.com/robesmi/MSISDNApp/model/errs"
 	"github.com/robesmi/MSISDNApp/service"
 	"github.com/robesmi/MSISDNApp/utils"
@@ -19,7 +20,7 @@ type AuthApiHandler struct {
 }
 
 type RefreshRequest struct{
-	RefreshToken string `json:"refresh_token"`
+	RefreshToken string `json:"refresh_token" xml:"refresh_token" yaml:"refresh_token"`
 }
  
 var	(
@@ -31,14 +32,15 @@ var	(
 // a new user, returning a pair of access/refresh tokens and a success json
 func (a AuthApiHandler) HandleNativeRegisterCall(c *gin.Context){
 	var login LoginForm
-	if err := c.Bind(&login); err != nil{
+	if err := apiformat.Bind(c, &login); err != nil{
+		c.AbortWithError(http.StatusBadRequest, err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	// Checking for any valid email address
 	emailRegex := regexp.MustCompile("[a-z0-9!#$%&'*+/=?^_`{|}~-]+(?:\\.[a-z0-9!#$%&'*+/=?^_`{|}~-]+)*@(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\\.)+[a-z0-9](?:[a-z0-9-]*[a-z0-9])?")
 	if !emailRegex.MatchString(login.Username){
-		c.JSON(http.StatusBadRequest, gin.H{
+		writeResponse(c, http.StatusBadRequest, gin.H{
 			"error": "Enter a valid email address",
 		})
 		return
@@ -48,7 +50,7 @@ func (a AuthApiHandler) HandleNativeRegisterCall(c *gin.Context){
 	// At least 8 characters, must contain 1 uppercase character, 1 lowercase character, 1 special character and 1 number
 	passwordRegex := regexp.MustCompile(`^(.{0,7}|[^0-9]*|[^A-Z]*|[^a-z]*|[a-zA-Z0-9]*)$`)
 		if passwordRegex.MatchString(login.Password){
-		c.JSON(http.StatusBadRequest, gin.H{
+		writeResponse(c, http.StatusBadRequest, gin.H{
 			"error": "Password must have at least 8 characters, contain at least 1 uppercase letter, 1 lower case letter,1 number and a special character.",
 		})
 		return
@@ -57,12 +59,12 @@ func (a AuthApiHandler) HandleNativeRegisterCall(c *gin.Context){
 	loginResp, err := a.Service.RegisterNativeUser(login.Username, login.Password,"user")
 	if err != nil{
 		if _,ok := err.(*errs.UserAlreadyExists); ok{
-			c.JSON(http.StatusBadRequest, gin.H{
+			writeResponse(c, http.StatusBadRequest, gin.H{
 				"error": "Email already in use",
 			})
 			return
 		}else{
-			c.JSON(http.StatusInternalServerError, gin.H{
+			writeResponse(c, http.StatusInternalServerError, gin.H{
 				"error": "Internal error, please try again",
 			})
 			return
@@ -72,7 +74,7 @@ func (a AuthApiHandler) HandleNativeRegisterCall(c *gin.Context){
 	c.SetCookie("access_token", loginResp.AccessToken, int(60 * 15),"/","localhost",false,true)
 	c.SetCookie("refresh_token", loginResp.RefreshToken, int(60 * 60 * 24),"/","localhost",false,true)
 
-	c.JSON(http.StatusOK, gin.H{
+	writeResponse(c, http.StatusOK, gin.H{
 		"status": "success",
 		"access_token" : loginResp.AccessToken,
 		"refresh_token" : loginResp.RefreshToken,
@@ -83,14 +85,15 @@ func (a AuthApiHandler) HandleNativeRegisterCall(c *gin.Context){
 // HandleNativeLogin will log in the users that choose to use a local account
 func (a AuthApiHandler) HandleNativeLoginCall(c *gin.Context){
 	var login LoginForm
-	if err := c.Bind(&login); err != nil{
+	if err := apiformat.Bind(c, &login); err != nil{
 		log.Println("Api error binding login form: " + err.Error())
+		c.AbortWithError(http.StatusBadRequest, err).SetType(gin.ErrorTypeBind)
 		return
 	}
 	
 	emailRegex := regexp.MustCompile("[a-z0-9!#$%&'*+/=?^_`{|}~-]+(?:\\.[a-z0-9!#$%&'*+/=?^_`{|}~-]+)*@(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\\.)+[a-z0-9](?:[a-z0-9-]*[a-z0-9])?")
 	if !emailRegex.MatchString(login.Username){
-		c.JSON(http.StatusBadRequest, gin.H{
+		writeResponse(c, http.StatusBadRequest, gin.H{
 			"error": "Enter a valid email address",
 		})
 		return
@@ -98,7 +101,7 @@ func (a AuthApiHandler) HandleNativeLoginCall(c *gin.Context){
 
 	passwordRegex := regexp.MustCompile(`^(.{0,7}|[^0-9]*|[^A-Z]*|[^a-z]*|[a-zA-Z0-9]*)$`)
 	if passwordRegex.MatchString(login.Password){
-		c.JSON(http.StatusBadRequest, gin.H{
+		writeResponse(c, http.StatusBadRequest, gin.H{
 			"error": "Password must have at least 8 characters, contain at least 1 uppercase letter, 1 lower case letter,1 number and a special character.",
 		})
 		return
@@ -107,12 +110,12 @@ func (a AuthApiHandler) HandleNativeLoginCall(c *gin.Context){
 	loginResp, err := a.Service.LoginNativeUser(login.Username, login.Password)
 	if err != nil{
 		if _,ok := err.(*errs.InvalidCredentials); ok{
-			c.JSON(http.StatusBadRequest, gin.H{
+			writeResponse(c, http.StatusBadRequest, gin.H{
 				"error": "Email or password is incorrect",
 			})
 			return
 		}else{
-			c.JSON(http.StatusInternalServerError, gin.H{
+			writeResponse(c, http.StatusInternalServerError, gin.H{
 				"error": "Internal error: " + err.Error(),
 			})
 			return
@@ -122,7 +125,7 @@ func (a AuthApiHandler) HandleNativeLoginCall(c *gin.Context){
 	c.SetCookie("access_token", loginResp.AccessToken, int(60 * 15),"/","localhost",false,true)
 	c.SetCookie("refresh_token", loginResp.RefreshToken, int(60 * 60 * 24),"/","localhost",false,true)
 
-	c.JSON(http.StatusOK, gin.H{
+	writeResponse(c, http.StatusOK, gin.H{
 		"status": "success",
 		"access_token" : loginResp.AccessToken,
 		"refresh_token" : loginResp.RefreshToken,
@@ -134,9 +137,9 @@ func (a AuthApiHandler) HandleNativeLoginCall(c *gin.Context){
 func (a AuthApiHandler) RefreshAccessTokenCall(c *gin.Context){
 	
 	var refToken RefreshRequest
-	err := c.ShouldBind(&refToken)
+	err := apiformat.Bind(c, &refToken)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
+		writeResponse(c, http.StatusBadRequest, gin.H{
 			"status": "failed",
 			"error": err.Error(),
 		})
@@ -159,7 +162,7 @@ func (a AuthApiHandler) RefreshAccessTokenCall(c *gin.Context){
 	}
 	c.SetCookie("access_token", resp.AccessToken, int(60 * 15),"/","localhost",false,true)
 	c.SetCookie("refresh_token", resp.RefreshToken, int(60 * 60 * 24),"/","localhost",false,true)
-	c.JSON(http.StatusOK, gin.H{
+	writeResponse(c, http.StatusOK, gin.H{
 		"status": "success",
 		"access_token" : resp.AccessToken,
 		"refresh_token" : resp.RefreshToken,
@@ -169,9 +172,9 @@ func (a AuthApiHandler) RefreshAccessTokenCall(c *gin.Context){
 func (a AuthApiHandler) LogOutCall(c *gin.Context) {
 
 	var refToken RefreshRequest
-	err := c.ShouldBind(&refToken)
+	err := apiformat.Bind(c, &refToken)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
+		writeResponse(c, http.StatusBadRequest, gin.H{
 			"status": "failed",
 			"error": err.Error(),
 		})
@@ -186,7 +189,7 @@ func (a AuthApiHandler) LogOutCall(c *gin.Context) {
 	if valErr != nil{
 		c.SetCookie("access_token", "", 0,"/","localhost",false,true)
 		c.SetCookie("refresh_token", "", 0,"/","localhost",false,true)
-		c.JSON(http.StatusOK, gin.H{
+		writeResponse(c, http.StatusOK, gin.H{
 			"status": "failed",
 			"error": valErr.Error(),
 		})
@@ -196,7 +199,7 @@ func (a AuthApiHandler) LogOutCall(c *gin.Context) {
 	if erro != nil{
 		c.SetCookie("access_token", "", 0,"/","localhost",false,true)
 		c.SetCookie("refresh_token", "", 0,"/","localhost",false,true)
-		c.JSON(http.StatusOK, gin.H{
+		writeResponse(c, http.StatusOK, gin.H{
 			"status": "failed",
 			"error": erro.Error(),
 		})
@@ -204,7 +207,7 @@ func (a AuthApiHandler) LogOutCall(c *gin.Context) {
 	}
 	c.SetCookie("access_token", "", 0,"/","localhost",false,true)
 	c.SetCookie("refresh_token", "", 0,"/","localhost",false,true)
-	c.JSON(http.StatusOK, gin.H{
+	writeResponse(c, http.StatusOK, gin.H{
 		"status": "success",
 	})
 }
\ No newline at end of file
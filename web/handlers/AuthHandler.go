@@ -45,8 +45,8 @@ func NewAuthHandler(service service.AuthService, logger zerolog.Logger, vault va
 }
 
 type LoginForm struct {
-	Username string `form:"username" json:"username"`
-	Password string `form:"password" json:"password"`
+	Username string `form:"username" json:"username" xml:"username" yaml:"username"`
+	Password string `form:"password" json:"password" xml:"password" yaml:"password"`
 }
 
 type GithubEmail struct{
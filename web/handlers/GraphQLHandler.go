@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/robesmi/MSISDNApp/model/errs"
+	"github.com/robesmi/MSISDNApp/service"
+	"github.com/robesmi/MSISDNApp/utils"
+	"github.com/robesmi/MSISDNApp/vault"
+	"github.com/rs/zerolog"
+)
+
+// GraphQLHandler answers GraphQL queries for lookups, country details and ranges, and
+// mutations changing the numbering plan
+type GraphQLHandler struct {
+	Service service.MSISDNService
+	Logger zerolog.Logger
+	Vault vault.VaultInterface
+	schema graphql.Schema
+}
+
+type GraphQLRequest struct {
+	Query string `json:"query"`
+	OperationName string `json:"operationName"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// viewerKey is the context key of the role of the caller, see viewer
+type viewerKey struct{}
+
+// viewer is the role of the access token a query was sent with, empty when there was
+// none, or the reason the token wasn't accepted
+type viewer struct {
+	role string
+	err error
+}
+
+func NewGraphQLHandler(service service.MSISDNService, logger zerolog.Logger, vault vault.VaultInterface) (*GraphQLHandler, error){
+	return newGraphQLHandler(service, logger, vault, true)
+}
+
+// NewReadOnlyGraphQLHandler answers the queries only, for nodes whose numbering plan
+// can't be changed
+func NewReadOnlyGraphQLHandler(service service.MSISDNService, logger zerolog.Logger, vault vault.VaultInterface) (*GraphQLHandler, error){
+	return newGraphQLHandler(service, logger, vault, false)
+}
+
+func newGraphQLHandler(service service.MSISDNService, logger zerolog.Logger, vault vault.VaultInterface, mutations bool) (*GraphQLHandler, error){
+
+	gh := &GraphQLHandler{Service: service, Logger: logger, Vault: vault}
+	schema, err := gh.newSchema(mutations)
+	if err != nil{
+		return nil, err
+	}
+	gh.schema = schema
+	return gh, nil
+}
+
+// Query executes the query in the body. Fields needing a role fail on their own with the
+// reason, so the rest of the query is still answered. Results are always JSON, as GraphQL
+// clients expect
+func (gh *GraphQLHandler) Query(c *gin.Context){
+
+	var req GraphQLRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Query) == ""{
+		c.JSON(http.StatusBadRequest, gin.H{ "error":"The body must be a JSON object with a query"})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), viewerKey{}, gh.viewer(c))
+	result := graphql.Do(graphql.Params{
+		Schema: gh.schema,
+		RequestString: req.Query,
+		VariableValues: req.Variables,
+		OperationName: req.OperationName,
+		Context: ctx,
+	})
+	// A query that can't be parsed or validated has no data at all
+	if result.Data == nil && result.HasErrors(){
+		c.JSON(http.StatusBadRequest, result)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// viewer reads the access token the same way the middleware does, from the authorization
+// header or the access_token cookie
+func (gh *GraphQLHandler) viewer(c *gin.Context) viewer{
+
+	var access_token string
+	fields := strings.Fields(c.Request.Header.Get("Authorization"))
+	if len(fields) == 2 && fields[0] == "Bearer"{
+		access_token = fields[1]
+	}else if cookie, err := c.Cookie("access_token"); err == nil{
+		access_token = cookie
+	}
+	if access_token == ""{
+		return viewer{}
+	}
+
+	claims, err := utils.ValidateAccessToken(gh.Vault, access_token)
+	if err != nil{
+		if _, ok := err.(*errs.ExpiredTokenError); ok{
+			return viewer{err: errors.New("Authorization token expired")}
+		}
+		gh.Logger.Error().Err(err).Str("package","handlers").Str("context","GraphQL").Msg("Token error")
+		return viewer{err: errors.New("Invalid access token, reauthorize.")}
+	}
+	role, _ := claims["role"].(string)
+	return viewer{role: role}
+}
+
+// authorize checks that the query was sent with the access token of one of the roles
+func authorize(ctx context.Context, roles ...string) error{
+
+	v, _ := ctx.Value(viewerKey{}).(viewer)
+	if v.err != nil{
+		return v.err
+	}
+	if v.role == ""{
+		return errors.New("No auth token")
+	}
+	for _, role := range roles{
+		if v.role == role{
+			return nil
+		}
+	}
+	return errors.New("Unauthorized")
+}
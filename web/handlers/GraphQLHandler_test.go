@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/robesmi/MSISDNApp/mocks/service"
+	mockvault "github.com/robesmi/MSISDNApp/mocks/vault"
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/dto"
+	"github.com/robesmi/MSISDNApp/utils"
+	"github.com/rs/zerolog"
+)
+
+var mockVault *mockvault.MockVaultInterface
+
+type graphQLResponse struct {
+	Data map[string]interface{} `json:"data"`
+	Errors []struct{
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func setupGraphQL(t *testing.T, w *httptest.ResponseRecorder) func(){
+
+	ctrl := gomock.NewController(t)
+	mockLookupService = service.NewMockMSISDNService(ctrl)
+	mockVault = mockvault.NewMockVaultInterface(ctrl)
+
+	// Sign and validate the tokens of the tests with a fresh key pair
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	private := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	publicDer, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	public := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDer})
+	mockVault.EXPECT().Fetch("appvars", "AccessTokenPrivateKey").Return(map[string]string{"AccessTokenPrivateKey": base64.StdEncoding.EncodeToString(private)}, nil).AnyTimes()
+	mockVault.EXPECT().Fetch("appvars", "AccessTokenPublicKey").Return(map[string]string{"AccessTokenPublicKey": base64.StdEncoding.EncodeToString(public)}, nil).AnyTimes()
+
+	gh, err := NewGraphQLHandler(mockLookupService, zerolog.Nop(), mockVault)
+	if err != nil{
+		t.Fatalf("Error building the GraphQL schema: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	ctx, router = gin.CreateTestContext(w)
+	router.POST("/graphql", gh.Query)
+
+	return func() {
+		ctx = nil
+		router = nil
+		defer ctrl.Finish()
+	}
+}
+
+// queryGraphQL posts the query with an access token of the role, or none when it's empty
+func queryGraphQL(t *testing.T, recorder *httptest.ResponseRecorder, query string, role string) graphQLResponse{
+
+	jsonVal, _ := json.Marshal(GraphQLRequest{Query: query})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBuffer(jsonVal))
+	req.Header.Set("Content-Type","application/json")
+	if role != ""{
+		token, err := utils.CreateAccessToken("user1", role, mockVault)
+		if err != nil{
+			t.Fatalf("Error creating an access token: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer " + token)
+	}
+	router.ServeHTTP(recorder, req)
+
+	var resp graphQLResponse
+	json.Unmarshal(recorder.Body.Bytes(), &resp)
+	return resp
+}
+
+func TestGraphQLLookup(t *testing.T) {
+
+	//Arrange
+	recorder := httptest.NewRecorder()
+	teardown := setupGraphQL(t, recorder)
+	defer teardown()
+
+	mockLookupService.EXPECT().LookupMSISDN("38977123456").Return(&dto.NumberLookupResponse{
+		MNO: "A1",
+		CC: "389",
+		CI: "mk",
+		NetworkCodes: []dto.NetworkCode{{MCC: "294", MNC: "01"}},
+		Country: &dto.CountryDetails{Name: "North Macedonia", Alpha2: "MK"},
+	}, nil)
+
+	//Act
+	resp := queryGraphQL(t, recorder, `{ lookup(number: "+389 77 123 456") { msisdn mno networkCodes { mcc mnc } country { name } } }`, "user")
+
+	//Assert
+	lookup, _ := resp.Data["lookup"].(map[string]interface{})
+	if recorder.Code != http.StatusOK || len(resp.Errors) != 0 || lookup["msisdn"] != "38977123456" || lookup["mno"] != "A1"{
+		t.Fatalf("Error in TestGraphQLLookup:\n expected a lookup of 38977123456\n got %d %s", recorder.Code, recorder.Body.String())
+	}
+	country, _ := lookup["country"].(map[string]interface{})
+	if country["name"] != "North Macedonia"{
+		t.Errorf("Error in TestGraphQLLookup:\n expected North Macedonia\n got %v", lookup["country"])
+	}
+}
+
+func TestGraphQLRoles(t *testing.T) {
+
+	tt := []struct{
+		Name string
+		Query string
+		Role string
+		Mock func()
+		ExpectedError string
+	}{
+		{
+			Name:			"Lookup without a token",
+			Query:			`{ lookup(number: "38977123456") { mno } }`,
+			ExpectedError:	"No auth token",
+		},
+		{
+			Name:			"Lookup as an admin",
+			Query:			`{ lookup(number: "38977123456") { mno } }`,
+			Role:			"admin",
+			ExpectedError:	"Unauthorized",
+		},
+		{
+			Name:			"Countries without a token",
+			Query:			`{ countries { alpha2 } }`,
+			Mock: func(){
+				mockLookupService.EXPECT().GetAllCountryDetails().Return(&[]dto.CountryDetails{{Name: "Austria", Alpha2: "AT"}}, nil)
+			},
+		},
+		{
+			Name:			"Operators as an admin",
+			Query:			`{ operators(country: "at") { mno } }`,
+			Role:			"admin",
+			Mock: func(){
+				mockLookupService.EXPECT().GetAllMobileOperators().Return(&[]model.MobileOperator{{CountryIdentifier: "at", MNO: "A1"}}, nil)
+			},
+		},
+		{
+			Name:			"Operators without a token",
+			Query:			`{ operators(country: "at") { mno } }`,
+			ExpectedError:	"No auth token",
+		},
+		{
+			Name:			"Removing a country as a user",
+			Query:			`mutation { removeCountry(pattern: "^389") }`,
+			Role:			"user",
+			ExpectedError:	"Unauthorized",
+		},
+		{
+			Name:			"Removing a country as an admin",
+			Query:			`mutation { removeCountry(pattern: "^389", effectiveTo: "2024-01-01") }`,
+			Role:			"admin",
+			Mock: func(){
+				mockLookupService.EXPECT().RemoveCountry("^389", "2024-01-01").Return(nil)
+			},
+		},
+	}
+
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Arrange
+			recorder := httptest.NewRecorder()
+			teardown := setupGraphQL(t, recorder)
+			defer teardown()
+			if test.Mock != nil{
+				test.Mock()
+			}
+
+			//Act
+			resp := queryGraphQL(t, recorder, test.Query, test.Role)
+
+			//Assert
+			if test.ExpectedError == "" && len(resp.Errors) != 0{
+				t.Errorf("Error in TestGraphQLRoles:\n expected no errors\n got %s", recorder.Body.String())
+			}
+			if test.ExpectedError != "" && (len(resp.Errors) != 1 || resp.Errors[0].Message != test.ExpectedError){
+				t.Errorf("Error in TestGraphQLRoles:\n expected %s\n got %s", test.ExpectedError, recorder.Body.String())
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
+
+func TestGraphQLAddNewCountry(t *testing.T) {
+
+	//Arrange
+	recorder := httptest.NewRecorder()
+	teardown := setupGraphQL(t, recorder)
+	defer teardown()
+
+	mockLookupService.EXPECT().AddNewCountry(&dto.CountryRequest{
+		CountryNumberFormat: "^389[0-9]{8}$",
+		CountryCode: "389",
+		CountryIdentifier: "mk",
+		TrunkPrefix: "0",
+		InternationalPrefix: "00",
+		NSNMaxLength: "8",
+		Priority: "1",
+	}).Return(nil)
+
+	//Act
+	resp := queryGraphQL(t, recorder, `mutation { addNewCountry(input: {countryIdentifier: "mk", countryCode: "389", pattern: "^389[0-9]{8}$", trunkPrefix: "0", nsnMaxLength: 8, priority: 1}) }`, "admin")
+
+	//Assert
+	if len(resp.Errors) != 0 || resp.Data["addNewCountry"] != true{
+		t.Errorf("Error in TestGraphQLAddNewCountry:\n expected true\n got %s", recorder.Body.String())
+	}
+}
+
+func TestGraphQLAddNewCountryInvalid(t *testing.T) {
+
+	//Arrange
+	recorder := httptest.NewRecorder()
+	teardown := setupGraphQL(t, recorder)
+	defer teardown()
+
+	//Act
+	resp := queryGraphQL(t, recorder, `mutation { addNewCountry(input: {countryIdentifier: "mkd", countryCode: "389", pattern: "^389"}) }`, "admin")
+
+	//Assert
+	if len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, "2 letters"){
+		t.Errorf("Error in TestGraphQLAddNewCountryInvalid:\n expected the country identifier to be rejected\n got %s", recorder.Body.String())
+	}
+}
+
+func TestGraphQLInvalidQuery(t *testing.T) {
+
+	//Arrange
+	recorder := httptest.NewRecorder()
+	teardown := setupGraphQL(t, recorder)
+	defer teardown()
+
+	//Act
+	resp := queryGraphQL(t, recorder, `{ lookup(number: 38977123456) { unknownField } }`, "")
+
+	//Assert
+	if recorder.Code != http.StatusBadRequest || len(resp.Errors) == 0{
+		t.Errorf("Error in TestGraphQLInvalidQuery:\n expected %d\n got %d %s", http.StatusBadRequest, recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestGraphQLReadOnly(t *testing.T) {
+
+	//Arrange
+	recorder := httptest.NewRecorder()
+	teardown := setupGraphQL(t, recorder)
+	defer teardown()
+	gh, err := NewReadOnlyGraphQLHandler(mockLookupService, zerolog.Nop(), mockVault)
+	if err != nil{
+		t.Fatalf("Error building the GraphQL schema: %v", err)
+	}
+	_, router = gin.CreateTestContext(recorder)
+	router.POST("/graphql", gh.Query)
+
+	//Act
+	resp := queryGraphQL(t, recorder, `mutation { removeCountry(pattern: "^389") }`, "admin")
+
+	//Assert
+	if recorder.Code != http.StatusBadRequest || len(resp.Errors) == 0{
+		t.Errorf("Error in TestGraphQLReadOnly:\n expected %d\n got %d %s", http.StatusBadRequest, recorder.Code, recorder.Body.String())
+	}
+}
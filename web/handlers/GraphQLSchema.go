@@ -0,0 +1,478 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/dto"
+	"github.com/robesmi/MSISDNApp/model/errs"
+)
+
+var networkCodeType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "NetworkCode",
+	Fields: graphql.Fields{
+		"mcc": &graphql.Field{Type: graphql.String},
+		"mnc": &graphql.Field{Type: graphql.String},
+	},
+})
+
+var numberFormatsType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "NumberFormats",
+	Fields: graphql.Fields{
+		"e164": &graphql.Field{Type: graphql.String},
+		"international": &graphql.Field{Type: graphql.String},
+		"national": &graphql.Field{Type: graphql.String},
+		"rfc3966": &graphql.Field{Type: graphql.String},
+	},
+})
+
+// countryType holds the details of a country, as returned by /api/countries
+var countryType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Country",
+	Fields: graphql.Fields{
+		"name": &graphql.Field{Type: graphql.String},
+		"nativeName": &graphql.Field{Type: graphql.String},
+		"alpha2": &graphql.Field{Type: graphql.String},
+		"alpha3": &graphql.Field{Type: graphql.String},
+		"numeric": &graphql.Field{Type: graphql.String},
+		"timeZones": &graphql.Field{Type: graphql.NewList(graphql.String)},
+		"currency": &graphql.Field{Type: graphql.String},
+		"region": &graphql.Field{Type: graphql.String},
+		"continent": &graphql.Field{Type: graphql.String},
+	},
+})
+
+var lookupType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Lookup",
+	Fields: graphql.Fields{
+		"msisdn": &graphql.Field{Type: graphql.String},
+		"mno": &graphql.Field{Type: graphql.String},
+		"operatorId": &graphql.Field{Type: graphql.Int},
+		"networkCodes": &graphql.Field{Type: graphql.NewList(networkCodeType)},
+		"hostNetwork": &graphql.Field{Type: graphql.String},
+		"countryCode": &graphql.Field{Type: graphql.String},
+		"subscriberNumber": &graphql.Field{Type: graphql.String},
+		"countryIdentifier": &graphql.Field{Type: graphql.String},
+		"numberType": &graphql.Field{Type: graphql.String},
+		"country": &graphql.Field{Type: countryType},
+		"ported": &graphql.Field{Type: graphql.Boolean},
+		"rangeHolder": &graphql.Field{Type: graphql.String},
+		"formats": &graphql.Field{Type: numberFormatsType},
+		"normalizationSteps": &graphql.Field{Type: graphql.NewList(graphql.String)},
+		"asOf": &graphql.Field{Type: graphql.String},
+	},
+})
+
+// operatorType is a range of the numbering plan
+var operatorType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Operator",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{Type: graphql.Int},
+		"countryIdentifier": &graphql.Field{Type: graphql.String},
+		"pattern": &graphql.Field{Type: graphql.String},
+		"excludedPattern": &graphql.Field{Type: graphql.String},
+		"operatorId": &graphql.Field{Type: graphql.Int},
+		"mno": &graphql.Field{Type: graphql.String},
+		"networkCodes": &graphql.Field{Type: graphql.NewList(networkCodeType)},
+		"hostNetwork": &graphql.Field{Type: graphql.String},
+		"prefixLength": &graphql.Field{Type: graphql.Int},
+		"numberType": &graphql.Field{Type: graphql.String},
+		"priority": &graphql.Field{Type: graphql.Int},
+		"effectiveFrom": &graphql.Field{Type: graphql.String},
+		"effectiveTo": &graphql.Field{Type: graphql.String},
+	},
+})
+
+// countryInputType has the fields of the country form of the admin panel
+var countryInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "CountryInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"countryIdentifier": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"countryCode": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"pattern": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"excludedPattern": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"countryCodeLength": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		"trunkPrefix": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"internationalPrefix": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"nsnMinLength": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		"nsnMaxLength": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		"numberGrouping": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"priority": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		"effectiveFrom": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"effectiveTo": &graphql.InputObjectFieldConfig{Type: graphql.String},
+	},
+})
+
+// operatorInputType has the fields of the range form of the admin panel
+var operatorInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "OperatorInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"countryIdentifier": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"pattern": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"excludedPattern": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"operatorId": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
+		"prefixLength": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
+		"numberType": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"priority": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		"effectiveFrom": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"effectiveTo": &graphql.InputObjectFieldConfig{Type: graphql.String},
+	},
+})
+
+// newSchema builds the schema with resolvers that apply the same role rules as the
+// middleware: lookups need a user's token like /service/api, ranges the token of a user
+// or admin like /service, and changes to the plan an admin's token like /admin, while
+// country details are public like /api/countries. The mutations are left out unless
+// they're asked for
+func (gh *GraphQLHandler) newSchema(mutations bool) (graphql.Schema, error){
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"lookup": &graphql.Field{
+				Type: lookupType,
+				Description: "Looks a number up, with the numbering plan in effect at the optional RFC 3339 asOf time",
+				Args: graphql.FieldConfigArgument{
+					"number": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"region": &graphql.ArgumentConfig{Type: graphql.String},
+					"asOf": &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: gh.resolveLookup,
+			},
+			"countries": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(countryType))),
+				Resolve: gh.resolveCountries,
+			},
+			"country": &graphql.Field{
+				Type: countryType,
+				Description: "The country with the ISO 3166-1 alpha-2, alpha-3 or numeric code, null when there's none",
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: gh.resolveCountry,
+			},
+			"operators": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(operatorType))),
+				Description: "The ranges of the country with the alpha-2 code, including expired and scheduled ones",
+				Args: graphql.FieldConfigArgument{
+					"country": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: gh.resolveOperators,
+			},
+		},
+	})
+	config := graphql.SchemaConfig{Query: query}
+	if !mutations{
+		return graphql.NewSchema(config)
+	}
+
+	removeArgs := graphql.FieldConfigArgument{
+		"pattern": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+		"effectiveTo": &graphql.ArgumentConfig{Type: graphql.String},
+	}
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"addNewCountry": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(countryInputType)},
+				},
+				Resolve: gh.resolveAddNewCountry,
+			},
+			"addNewMobileOperator": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(operatorInputType)},
+				},
+				Resolve: gh.resolveAddNewMobileOperator,
+			},
+			"removeCountry": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Description: "Ends the country rule with the pattern at effectiveTo, or right away",
+				Args: removeArgs,
+				Resolve: gh.resolveRemoveCountry,
+			},
+			"removeOperator": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Description: "Ends the range with the pattern at effectiveTo, or right away",
+				Args: removeArgs,
+				Resolve: gh.resolveRemoveOperator,
+			},
+		},
+	})
+
+	config.Mutation = mutation
+	return graphql.NewSchema(config)
+}
+
+func (gh *GraphQLHandler) resolveLookup(p graphql.ResolveParams) (interface{}, error){
+
+	if err := authorize(p.Context, "user"); err != nil{
+		return nil, err
+	}
+	var asOf *time.Time
+	if value := stringArg(p.Args, "asOf"); value != ""{
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil{
+			return nil, errors.New("asOf must be an RFC 3339 timestamp like 2023-06-01T12:00:00Z")
+		}
+		asOf = &parsed
+	}
+
+	msh := MSISDNLookupHandler{Service: gh.Service, Logger: gh.Logger}
+	normalized, err := msh.normalizeInput(stringArg(p.Args, "number"), stringArg(p.Args, "region"))
+	if err != nil{
+		return nil, err
+	}
+	response, err := msh.lookup(normalized.Number, asOf)
+	if err != nil{
+		switch err.(type) {
+		case *errs.NumberNotFoundError, *errs.NoCarriersFoundError:
+		default:
+			gh.Logger.Error().Err(err).Str("package","handlers").Str("context","GraphQL lookup").Msg("Error making lookup")
+		}
+		return nil, err
+	}
+	response.Normalization = normalized.Steps
+	return lookupObject(normalized.Number, response), nil
+}
+
+func (gh *GraphQLHandler) resolveCountries(p graphql.ResolveParams) (interface{}, error){
+
+	countries, err := gh.Service.GetAllCountryDetails()
+	if err != nil{
+		gh.Logger.Error().Err(err).Str("package","handlers").Str("context","GraphQL countries").Msg("Error listing countries")
+		return nil, err
+	}
+	objects := []map[string]interface{}{}
+	for i := range *countries{
+		objects = append(objects, countryObject(&(*countries)[i]))
+	}
+	return objects, nil
+}
+
+func (gh *GraphQLHandler) resolveCountry(p graphql.ResolveParams) (interface{}, error){
+
+	country, err := gh.Service.GetCountryDetails(stringArg(p.Args, "id"))
+	if err != nil{
+		if _, ok := err.(*errs.CountryNotFoundError); ok{
+			return nil, nil
+		}
+		gh.Logger.Error().Err(err).Str("package","handlers").Str("context","GraphQL country").Msg("Error getting country")
+		return nil, err
+	}
+	return countryObject(country), nil
+}
+
+func (gh *GraphQLHandler) resolveOperators(p graphql.ResolveParams) (interface{}, error){
+
+	if err := authorize(p.Context, "user", "admin"); err != nil{
+		return nil, err
+	}
+	operators, err := gh.Service.GetAllMobileOperators()
+	if err != nil{
+		gh.Logger.Error().Err(err).Str("package","handlers").Str("context","GraphQL operators").Msg("Error listing operators")
+		return nil, err
+	}
+	country := stringArg(p.Args, "country")
+	objects := []map[string]interface{}{}
+	for _, operator := range *operators{
+		if strings.EqualFold(operator.CountryIdentifier, country){
+			objects = append(objects, operatorObject(operator))
+		}
+	}
+	return objects, nil
+}
+
+func (gh *GraphQLHandler) resolveAddNewCountry(p graphql.ResolveParams) (interface{}, error){
+
+	if err := authorize(p.Context, "admin"); err != nil{
+		return nil, err
+	}
+	input, _ := p.Args["input"].(map[string]interface{})
+	cReq := dto.CountryRequest{
+		CountryNumberFormat: stringArg(input, "pattern"),
+		ExcludedFormat: stringArg(input, "excludedPattern"),
+		CountryCode: stringArg(input, "countryCode"),
+		CountryIdentifier: stringArg(input, "countryIdentifier"),
+		CountryCodeLength: intArg(input, "countryCodeLength"),
+		TrunkPrefix: stringArg(input, "trunkPrefix"),
+		InternationalPrefix: stringArg(input, "internationalPrefix"),
+		NSNMinLength: intArg(input, "nsnMinLength"),
+		NSNMaxLength: intArg(input, "nsnMaxLength"),
+		NumberGrouping: stringArg(input, "numberGrouping"),
+		Priority: intArg(input, "priority"),
+		EffectiveFrom: stringArg(input, "effectiveFrom"),
+		EffectiveTo: stringArg(input, "effectiveTo"),
+	}
+	if invalid := validateCountryRequest(&cReq); invalid != nil{
+		return nil, invalid
+	}
+	if err := gh.Service.AddNewCountry(&cReq); err != nil{
+		return nil, gh.planError(err, "GraphQL addNewCountry")
+	}
+	return true, nil
+}
+
+func (gh *GraphQLHandler) resolveAddNewMobileOperator(p graphql.ResolveParams) (interface{}, error){
+
+	if err := authorize(p.Context, "admin"); err != nil{
+		return nil, err
+	}
+	input, _ := p.Args["input"].(map[string]interface{})
+	mnoReq := dto.OperatorRequest{
+		CountryIdentifier: stringArg(input, "countryIdentifier"),
+		PrefixFormat: stringArg(input, "pattern"),
+		ExcludedFormat: stringArg(input, "excludedPattern"),
+		OperatorID: intArg(input, "operatorId"),
+		PrefixLength: intArg(input, "prefixLength"),
+		NumberType: stringArg(input, "numberType"),
+		Priority: intArg(input, "priority"),
+		EffectiveFrom: stringArg(input, "effectiveFrom"),
+		EffectiveTo: stringArg(input, "effectiveTo"),
+	}
+	if invalid := validateOperatorRequest(&mnoReq); invalid != nil{
+		return nil, invalid
+	}
+	if err := gh.Service.AddNewMobileOperator(&mnoReq); err != nil{
+		return nil, gh.planError(err, "GraphQL addNewMobileOperator")
+	}
+	return true, nil
+}
+
+func (gh *GraphQLHandler) resolveRemoveCountry(p graphql.ResolveParams) (interface{}, error){
+
+	if err := authorize(p.Context, "admin"); err != nil{
+		return nil, err
+	}
+	if err := gh.Service.RemoveCountry(stringArg(p.Args, "pattern"), stringArg(p.Args, "effectiveTo")); err != nil{
+		return nil, gh.planError(err, "GraphQL removeCountry")
+	}
+	return true, nil
+}
+
+func (gh *GraphQLHandler) resolveRemoveOperator(p graphql.ResolveParams) (interface{}, error){
+
+	if err := authorize(p.Context, "admin"); err != nil{
+		return nil, err
+	}
+	if err := gh.Service.RemoveOperator(stringArg(p.Args, "pattern"), stringArg(p.Args, "effectiveTo")); err != nil{
+		return nil, gh.planError(err, "GraphQL removeOperator")
+	}
+	return true, nil
+}
+
+// planError logs errors changing the plan that aren't caused by the rule itself
+func (gh *GraphQLHandler) planError(err error, context string) error{
+
+	if _, ok := err.(*errs.RuleOverlapError); !ok && !isInvalidRule(err){
+		gh.Logger.Error().Err(err).Str("package","handlers").Str("context",context).Msg("Error changing the numbering plan")
+	}
+	return err
+}
+
+func stringArg(args map[string]interface{}, name string) string{
+	value, _ := args[name].(string)
+	return value
+}
+
+// intArg returns the argument the way the admin panel form posts it, empty when it's not set
+func intArg(args map[string]interface{}, name string) string{
+
+	value, ok := args[name].(int)
+	if !ok{
+		return ""
+	}
+	return strconv.Itoa(value)
+}
+
+func lookupObject(msisdn string, response *dto.NumberLookupResponse) map[string]interface{}{
+
+	object := map[string]interface{}{
+		"msisdn": msisdn,
+		"mno": response.MNO,
+		"operatorId": response.OperatorID,
+		"networkCodes": networkCodeObjects(response.NetworkCodes),
+		"hostNetwork": response.HostNetwork,
+		"countryCode": response.CC,
+		"subscriberNumber": response.SN,
+		"countryIdentifier": response.CI,
+		"numberType": response.Type,
+		"ported": response.Ported,
+		"rangeHolder": response.RangeHolder,
+		"formats": map[string]interface{}{
+			"e164": response.Formats.E164,
+			"international": response.Formats.International,
+			"national": response.Formats.National,
+			"rfc3966": response.Formats.RFC3966,
+		},
+		"normalizationSteps": response.Normalization,
+		"asOf": formatTime(response.AsOf),
+	}
+	if response.Country != nil{
+		object["country"] = countryObject(response.Country)
+	}
+	return object
+}
+
+func countryObject(country *dto.CountryDetails) map[string]interface{}{
+	return map[string]interface{}{
+		"name": country.Name,
+		"nativeName": country.NativeName,
+		"alpha2": country.Alpha2,
+		"alpha3": country.Alpha3,
+		"numeric": country.Numeric,
+		"timeZones": country.TimeZones,
+		"currency": country.Currency,
+		"region": country.Region,
+		"continent": country.Continent,
+	}
+}
+
+func operatorObject(operator model.MobileOperator) map[string]interface{}{
+
+	var codes []dto.NetworkCode
+	for _, code := range strings.Split(operator.NetworkCodes, ","){
+		if mcc, mnc, ok := strings.Cut(strings.TrimSpace(code), "-"); ok{
+			codes = append(codes, dto.NetworkCode{MCC: mcc, MNC: mnc})
+		}
+	}
+	return map[string]interface{}{
+		"id": operator.ID,
+		"countryIdentifier": operator.CountryIdentifier,
+		"pattern": operator.PrefixFormat,
+		"excludedPattern": operator.ExcludedFormat,
+		"operatorId": operator.OperatorID,
+		"mno": operator.MNO,
+		"networkCodes": networkCodeObjects(codes),
+		"hostNetwork": operator.HostNetwork,
+		"prefixLength": operator.PrefixLength,
+		"numberType": operator.NumberType,
+		"priority": operator.Priority,
+		"effectiveFrom": formatTime(operator.EffectiveFrom),
+		"effectiveTo": formatTime(operator.EffectiveTo),
+	}
+}
+
+func networkCodeObjects(codes []dto.NetworkCode) []map[string]interface{}{
+
+	var objects []map[string]interface{}
+	for _, code := range codes{
+		objects = append(objects, map[string]interface{}{"mcc": code.MCC, "mnc": code.MNC})
+	}
+	return objects
+}
+
+// formatTime returns the time in RFC 3339, or nil when it's unbounded
+func formatTime(t *time.Time) interface{}{
+
+	if t == nil{
+		return nil
+	}
+	return t.Format(time.RFC3339)
+}
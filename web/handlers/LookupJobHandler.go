@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/robesmi/MSISDNApp/model/errs"
+	"github.com/robesmi/MSISDNApp/service"
+	"github.com/rs/zerolog"
+)
+
+type LookupJobHandler struct {
+	Service service.JobService
+	Logger zerolog.Logger
+}
+
+func (jh LookupJobHandler) GetJobsPage(c *gin.Context){
+
+	owner := c.GetString("id")
+	if owner == ""{
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+	jobs, err := jh.Service.GetJobsByOwner(owner)
+	if err != nil{
+		c.HTML(http.StatusInternalServerError, "jobs.html", gin.H{
+			"error": "Internal error: " + err.Error(),
+		})
+		return
+	}
+	c.HTML(http.StatusOK, "jobs.html", gin.H{
+		"jobs": jobs,
+	})
+}
+
+// CreateJob takes a CSV or plain text file from the lookup jobs page and queues a job for it
+func (jh LookupJobHandler) CreateJob(c *gin.Context){
+
+	owner := c.GetString("id")
+	if owner == ""{
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+	fileHeader, err := c.FormFile("file")
+	if err != nil{
+		c.HTML(http.StatusBadRequest, "jobs.html", gin.H{
+			"error": "Please select a file to upload",
+		})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil{
+		jh.Logger.Error().Err(err).Str("package","handlers").Str("context","CreateJob").Msg("Error opening uploaded file")
+		c.HTML(http.StatusInternalServerError, "jobs.html", gin.H{
+			"error": "Internal error: " + err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	job, createErr := jh.Service.CreateJob(owner, fileHeader.Filename, file)
+	if createErr != nil{
+		code := http.StatusInternalServerError
+		if _, ok := createErr.(*errs.InvalidFileError); ok{
+			code = http.StatusBadRequest
+		}
+		c.HTML(code, "jobs.html", gin.H{
+			"error": createErr.Error(),
+		})
+		return
+	}
+
+	jobs, _ := jh.Service.GetJobsByOwner(owner)
+	c.HTML(http.StatusOK, "jobs.html", gin.H{
+		"created": job,
+		"jobs": jobs,
+	})
+}
+
+func (jh LookupJobHandler) CancelJob(c *gin.Context){
+
+	owner := c.GetString("id")
+	if owner == ""{
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+	if err := jh.Service.CancelJob(owner, c.Param("id")); err != nil{
+		c.HTML(jobErrorCode(err), "jobs.html", gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.Redirect(http.StatusFound, "/service/jobs")
+}
+
+// CreateJobApi takes a CSV or plain text file as the multipart field "file" and
+// responds with the queued job
+func (jh LookupJobHandler) CreateJobApi(c *gin.Context){
+
+	owner := c.GetString("id")
+	if owner == ""{
+		writeResponse(c, http.StatusUnauthorized, map[string]string{ "error":"Invalid access token, reauthorize."})
+		return
+	}
+	fileHeader, err := c.FormFile("file")
+	if err != nil{
+		writeResponse(c, http.StatusBadRequest, map[string]string{ "error":"Please upload a file in the field \"file\""})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil{
+		jh.Logger.Error().Err(err).Str("package","handlers").Str("context","CreateJobApi").Msg("Error opening uploaded file")
+		writeResponse(c, http.StatusInternalServerError, map[string]string{ "error":"Internal error"})
+		return
+	}
+	defer file.Close()
+
+	job, createErr := jh.Service.CreateJob(owner, fileHeader.Filename, file)
+	if createErr != nil{
+		code := http.StatusInternalServerError
+		if _, ok := createErr.(*errs.InvalidFileError); ok{
+			code = http.StatusBadRequest
+		}
+		writeResponse(c, code, map[string]string{ "error": createErr.Error()})
+		return
+	}
+	writeResponse(c, http.StatusAccepted, job)
+}
+
+// GetJobStatusApi responds with the status and progress of a job
+func (jh LookupJobHandler) GetJobStatusApi(c *gin.Context){
+
+	job, err := jh.Service.GetJob(c.GetString("id"), c.Param("id"))
+	if err != nil{
+		writeResponse(c, jobErrorCode(err), map[string]string{ "error": err.Error()})
+		return
+	}
+	writeResponse(c, http.StatusOK, job)
+}
+
+func (jh LookupJobHandler) CancelJobApi(c *gin.Context){
+
+	if err := jh.Service.CancelJob(c.GetString("id"), c.Param("id")); err != nil{
+		writeResponse(c, jobErrorCode(err), map[string]string{ "error": err.Error()})
+		return
+	}
+	writeResponse(c, http.StatusAccepted, map[string]string{ "status":"cancelling"})
+}
+
+// DownloadJobResult sends the enriched CSV of a completed job as an attachment
+func (jh LookupJobHandler) DownloadJobResult(c *gin.Context){
+
+	id := c.Param("id")
+	result, err := jh.Service.OpenJobResult(c.GetString("id"), id)
+	if err != nil{
+		writeResponse(c, jobErrorCode(err), map[string]string{ "error": err.Error()})
+		return
+	}
+	defer result.Close()
+
+	c.Header("Content-Disposition", "attachment; filename=\"" + id + ".csv\"")
+	c.Header("Content-Type", "text/csv")
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, result); err != nil{
+		jh.Logger.Error().Err(err).Str("package","handlers").Str("context","DownloadJobResult").Msg("Error sending job results")
+	}
+}
+
+func jobErrorCode(err error) int{
+
+	switch err.(type) {
+	case *errs.JobNotFoundError:
+		return http.StatusNotFound
+	case *errs.JobNotFinishedError, *errs.JobFinishedError:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
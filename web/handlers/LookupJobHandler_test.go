@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/robesmi/MSISDNApp/mocks/service"
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/errs"
+	"github.com/rs/zerolog"
+)
+
+var mockJobService *service.MockJobService
+
+func setupJobs(t *testing.T, w *httptest.ResponseRecorder) func(){
+
+	ctrl := gomock.NewController(t)
+	mockJobService = service.NewMockJobService(ctrl)
+	jh := LookupJobHandler{mockJobService, zerolog.Nop()}
+
+	gin.SetMode(gin.TestMode)
+	ctx, router = gin.CreateTestContext(w)
+	router.Use(func(c *gin.Context){
+		c.Set("id", "owner1")
+	})
+	router.POST("/jobs", jh.CreateJobApi)
+	router.GET("/jobs/:id", jh.GetJobStatusApi)
+	router.POST("/jobs/:id/cancel", jh.CancelJobApi)
+
+	return func() {
+		ctx = nil
+		router = nil
+		defer ctrl.Finish()
+	}
+}
+
+func TestCreateJobApi(t *testing.T) {
+
+	//Arrange
+	recorder := httptest.NewRecorder()
+	teardown := setupJobs(t, recorder)
+	defer teardown()
+
+	job := model.LookupJob{ID: "job1", Status: model.JobQueued, Total: 1}
+	mockJobService.EXPECT().CreateJob("owner1", "numbers.csv", gomock.Any()).Return(&job, nil)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("file", "numbers.csv")
+	part.Write([]byte("38977123456\n"))
+	writer.Close()
+
+	//Act
+	req := httptest.NewRequest(http.MethodPost, "/jobs", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	router.ServeHTTP(recorder, req)
+
+	//Assert
+	if recorder.Code != http.StatusAccepted{
+		t.Errorf("Error in TestCreateJobApi:\n expected %d\n got %d", http.StatusAccepted, recorder.Code)
+	}
+}
+
+func TestCreateJobApiWithoutFile(t *testing.T) {
+
+	//Arrange
+	recorder := httptest.NewRecorder()
+	teardown := setupJobs(t, recorder)
+	defer teardown()
+
+	//Act
+	req := httptest.NewRequest(http.MethodPost, "/jobs", nil)
+	router.ServeHTTP(recorder, req)
+
+	//Assert
+	if recorder.Code != http.StatusBadRequest{
+		t.Errorf("Error in TestCreateJobApiWithoutFile:\n expected %d\n got %d", http.StatusBadRequest, recorder.Code)
+	}
+}
+
+func TestJobApiErrors(t *testing.T) {
+
+	tt := []struct{
+		Name string
+		Method string
+		Path string
+		Err error
+		ExpectedReturnCode int
+	}{
+		{
+			Name:				"Unknown job status",
+			Method:				http.MethodGet,
+			Path:				"/jobs/job1",
+			Err:				errs.NewJobNotFoundError(),
+			ExpectedReturnCode:	http.StatusNotFound,
+		},
+		{
+			Name:				"Cancel finished job",
+			Method:				http.MethodPost,
+			Path:				"/jobs/job1/cancel",
+			Err:				errs.NewJobFinishedError(),
+			ExpectedReturnCode:	http.StatusConflict,
+		},
+	}
+
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Arrange
+			recorder := httptest.NewRecorder()
+			teardown := setupJobs(t, recorder)
+			defer teardown()
+
+			mockJobService.EXPECT().GetJob("owner1", "job1").Return(nil, test.Err).AnyTimes()
+			mockJobService.EXPECT().CancelJob("owner1", "job1").Return(test.Err).AnyTimes()
+
+			//Act
+			req := httptest.NewRequest(test.Method, test.Path, nil)
+			router.ServeHTTP(recorder, req)
+
+			//Assert
+			if recorder.Code != test.ExpectedReturnCode{
+				t.Errorf("Error in TestJobApiErrors:\n expected %d\n got %d", test.ExpectedReturnCode, recorder.Code)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
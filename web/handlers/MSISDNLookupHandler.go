@@ -1,13 +1,21 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
-	"regexp"
-	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
+	"github.com/robesmi/MSISDNApp/apiformat"
+	"github.com/robesmi/MSISDNApp/model/dto"
+	"github.com/robesmi/MSISDNApp/model/errs"
+	"github.com/robesmi/MSISDNApp/normalize"
+	"github.com/robesmi/MSISDNApp/rpc"
 	"github.com/robesmi/MSISDNApp/service"
 	"github.com/rs/zerolog"
+	"google.golang.org/protobuf/proto"
 )
 
 type MSISDNLookupHandler struct {
@@ -16,11 +24,52 @@ type MSISDNLookupHandler struct {
 }
 type LookupRequest struct {
 	Number string `form:"number"`
+	Region string `form:"region"`
 }
 type ApiLookupRequest struct{
-	Number string `json:"number" xml:"number"`
+	Number string `json:"number" xml:"number" yaml:"number"`
+	Region string `json:"region" xml:"region" yaml:"region"`
+	// AsOf is an optional RFC 3339 timestamp to look the number up with the numbering plan in effect at
+	AsOf *time.Time `json:"as_of" xml:"as_of" yaml:"as_of"`
 }
 
+// ProtoMessage makes Protobuf bodies of the request a LookupRequest of the gRPC API
+func (req *ApiLookupRequest) ProtoMessage() proto.Message{
+	return &rpc.LookupRequest{}
+}
+
+// FromProto sets the request from a decoded LookupRequest
+func (req *ApiLookupRequest) FromProto(message proto.Message){
+
+	lookup := message.(*rpc.LookupRequest)
+	req.Number = lookup.GetNumber()
+	req.Region = lookup.GetRegion()
+	if lookup.GetAsOf() != nil{
+		asOf := lookup.GetAsOf().AsTime()
+		req.AsOf = &asOf
+	}
+}
+
+type ApiExtractRequest struct{
+	Text string `json:"text" xml:"text" yaml:"text"`
+	// Region is the country numbers written in national format belong to
+	Region string `json:"region" xml:"region" yaml:"region"`
+}
+type ApiBatchLookupRequest struct{
+	Numbers []string `json:"numbers" xml:"numbers" yaml:"numbers"`
+	Region string `json:"region" xml:"region" yaml:"region"`
+	AsOf *time.Time `json:"as_of" xml:"as_of" yaml:"as_of"`
+}
+
+const (
+	// maxBatchSize is the most numbers accepted in a single batch lookup call
+	maxBatchSize = 1000
+	// batchWorkers is how many lookups of a batch run at the same time
+	batchWorkers = 16
+	// maxExtractLength is the most characters of text scanned for numbers in a single call
+	maxExtractLength = 100000
+)
+
 func (msh MSISDNLookupHandler) GetMainPage(c *gin.Context){
 	c.HTML(http.StatusOK, "home.html", nil)
 }
@@ -39,81 +88,304 @@ func (msh MSISDNLookupHandler) NumberLookup(c *gin.Context){
 		})
 		return
 	}
-	if req.Number == ""{
-		//If no input
+	normalized, normErr := msh.normalizeInput(req.Number, req.Region)
+	if normErr != nil{
 		c.HTML(http.StatusBadRequest, "index.html", gin.H{
-			"error" : "Please enter a MSISDN",
+			"error" : normErr.Error(),
+			"region": req.Region,
 		})
 		return
 	}
 
-		number := strings.TrimLeft(req.Number,"0")
-		m1 := regexp.MustCompile(`\D`)
-		number = m1.ReplaceAllString(number,"")
-		var validNumberRegex = regexp.MustCompile(`^[0-9]{7,15}$`)
-		if !validNumberRegex.MatchString(number){
-			c.HTML(http.StatusBadRequest, "index.html", gin.H{
-				"error" : "The MSISDN must only contain digits and be 7-15 digits long",
-			})
-			return
-		}
-
-			// Execute service layer logic and receive a response
-			response, lookupErr := msh.Service.LookupMSISDN(number)
-			if lookupErr != nil{
-				msh.Logger.Error().Err(lookupErr).Str("package","handlers").Str("context","NumberLookupApi").Msg("Error making lookup")
-				c.HTML(http.StatusBadRequest, "index.html", gin.H{
-					"error" : lookupErr.Error(),
-				})
-				return
-			}
-			
-			// Send response back
-			c.HTML(http.StatusOK, "index.html", gin.H{
-				"mno" : response.MNO,
-				"cc" :	response.CC,
-				"sn":	response.SN,
-				"ci": response.CI,
-			})
+	// Execute service layer logic and receive a response
+	validation, lookupErr := msh.Service.ValidateMSISDN(normalized.Number)
+	if lookupErr != nil{
+		msh.Logger.Error().Err(lookupErr).Str("package","handlers").Str("context","NumberLookup").Msg("Error making lookup")
+		c.HTML(http.StatusBadRequest, "index.html", gin.H{
+			"error" : lookupErr.Error(),
+			"region": req.Region,
+		})
+		return
+	}
+	if validation.Result == nil{
+		c.HTML(http.StatusBadRequest, "index.html", gin.H{
+			"error" : validation.Reason,
+			"verdict": validation.Verdict,
+			"msisdn": normalized.Number,
+			"region": req.Region,
+		})
+		return
+	}
+	response := validation.Result
+	
+	// Send response back
+	c.HTML(http.StatusOK, "index.html", gin.H{
+		"mno" : response.MNO,
+		"cc" :	response.CC,
+		"sn":	response.SN,
+		"ci": response.CI,
+		"type": response.Type,
+		"ported": response.Ported,
+		"rangeHolder": response.RangeHolder,
+		"networkCodes": response.NetworkCodes,
+		"hostNetwork": response.HostNetwork,
+		"formats": response.Formats,
+		"msisdn": normalized.Number,
+		"verdict": validation.Verdict,
+		"steps": normalized.Steps,
+		"region": req.Region,
+	})
 }
 
 func (msh MSISDNLookupHandler) NumberLookupApi(c *gin.Context){
 
 	// Check for empty input, trim and validate number
 	var req ApiLookupRequest
-	if err := c.ShouldBind(&req); err != nil{
+	if err := apiformat.Bind(c, &req); err != nil{
 		writeResponse(c, http.StatusBadRequest, map[string]string{ "error":"API call type should be string"})
 		return
 	}
-	if req.Number == ""{
-		//If no input
-		writeResponse(c,http.StatusBadRequest,map[string]string{ "error":"Please enter a MSISDN"})
+	normalized, normErr := msh.normalizeInput(req.Number, req.Region)
+	if normErr != nil{
+		writeResponse(c, http.StatusBadRequest, map[string]string{ "error": normErr.Error()})
 		return
 	}
 
-		number := strings.TrimLeft(req.Number,"0")
-		m1 := regexp.MustCompile(`\D`)
-		number = m1.ReplaceAllString(number,"")
-		var validNumberRegex = regexp.MustCompile(`^[0-9]{7,15}$`)
-		if !validNumberRegex.MatchString(number){
-			writeResponse(c, http.StatusBadRequest, map[string]string{ "error":"The MSISDN must only contain digits and be 7-15 digits long"})
+	// Execute service layer logic and receive a response
+	response, lookupErr := msh.lookup(normalized.Number, req.AsOf)
+	if lookupErr != nil{
+		msh.Logger.Error().Err(lookupErr).Str("package","handlers").Str("context","NumberLookupApi").Msg("Error making lookup")
+		writeResponse(c,http.StatusBadRequest, map[string]string{ "error": lookupErr.Error()})
+		return
+	}
+	response.Normalization = normalized.Steps
+	
+	// Send response back
+	writeResponse(c, http.StatusOK, response)
+}
+
+// NumberValidateApi responds with a verdict on the validity of the number and the
+// reason for it, along with the lookup result when the number is valid
+func (msh MSISDNLookupHandler) NumberValidateApi(c *gin.Context){
+
+	var req ApiLookupRequest
+	if err := apiformat.Bind(c, &req); err != nil{
+		writeResponse(c, http.StatusBadRequest, map[string]string{ "error":"API call type should be string"})
+		return
+	}
+	normalized, normErr := msh.normalizeInput(req.Number, req.Region)
+	if normErr != nil{
+		writeResponse(c, http.StatusBadRequest, map[string]string{ "error": normErr.Error()})
+		return
+	}
+
+	var response *dto.ValidationResponse
+	var validateErr error
+	if req.AsOf != nil{
+		response, validateErr = msh.Service.ValidateMSISDNAt(normalized.Number, *req.AsOf)
+	}else{
+		response, validateErr = msh.Service.ValidateMSISDN(normalized.Number)
+	}
+	if validateErr != nil{
+		msh.Logger.Error().Err(validateErr).Str("package","handlers").Str("context","NumberValidateApi").Msg("Error validating number")
+		writeResponse(c, http.StatusInternalServerError, map[string]string{ "error": validateErr.Error()})
+		return
+	}
+	if response.Result != nil{
+		response.Result.Normalization = normalized.Steps
+	}
+	writeResponse(c, http.StatusOK, response)
+}
+
+// NumberPartialLookupApi responds with the countries and operators a number still being
+// typed can belong to and how many more digits it takes, to be called on every keystroke
+func (msh MSISDNLookupHandler) NumberPartialLookupApi(c *gin.Context){
+
+	var req ApiLookupRequest
+	if err := apiformat.Bind(c, &req); err != nil{
+		writeResponse(c, http.StatusBadRequest, map[string]string{ "error":"API call type should be string"})
+		return
+	}
+	region, regionErr := msh.region(req.Region)
+	if regionErr != nil{
+		writeResponse(c, http.StatusBadRequest, map[string]string{ "error": regionErr.Error()})
+		return
+	}
+	normalized, normErr := normalize.NormalizePartial(req.Number, region)
+	if normErr != nil{
+		writeResponse(c, http.StatusBadRequest, map[string]string{ "error": normErr.Error()})
+		return
+	}
+
+	var response *dto.PartialLookupResponse
+	var lookupErr error
+	if req.AsOf != nil{
+		response, lookupErr = msh.Service.LookupPartialAt(normalized.Number, *req.AsOf)
+	}else{
+		response, lookupErr = msh.Service.LookupPartial(normalized.Number)
+	}
+	if lookupErr != nil{
+		msh.Logger.Error().Err(lookupErr).Str("package","handlers").Str("context","NumberPartialLookupApi").Msg("Error making partial lookup")
+		writeResponse(c, http.StatusInternalServerError, map[string]string{ "error": lookupErr.Error()})
+		return
+	}
+	response.Normalization = normalized.Steps
+	writeResponse(c, http.StatusOK, response)
+}
+
+// NumberExtractApi responds with every phone number found in the text of the request,
+// along with its character offsets and lookup result, in the order they appear
+func (msh MSISDNLookupHandler) NumberExtractApi(c *gin.Context){
+
+	var req ApiExtractRequest
+	if err := apiformat.Bind(c, &req); err != nil{
+		writeResponse(c, http.StatusBadRequest, map[string]string{ "error":"API call type should be string"})
+		return
+	}
+	if utf8.RuneCountInString(req.Text) > maxExtractLength{
+		writeResponse(c, http.StatusBadRequest, map[string]string{ "error": fmt.Sprintf("The text can be at most %d characters long", maxExtractLength)})
+		return
+	}
+
+	response, err := msh.Service.ExtractMSISDNs(req.Text, req.Region)
+	if err != nil{
+		if _, ok := err.(*errs.CountryNotFoundError); ok{
+			writeResponse(c, http.StatusBadRequest, map[string]string{ "error": err.Error()})
 			return
+		}
+		msh.Logger.Error().Err(err).Str("package","handlers").Str("context","NumberExtractApi").Msg("Error extracting numbers")
+		writeResponse(c, http.StatusInternalServerError, map[string]string{ "error": err.Error()})
+		return
+	}
+	writeResponse(c, http.StatusOK, response)
+}
+
+// NumberLookupBatchApi looks up every number of the request concurrently and responds with
+// a result or an error for each of them, in the same order they were sent
+func (msh MSISDNLookupHandler) NumberLookupBatchApi(c *gin.Context){
 
+	var req ApiBatchLookupRequest
+	if err := apiformat.Bind(c, &req); err != nil{
+		writeResponse(c, http.StatusBadRequest, map[string]string{ "error":"API call type should be a list of strings"})
+		return
+	}
+	if len(req.Numbers) == 0{
+		writeResponse(c, http.StatusBadRequest, map[string]string{ "error":"Please enter at least one MSISDN"})
+		return
+	}
+	if len(req.Numbers) > maxBatchSize{
+		writeResponse(c, http.StatusBadRequest, map[string]string{ "error":fmt.Sprintf("A batch can contain at most %d numbers", maxBatchSize)})
+		return
+	}
+
+	var region *normalize.Region
+	if req.Region != ""{
+		var regionErr error
+		if region, regionErr = msh.Service.GetRegion(req.Region); regionErr != nil{
+			writeResponse(c, http.StatusBadRequest, map[string]string{ "error": regionErr.Error()})
+			return
 		}
+	}
 
-			// Execute service layer logic and receive a response
-			response, lookupErr := msh.Service.LookupMSISDN(number)
-			if lookupErr != nil{
-				msh.Logger.Error().Err(lookupErr).Str("package","handlers").Str("context","NumberLookupApi").Msg("Error making lookup")
-				writeResponse(c,http.StatusBadRequest, map[string]string{ "error": lookupErr.Error()})
-				return
+	results := make([]dto.BatchLookupItem, len(req.Numbers))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < batchWorkers && w < len(req.Numbers); w++ {
+		wg.Add(1)
+		go func(){
+			defer wg.Done()
+			for i := range jobs{
+				results[i] = msh.lookupBatchItem(req.Numbers[i], region, req.AsOf)
 			}
-			
-			// Send response back
-			writeResponse(c, http.StatusOK, response)
+		}()
+	}
+	for i := range req.Numbers{
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	writeResponse(c, http.StatusOK, dto.BatchLookupResponse{Results: results})
+}
+
+func (msh MSISDNLookupHandler) lookupBatchItem(input string, region *normalize.Region, asOf *time.Time) dto.BatchLookupItem{
+
+	item := dto.BatchLookupItem{Number: input}
+	normalized, normErr := normalize.Normalize(input, region)
+	if normErr != nil{
+		item.Error = normErr.Error()
+		return item
+	}
+	response, lookupErr := msh.lookup(normalized.Number, asOf)
+	if lookupErr != nil{
+		item.Error = lookupErr.Error()
+		return item
+	}
+	response.Normalization = normalized.Steps
+	item.Result = response
+	return item
+}
+
+// CountriesApi responds with the details of every country of the numbering plan
+func (msh MSISDNLookupHandler) CountriesApi(c *gin.Context){
+
+	countries, err := msh.Service.GetAllCountryDetails()
+	if err != nil{
+		msh.Logger.Error().Err(err).Str("package","handlers").Str("context","CountriesApi").Msg("Error listing countries")
+		writeResponse(c, http.StatusInternalServerError, map[string]string{ "error": err.Error()})
+		return
+	}
+	writeResponse(c, http.StatusOK, countries)
 }
 
+// CountryApi responds with the details of the country with the ISO 3166-1 alpha-2,
+// alpha-3 or numeric code in the path
+func (msh MSISDNLookupHandler) CountryApi(c *gin.Context){
+
+	country, err := msh.Service.GetCountryDetails(c.Param("code"))
+	if err != nil{
+		if _, ok := err.(*errs.CountryNotFoundError); ok{
+			writeResponse(c, http.StatusNotFound, map[string]string{ "error": err.Error()})
+			return
+		}
+		msh.Logger.Error().Err(err).Str("package","handlers").Str("context","CountryApi").Msg("Error getting country")
+		writeResponse(c, http.StatusInternalServerError, map[string]string{ "error": err.Error()})
+		return
+	}
+	writeResponse(c, http.StatusOK, country)
+}
+
+// lookup looks the MSISDN up with the current numbering plan, or with the one in
+// effect at the time when one is given
+func (msh MSISDNLookupHandler) lookup(msisdn string, asOf *time.Time) (*dto.NumberLookupResponse, error){
+
+	if asOf == nil{
+		return msh.Service.LookupMSISDN(msisdn)
+	}
+	return msh.Service.LookupMSISDNAt(msisdn, *asOf)
+}
+
+// normalizeInput turns the input into a MSISDN, reading numbers in national format
+// with the dialing rules of the region when one is given
+func (msh MSISDNLookupHandler) normalizeInput(input string, regionHint string) (*normalize.Result, error){
+
+	region, err := msh.region(regionHint)
+	if err != nil{
+		return nil, err
+	}
+	return normalize.Normalize(input, region)
+}
+
+// region returns the dialing rules of the region, or nil when none was given
+func (msh MSISDNLookupHandler) region(regionHint string) (*normalize.Region, error){
+
+	if regionHint == ""{
+		return nil, nil
+	}
+	return msh.Service.GetRegion(regionHint)
+}
 
+// writeResponse writes the data in the format the client accepts, see apiformat.Write
 func writeResponse(c *gin.Context,code int, data interface{}){
-	c.JSON(code,data)
+	apiformat.Write(c, code, data)
 }
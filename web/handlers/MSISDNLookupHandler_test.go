@@ -3,15 +3,24 @@ package handlers
 import (
 	"bytes"
 	"encoding/json"
+	"encoding/xml"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
+	"github.com/robesmi/MSISDNApp/apiformat"
 	"github.com/robesmi/MSISDNApp/mocks/service"
+	"github.com/robesmi/MSISDNApp/model"
+	"github.com/robesmi/MSISDNApp/model/dto"
 	"github.com/robesmi/MSISDNApp/model/errs"
+	"github.com/robesmi/MSISDNApp/normalize"
+	"github.com/robesmi/MSISDNApp/rpc"
 	"github.com/rs/zerolog"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 
@@ -34,17 +43,22 @@ func setup(t *testing.T, w *httptest.ResponseRecorder) func(){
 
 	gin.SetMode(gin.TestMode)
 	ctx, router = gin.CreateTestContext(w)
-	router.POST("/lookup", lh.NumberLookupApi)
+	router.POST("/lookup", apiformat.Negotiate(), lh.NumberLookupApi)
+	router.POST("/lookup/batch", apiformat.Negotiate(), lh.NumberLookupBatchApi)
+	router.POST("/validate", apiformat.Negotiate(), lh.NumberValidateApi)
+	router.POST("/lookup/partial", apiformat.Negotiate(), lh.NumberPartialLookupApi)
+	router.POST("/extract", apiformat.Negotiate(), lh.NumberExtractApi)
+	router.GET("/countries/:code", apiformat.Negotiate(), lh.CountryApi)
 
 	router.GET("/refresh", ah.RefreshAccessToken)
 	router.GET("/logout", ah.LogOut)
 	router.POST("/oauth/google/callback", ah.HandleGoogleCode)
 	router.GET("/oauth/github/callback", ah.HandleGithubCode)
 
-	router.POST("/service/api/register", aph.HandleNativeRegisterCall)
-	router.POST("/service/api/login", aph.HandleNativeLoginCall)
-	router.POST("/service/api/refresh", aph.RefreshAccessTokenCall)
-	router.POST("/service/api/logout", aph.LogOutCall)
+	router.POST("/service/api/register", apiformat.Negotiate(), aph.HandleNativeRegisterCall)
+	router.POST("/service/api/login", apiformat.Negotiate(), aph.HandleNativeLoginCall)
+	router.POST("/service/api/refresh", apiformat.Negotiate(), aph.RefreshAccessTokenCall)
+	router.POST("/service/api/logout", apiformat.Negotiate(), aph.LogOutCall)
 
 
 	return func() {
@@ -141,7 +155,7 @@ func TestNumberLookup(t *testing.T) {
 				if test.ExpectsError{
 					mockLookupService.EXPECT().LookupMSISDN(gomock.Any()).Return(nil, errs.NewUnexpectedError(""))
 				}else{
-					mockLookupService.EXPECT().LookupMSISDN(gomock.Any()).Return(nil, nil)
+					mockLookupService.EXPECT().LookupMSISDN(gomock.Any()).Return(&dto.NumberLookupResponse{}, nil)
 				}
 			}
 			jsonReq := LookupRequest{
@@ -162,4 +176,453 @@ func TestNumberLookup(t *testing.T) {
 		}
 		t.Run(test.Name, fn)
 	}
-}
\ No newline at end of file
+}
+
+func TestNumberLookupWithRegion(t *testing.T) {
+
+	//Arrange
+	recorder := httptest.NewRecorder()
+	teardown := setup(t,recorder)
+	defer teardown()
+
+	region := normalize.Region{CallingCode: "389", TrunkPrefix: "0", InternationalPrefixes: []string{"00"}}
+	found := dto.NumberLookupResponse{MNO: "Telekom", CC: "389", SN: "123456", CI: "mk"}
+	mockLookupService.EXPECT().GetRegion("mk").Return(&region, nil)
+	mockLookupService.EXPECT().LookupMSISDN("38970123456").Return(&found, nil)
+
+	jsonVal, _ := json.Marshal(ApiLookupRequest{Number: "070 123 456", Region: "mk"})
+
+	//Act
+	req := httptest.NewRequest(http.MethodPost,"/lookup",bytes.NewBuffer(jsonVal))
+	req.Header.Set("Content-Type","application/json")
+	router.ServeHTTP(recorder,req)
+
+	//Assert
+	if recorder.Code != http.StatusOK{
+		t.Fatalf("Error in TestNumberLookupWithRegion:\n expected %d\n got %d", http.StatusOK, recorder.Code)
+	}
+	var resp dto.NumberLookupResponse
+	json.Unmarshal(recorder.Body.Bytes(), &resp)
+	if len(resp.Normalization) == 0{
+		t.Errorf("Error in TestNumberLookupWithRegion:\n expected the normalization steps\n got %s", recorder.Body.String())
+	}
+}
+
+func TestNumberLookupAsOf(t *testing.T) {
+
+	//Arrange
+	recorder := httptest.NewRecorder()
+	teardown := setup(t,recorder)
+	defer teardown()
+
+	asOf := time.Date(2023, time.June, 1, 12, 0, 0, 0, time.UTC)
+	found := dto.NumberLookupResponse{MNO: "Telekom", CC: "389", SN: "123456", CI: "mk", AsOf: &asOf}
+	mockLookupService.EXPECT().LookupMSISDNAt("38977123456", asOf).Return(&found, nil)
+
+	//Act
+	req := httptest.NewRequest(http.MethodPost,"/lookup",bytes.NewBufferString(`{"number":"+38977123456","as_of":"2023-06-01T12:00:00Z"}`))
+	req.Header.Set("Content-Type","application/json")
+	router.ServeHTTP(recorder,req)
+
+	//Assert
+	if recorder.Code != http.StatusOK{
+		t.Fatalf("Error in TestNumberLookupAsOf:\n expected %d\n got %d", http.StatusOK, recorder.Code)
+	}
+	var resp dto.NumberLookupResponse
+	json.Unmarshal(recorder.Body.Bytes(), &resp)
+	if resp.AsOf == nil || !resp.AsOf.Equal(asOf){
+		t.Errorf("Error in TestNumberLookupAsOf:\n expected %s\n got %s", asOf, recorder.Body.String())
+	}
+}
+
+func TestNumberLookupXML(t *testing.T) {
+
+	//Arrange
+	recorder := httptest.NewRecorder()
+	teardown := setup(t,recorder)
+	defer teardown()
+
+	found := dto.NumberLookupResponse{MNO: "Telekom", CC: "389", SN: "123456", CI: "mk"}
+	mockLookupService.EXPECT().LookupMSISDN("38970123456").Return(&found, nil)
+
+	//Act
+	req := httptest.NewRequest(http.MethodPost,"/lookup",bytes.NewBufferString(`<request><number>+389 70 123 456</number></request>`))
+	req.Header.Set("Content-Type","application/xml")
+	req.Header.Set("Accept","application/xml")
+	router.ServeHTTP(recorder,req)
+
+	//Assert
+	var resp dto.NumberLookupResponse
+	xml.Unmarshal(recorder.Body.Bytes(), &resp)
+	if recorder.Code != http.StatusOK || resp.MNO != "Telekom" || len(resp.Normalization) == 0{
+		t.Errorf("Error in TestNumberLookupXML:\n expected %d Telekom\n got %d %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestNumberLookupNotAcceptable(t *testing.T) {
+
+	//Arrange
+	recorder := httptest.NewRecorder()
+	teardown := setup(t,recorder)
+	defer teardown()
+
+	// The number isn't looked up, since the response couldn't be sent
+
+	//Act
+	req := httptest.NewRequest(http.MethodPost,"/lookup",bytes.NewBufferString(`{"number":"38970123456"}`))
+	req.Header.Set("Content-Type","application/json")
+	req.Header.Set("Accept","text/html")
+	router.ServeHTTP(recorder,req)
+
+	//Assert
+	if recorder.Code != http.StatusNotAcceptable{
+		t.Errorf("Error in TestNumberLookupNotAcceptable:\n expected %d\n got %d", http.StatusNotAcceptable, recorder.Code)
+	}
+}
+
+func TestNumberLookupUnknownRegion(t *testing.T) {
+
+	//Arrange
+	recorder := httptest.NewRecorder()
+	teardown := setup(t,recorder)
+	defer teardown()
+
+	mockLookupService.EXPECT().GetRegion("zz").Return(nil, errs.NewCountryNotFoundError())
+	jsonVal, _ := json.Marshal(ApiLookupRequest{Number: "070 123 456", Region: "zz"})
+
+	//Act
+	req := httptest.NewRequest(http.MethodPost,"/lookup",bytes.NewBuffer(jsonVal))
+	req.Header.Set("Content-Type","application/json")
+	router.ServeHTTP(recorder,req)
+
+	//Assert
+	if recorder.Code != http.StatusBadRequest{
+		t.Errorf("Error in TestNumberLookupUnknownRegion:\n expected %d\n got %d", http.StatusBadRequest, recorder.Code)
+	}
+}
+
+func TestNumberValidate(t *testing.T) {
+
+	//Arrange
+	recorder := httptest.NewRecorder()
+	teardown := setup(t,recorder)
+	defer teardown()
+
+	verdict := dto.ValidationResponse{Number: "3897712345", Verdict: model.ValidityTooShort, Reason: "too short", CountryIdentifier: "mk"}
+	mockLookupService.EXPECT().ValidateMSISDN("3897712345").Return(&verdict, nil)
+	jsonVal, _ := json.Marshal(ApiLookupRequest{Number: "+389 77 123 45"})
+
+	//Act
+	req := httptest.NewRequest(http.MethodPost,"/validate",bytes.NewBuffer(jsonVal))
+	req.Header.Set("Content-Type","application/json")
+	router.ServeHTTP(recorder,req)
+
+	//Assert
+	var resp dto.ValidationResponse
+	json.Unmarshal(recorder.Body.Bytes(), &resp)
+	if recorder.Code != http.StatusOK || resp.Verdict != model.ValidityTooShort{
+		t.Errorf("Error in TestNumberValidate:\n expected %d %s\n got %d %s", http.StatusOK, model.ValidityTooShort, recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestNumberValidateAsOf(t *testing.T) {
+
+	//Arrange
+	recorder := httptest.NewRecorder()
+	teardown := setup(t,recorder)
+	defer teardown()
+
+	asOf := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	verdict := dto.ValidationResponse{Number: "38977123456", Verdict: model.ValidityUnknownRange, Reason: "unknown range", CountryIdentifier: "mk"}
+	mockLookupService.EXPECT().ValidateMSISDNAt("38977123456", asOf).Return(&verdict, nil)
+	jsonVal, _ := json.Marshal(ApiLookupRequest{Number: "38977123456", AsOf: &asOf})
+
+	//Act
+	req := httptest.NewRequest(http.MethodPost,"/validate",bytes.NewBuffer(jsonVal))
+	req.Header.Set("Content-Type","application/json")
+	router.ServeHTTP(recorder,req)
+
+	//Assert
+	var resp dto.ValidationResponse
+	json.Unmarshal(recorder.Body.Bytes(), &resp)
+	if recorder.Code != http.StatusOK || resp.Verdict != model.ValidityUnknownRange{
+		t.Errorf("Error in TestNumberValidateAsOf:\n expected %d %s\n got %d %s", http.StatusOK, model.ValidityUnknownRange, recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestNumberValidateProtobuf(t *testing.T) {
+
+	//Arrange
+	recorder := httptest.NewRecorder()
+	teardown := setup(t,recorder)
+	defer teardown()
+
+	asOf := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	verdict := dto.ValidationResponse{Number: "38977123456", Verdict: model.ValidityUnknownRange, Reason: "unknown range", CountryIdentifier: "mk"}
+	mockLookupService.EXPECT().ValidateMSISDNAt("38977123456", asOf).Return(&verdict, nil)
+	body, _ := proto.Marshal(&rpc.LookupRequest{Number: "38977123456", AsOf: timestamppb.New(asOf)})
+
+	//Act
+	req := httptest.NewRequest(http.MethodPost,"/validate",bytes.NewBuffer(body))
+	req.Header.Set("Content-Type","application/x-protobuf")
+	router.ServeHTTP(recorder,req)
+
+	//Assert
+	var resp dto.ValidationResponse
+	json.Unmarshal(recorder.Body.Bytes(), &resp)
+	if recorder.Code != http.StatusOK || resp.Verdict != model.ValidityUnknownRange{
+		t.Errorf("Error in TestNumberValidateProtobuf:\n expected %d %s\n got %d %s", http.StatusOK, model.ValidityUnknownRange, recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestNumberPartialLookup(t *testing.T) {
+
+	//Arrange
+	recorder := httptest.NewRecorder()
+	teardown := setup(t,recorder)
+	defer teardown()
+
+	region := normalize.Region{CallingCode: "389", TrunkPrefix: "0", InternationalPrefixes: []string{"00"}}
+	partial := dto.PartialLookupResponse{
+		Number: "3897",
+		MinRemaining: 7,
+		MaxRemaining: 7,
+		Countries: []dto.PartialCountry{{CountryIdentifier: "mk", CountryCode: "389", MinRemaining: 7, MaxRemaining: 7}},
+		Operators: []dto.PartialOperator{{CountryIdentifier: "mk", MNO: "A1", Type: model.NumberTypeMobile, MinRemaining: 7, MaxRemaining: 7}},
+	}
+	mockLookupService.EXPECT().GetRegion("mk").Return(&region, nil)
+	mockLookupService.EXPECT().LookupPartial("3897").Return(&partial, nil)
+	jsonVal, _ := json.Marshal(ApiLookupRequest{Number: "07", Region: "mk"})
+
+	//Act
+	req := httptest.NewRequest(http.MethodPost,"/lookup/partial",bytes.NewBuffer(jsonVal))
+	req.Header.Set("Content-Type","application/json")
+	router.ServeHTTP(recorder,req)
+
+	//Assert
+	if recorder.Code != http.StatusOK{
+		t.Fatalf("Error in TestNumberPartialLookup:\n expected %d\n got %d", http.StatusOK, recorder.Code)
+	}
+	var resp dto.PartialLookupResponse
+	json.Unmarshal(recorder.Body.Bytes(), &resp)
+	if len(resp.Operators) != 1 || resp.MaxRemaining != 7 || len(resp.Normalization) == 0{
+		t.Errorf("Error in TestNumberPartialLookup:\n expected the candidates and normalization steps\n got %s", recorder.Body.String())
+	}
+}
+
+func TestNumberPartialLookupAsOf(t *testing.T) {
+
+	//Arrange
+	recorder := httptest.NewRecorder()
+	teardown := setup(t,recorder)
+	defer teardown()
+
+	asOf := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	partial := dto.PartialLookupResponse{Number: "3897", MinRemaining: 7, MaxRemaining: 7, AsOf: &asOf}
+	mockLookupService.EXPECT().LookupPartialAt("3897", asOf).Return(&partial, nil)
+	jsonVal, _ := json.Marshal(ApiLookupRequest{Number: "+3897", AsOf: &asOf})
+
+	//Act
+	req := httptest.NewRequest(http.MethodPost,"/lookup/partial",bytes.NewBuffer(jsonVal))
+	req.Header.Set("Content-Type","application/json")
+	router.ServeHTTP(recorder,req)
+
+	//Assert
+	var resp dto.PartialLookupResponse
+	json.Unmarshal(recorder.Body.Bytes(), &resp)
+	if recorder.Code != http.StatusOK || resp.AsOf == nil || !resp.AsOf.Equal(asOf){
+		t.Errorf("Error in TestNumberPartialLookupAsOf:\n expected %d as of %s\n got %d %s", http.StatusOK, asOf, recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestNumberPartialLookupEmpty(t *testing.T) {
+
+	//Arrange
+	recorder := httptest.NewRecorder()
+	teardown := setup(t,recorder)
+	defer teardown()
+
+	jsonVal, _ := json.Marshal(ApiLookupRequest{Number: "+"})
+
+	//Act
+	req := httptest.NewRequest(http.MethodPost,"/lookup/partial",bytes.NewBuffer(jsonVal))
+	req.Header.Set("Content-Type","application/json")
+	router.ServeHTTP(recorder,req)
+
+	//Assert
+	if recorder.Code != http.StatusBadRequest{
+		t.Errorf("Error in TestNumberPartialLookupEmpty:\n expected %d\n got %d", http.StatusBadRequest, recorder.Code)
+	}
+}
+
+func TestNumberExtract(t *testing.T) {
+
+	//Arrange
+	recorder := httptest.NewRecorder()
+	teardown := setup(t,recorder)
+	defer teardown()
+
+	text := "call me on +389 70-123-456 after 5"
+	extracted := dto.ExtractionResponse{Numbers: []dto.ExtractedNumber{{
+		Text: "+389 70-123-456",
+		Start: 11,
+		End: 26,
+		Number: "38970123456",
+		Result: &dto.NumberLookupResponse{MNO: "Telekom", CC: "389", SN: "123456", CI: "mk"},
+	}}}
+	mockLookupService.EXPECT().ExtractMSISDNs(text, "mk").Return(&extracted, nil)
+	jsonVal, _ := json.Marshal(ApiExtractRequest{Text: text, Region: "mk"})
+
+	//Act
+	req := httptest.NewRequest(http.MethodPost,"/extract",bytes.NewBuffer(jsonVal))
+	req.Header.Set("Content-Type","application/json")
+	router.ServeHTTP(recorder,req)
+
+	//Assert
+	var resp dto.ExtractionResponse
+	json.Unmarshal(recorder.Body.Bytes(), &resp)
+	if recorder.Code != http.StatusOK || len(resp.Numbers) != 1 || resp.Numbers[0].Start != 11 || resp.Numbers[0].Result == nil{
+		t.Errorf("Error in TestNumberExtract:\n expected %d with the number\n got %d %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestNumberExtractUnknownRegion(t *testing.T) {
+
+	//Arrange
+	recorder := httptest.NewRecorder()
+	teardown := setup(t,recorder)
+	defer teardown()
+
+	mockLookupService.EXPECT().ExtractMSISDNs(gomock.Any(), "zz").Return(nil, errs.NewCountryNotFoundError())
+	jsonVal, _ := json.Marshal(ApiExtractRequest{Text: "call 070 123 456", Region: "zz"})
+
+	//Act
+	req := httptest.NewRequest(http.MethodPost,"/extract",bytes.NewBuffer(jsonVal))
+	req.Header.Set("Content-Type","application/json")
+	router.ServeHTTP(recorder,req)
+
+	//Assert
+	if recorder.Code != http.StatusBadRequest{
+		t.Errorf("Error in TestNumberExtractUnknownRegion:\n expected %d\n got %d", http.StatusBadRequest, recorder.Code)
+	}
+}
+
+func TestNumberLookupBatch(t *testing.T) {
+
+	//Arrange
+	recorder := httptest.NewRecorder()
+	teardown := setup(t,recorder)
+	defer teardown()
+
+	found := dto.NumberLookupResponse{MNO: "A1", CC: "389", SN: "123456", CI: "mk"}
+	mockLookupService.EXPECT().LookupMSISDN("38977123456").Return(&found, nil)
+	mockLookupService.EXPECT().LookupMSISDN("123456789").Return(nil, errs.NewNumberNotFoundError())
+
+	jsonVal, _ := json.Marshal(ApiBatchLookupRequest{
+		Numbers: []string{"389 77 123 456", "lorem ipsum", "123456789"},
+	})
+
+	//Act
+	req := httptest.NewRequest(http.MethodPost,"/lookup/batch",bytes.NewBuffer(jsonVal))
+	req.Header.Set("Content-Type","application/json")
+	router.ServeHTTP(recorder,req)
+
+	//Assert
+	if recorder.Code != http.StatusOK{
+		t.Fatalf("Error in TestNumberLookupBatch:\n expected %d\n got %d", http.StatusOK, recorder.Code)
+	}
+	var resp dto.BatchLookupResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &resp); err != nil || len(resp.Results) != 3{
+		t.Fatalf("Error in TestNumberLookupBatch:\n expected %d results\n got %s", 3, recorder.Body.String())
+	}
+	if resp.Results[0].Result == nil || !resp.Results[0].Result.Compare(found){
+		t.Errorf("Error in TestNumberLookupBatch:\n expected a result for %s", resp.Results[0].Number)
+	}
+	if resp.Results[1].Error == "" || resp.Results[2].Error == ""{
+		t.Error("Error in TestNumberLookupBatch:\n expected errors for the invalid and unknown numbers")
+	}
+}
+
+func TestNumberLookupBatchLimits(t *testing.T) {
+
+	tt := []struct{
+		Name string
+		Count int
+	}{
+		{
+			Name:	"Empty batch",
+			Count:	0,
+		},
+		{
+			Name:	"Batch over the limit",
+			Count:	maxBatchSize + 1,
+		},
+	}
+
+	for _, test := range tt{
+		fn := func(t *testing.T){
+
+			//Arrange
+			recorder := httptest.NewRecorder()
+			teardown := setup(t,recorder)
+			defer teardown()
+
+			numbers := make([]string, test.Count)
+			for i := range numbers{
+				numbers[i] = "38977123456"
+			}
+			jsonVal, _ := json.Marshal(ApiBatchLookupRequest{Numbers: numbers})
+
+			//Act
+			req := httptest.NewRequest(http.MethodPost,"/lookup/batch",bytes.NewBuffer(jsonVal))
+			req.Header.Set("Content-Type","application/json")
+			router.ServeHTTP(recorder,req)
+
+			//Assert
+			if recorder.Code != http.StatusBadRequest{
+				t.Errorf("Error in TestNumberLookupBatchLimits:\n expected %d\n got %d", http.StatusBadRequest, recorder.Code)
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}
+
+func TestCountryApi(t *testing.T) {
+
+	tests := []struct{
+		Name string
+		Code string
+		Details *dto.CountryDetails
+		Err error
+		ExpCode int
+	}{
+		{Name: "found", Code: "AUT", Details: &dto.CountryDetails{Name: "Austria", Alpha2: "AT", Alpha3: "AUT", Numeric: "040"}, ExpCode: http.StatusOK},
+		{Name: "unknown", Code: "ZZZ", Err: errs.NewCountryNotFoundError(), ExpCode: http.StatusNotFound},
+	}
+
+	for _, test := range tests{
+		fn := func(t *testing.T){
+
+			//Arrange
+			recorder := httptest.NewRecorder()
+			teardown := setup(t,recorder)
+			defer teardown()
+			mockLookupService.EXPECT().GetCountryDetails(test.Code).Return(test.Details, test.Err)
+
+			//Act
+			req := httptest.NewRequest(http.MethodGet, "/countries/" + test.Code, nil)
+			router.ServeHTTP(recorder,req)
+
+			//Assert
+			var resp dto.CountryDetails
+			json.Unmarshal(recorder.Body.Bytes(), &resp)
+			if recorder.Code != test.ExpCode{
+				t.Errorf("Error in TestCountryApi:\n expected %d\n got %d", test.ExpCode, recorder.Code)
+			}
+			if test.Details != nil && resp.Alpha2 != test.Details.Alpha2{
+				t.Errorf("Error in TestCountryApi:\n expected %s\n got %s", test.Details.Alpha2, recorder.Body.String())
+			}
+		}
+		t.Run(test.Name, fn)
+	}
+}